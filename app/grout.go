@@ -21,8 +21,9 @@ func main() {
 	logger.Debug("Starting Grout")
 
 	currentCFW := cfw.GetCFW()
-	quitOnBack := len(config.Hosts) == 1
-	showCollections := config.ShowCollections(config.Hosts[0])
+	// The platform list is the root screen whichever host is active, servers are switched from Settings
+	quitOnBack := true
+	showCollections := collectionsAvailable(config, *config.CurrentHost())
 
 	fsm := buildFSM(config, currentCFW, platforms, quitOnBack, showCollections)
 
@@ -33,7 +34,8 @@ func main() {
 
 func cleanup() {
 	if autoSync != nil && autoSync.IsRunning() {
-		gaba.GetLogger().Info("Waiting for auto-sync to complete before exiting...")
+		gaba.GetLogger().Info("Cancelling auto-sync before exiting...")
+		autoSync.Stop()
 		gaba.ProcessMessage(
 			i18n.Localize(&goi18n.Message{ID: "auto_sync_waiting", Other: "Waiting for save sync to complete..."}, nil),
 			gaba.ProcessMessageOptions{},
@@ -2,9 +2,12 @@ package main
 
 import (
 	"errors"
+	"grout/cache"
 	"grout/cfw"
 	"grout/cfw/muos"
+	"grout/connectivity"
 	"grout/internal"
+	"grout/internal/constants"
 	"grout/internal/environment"
 	"grout/internal/fileutil"
 	"grout/resources"
@@ -14,6 +17,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
@@ -116,7 +120,7 @@ func setup() SetupResult {
 			log.Fatalf("Login failed: %v", loginErr)
 		}
 		logger.Debug("Login successful, saving configuration")
-		config.Hosts = loginConfig.Hosts
+		config.AddHost(loginConfig.Hosts[0])
 		internal.SaveConfig(config)
 	}
 
@@ -124,6 +128,19 @@ func setup() SetupResult {
 		gaba.SetRawLogLevel(config.LogLevel)
 	}
 
+	// Tokens are shared by pointer with config.Hosts, which already holds the refreshed pair. Refreshes
+	// happen off the UI goroutine, so only the token is written to disk, not the config the UI is using.
+	romm.OnTokenRefresh(func(hostKey string, token *romm.Token) {
+		if err := internal.SaveHostToken(hostKey, token); err != nil {
+			logger.Error("Failed to persist refreshed token", "error", err)
+		}
+	})
+
+	// The download manager and the updater don't take a client, they trust the active host through the default transport
+	romm.InstallDefaultTransport(*config.CurrentHost())
+
+	migrateLegacyCredentials(config)
+
 	if config.Language != "" && !isFirstLaunch {
 		if err := i18n.SetWithCode(config.Language); err != nil {
 			logger.Error("Failed to set language", "error", err, "language", config.Language)
@@ -155,7 +172,7 @@ func setup() SetupResult {
 	if len(config.DirectoryMappings) == 0 {
 		screen := ui.NewPlatformMappingScreen()
 		result, err := screen.Draw(ui.PlatformMappingInput{
-			Host:           config.Hosts[0],
+			Host:           *config.CurrentHost(),
 			ApiTimeout:     config.ApiTimeout,
 			CFW:            currentCFW,
 			RomDirectory:   cfw.GetRomDirectory(),
@@ -173,6 +190,7 @@ func setup() SetupResult {
 
 	var platforms []romm.Platform
 	var loadErr error
+	offline := false
 
 	splashBytes, _ := resources.GetSplashImageBytes()
 
@@ -182,8 +200,10 @@ func setup() SetupResult {
 			ImageWidth:  768,
 			ImageHeight: 540,
 		}, func() (interface{}, error) {
+			refreshServerVersion(config)
+
 			var err error
-			platforms, err = internal.GetMappedPlatforms(config.Hosts[0], config.DirectoryMappings, config.ApiTimeout)
+			platforms, err = internal.GetMappedPlatforms(*config.CurrentHost(), config.DirectoryMappings, config.ApiTimeout)
 			if err != nil {
 				loadErr = err
 				return nil, err
@@ -198,6 +218,34 @@ func setup() SetupResult {
 		}
 
 		logger.Error("Failed to load platforms", "error", loadErr)
+
+		// With RomM out of reach, what was cached last time is enough to browse
+		if romm.IsUnreachable(loadErr) {
+			if err := cache.InitCacheManager(*config.CurrentHost(), config); err != nil {
+				logger.Error("Failed to initialize cache manager", "error", err)
+			}
+			if cached := cachedPlatforms(config); len(cached) > 0 {
+				logger.Info("RomM unreachable, starting offline", "platforms", len(cached))
+				platforms = cached
+				offline = true
+				break
+			}
+		}
+
+		// An expired session can't be recovered without the password, and a changed certificate has to be
+		// reviewed at login, so send the user back there
+		_, certErr := romm.AsCertificateError(loadErr)
+		if errors.Is(loadErr, romm.ErrUnauthorized) || certErr {
+			loginConfig, loginErr := ui.LoginFlow(*config.CurrentHost())
+			if loginErr == nil {
+				config.AddHost(loginConfig.Hosts[0])
+				internal.SaveConfig(config)
+				romm.InstallDefaultTransport(*config.CurrentHost())
+				continue
+			}
+			logger.Error("Re-login failed", "error", loginErr)
+		}
+
 		errorMessage := classifyStartupError(loadErr)
 		errorMsg := i18n.Localize(errorMessage, nil)
 
@@ -210,12 +258,91 @@ func setup() SetupResult {
 		logger.Info("User chose to retry connection")
 	}
 
+	hostMonitor = connectivity.NewMonitor(config.ApiTimeout)
+	ui.AddStatusBarIcon(hostMonitor.Icon())
+	if offline {
+		hostMonitor.GoOffline(*config.CurrentHost())
+		ui.ShowOfflineNotice()
+	}
+
 	return SetupResult{
 		Config:    config,
 		Platforms: platforms,
 	}
 }
 
+// migrateLegacyCredentials exchanges passwords stored by older versions for a token pair
+// and drops the password from the config. Hosts that can't be reached keep their password
+// and are retried on the next launch.
+func migrateLegacyCredentials(config *internal.Config) {
+	logger := gaba.GetLogger()
+	migrated := false
+
+	for i := range config.Hosts {
+		host := &config.Hosts[i]
+		if host.Password == "" || host.Token.HasRefreshToken() {
+			continue
+		}
+
+		token, err := romm.NewClientFromHost(*host, constants.LoginTimeout).Login(host.Username, host.Password)
+		if err != nil {
+			logger.Warn("Unable to exchange stored password for a token", "host", host.RootURI, "error", err)
+			continue
+		}
+
+		host.Token = token
+		host.Password = ""
+		migrated = true
+	}
+
+	if migrated {
+		logger.Info("Replaced stored passwords with tokens")
+		internal.SaveConfig(config)
+	}
+}
+
+// cachedPlatforms returns the mapped platforms of the active host's games cache, in the user's
+// order, or nothing when the cache holds no games to browse.
+func cachedPlatforms(config *internal.Config) []romm.Platform {
+	cm := cache.GetCacheManager()
+	if !cm.HasCache() {
+		return nil
+	}
+
+	platforms, err := cm.GetPlatforms()
+	if err != nil {
+		gaba.GetLogger().Error("Failed to load cached platforms", "error", err)
+		return nil
+	}
+
+	platforms = slices.DeleteFunc(platforms, func(p romm.Platform) bool {
+		_, mapped := config.DirectoryMappings[p.FSSlug]
+		return !mapped
+	})
+	return internal.SortPlatformsByOrder(platforms, config.PlatformOrder)
+}
+
+// refreshServerVersion re-reads the RomM version so capabilities follow server upgrades.
+func refreshServerVersion(config *internal.Config) {
+	logger := gaba.GetLogger()
+	host := config.CurrentHost()
+
+	heartbeat, err := romm.NewClientFromHost(*host, config.ApiTimeout).GetHeartbeat()
+	if err != nil {
+		logger.Debug("Unable to read RomM version", "error", err)
+		return
+	}
+
+	version := heartbeat.Version()
+	if version == "" || version == host.ServerVersion {
+		return
+	}
+
+	logger.Info("RomM server version changed", "from", host.ServerVersion, "to", version)
+	host.ServerVersion = version
+	internal.SaveConfig(config)
+}
+
 func classifyStartupError(err error) *goi18n.Message {
 	if err == nil {
 		return nil
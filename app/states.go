@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"grout/cache"
 	"grout/cfw"
+	"grout/connectivity"
 	"grout/internal"
 	"grout/internal/constants"
 	"grout/romm"
@@ -10,6 +13,7 @@ import (
 	"grout/ui"
 	"grout/update"
 	"os"
+	"slices"
 	gosync "sync"
 	"sync/atomic"
 
@@ -24,6 +28,11 @@ var (
 	autoSyncOnce   gosync.Once
 	autoUpdate     *update.AutoUpdate
 	autoUpdateOnce gosync.Once
+	hostMonitor    *connectivity.Monitor
+
+	// queuedDownloadsOffered is set once the queued downloads were offered, so they aren't
+	// offered again on every screen until RomM comes back from being offline another time
+	queuedDownloadsOffered bool
 )
 
 const (
@@ -31,13 +40,19 @@ const (
 	gameList                    gaba.StateName = "game_list"
 	gameDetails                 gaba.StateName = "game_details"
 	gameOptions                 gaba.StateName = "game_options"
+	collectionMembership        gaba.StateName = "collection_membership"
 	collectionList              gaba.StateName = "collection_list"
 	collectionPlatformSelection gaba.StateName = "collection_platform_selection"
 	search                      gaba.StateName = "search"
+	listOptions                 gaba.StateName = "list_options"
+	searchAll                   gaba.StateName = "search_all"
 	collectionSearch            gaba.StateName = "collection_search"
 	settings                    gaba.StateName = "settings"
 	generalSettings             gaba.StateName = "general_settings"
+	regionPriority              gaba.StateName = "region_priority"
 	collectionsSettings         gaba.StateName = "collections_settings"
+	servers                     gaba.StateName = "servers"
+	manageCollections           gaba.StateName = "manage_collections"
 	advancedSettings            gaba.StateName = "advanced_settings"
 	settingsPlatformMapping     gaba.StateName = "platform_mapping"
 	saveSyncSettings            gaba.StateName = "save_sync_settings"
@@ -47,6 +62,7 @@ const (
 	saveSync                    gaba.StateName = "save_sync"
 	biosDownload                gaba.StateName = "bios_download"
 	artworkSync                 gaba.StateName = "artwork_sync"
+	unknownRoms                 gaba.StateName = "unknown_roms"
 	updateCheck                 gaba.StateName = "update_check"
 )
 
@@ -59,9 +75,14 @@ type NavState struct {
 	CurrentGames []romm.Rom
 	FullGames    []romm.Rom
 	SearchFilter string
+	SearchAll    string
 	HasBIOS      bool
 	GameListPos  ListPosition
 
+	// Filters and Sorts hold the filters and order of every game list visited, see listKey
+	Filters map[string]cache.Filters
+	Sorts   map[string]cache.Sort
+
 	CollectionSearchFilter string
 	CollectionGames        []romm.Rom
 	CollectionListPos      ListPosition
@@ -81,10 +102,23 @@ func (s *NavState) ResetGameList() {
 	s.CurrentGames = nil
 	s.FullGames = nil
 	s.SearchFilter = ""
+	s.SearchAll = ""
 	s.HasBIOS = false
 	s.GameListPos = ListPosition{}
 }
 
+// listKey names the game list being browsed, so each platform, collection and search across
+// all platforms keeps its own filters and order.
+func (s *NavState) listKey(platform romm.Platform, collection romm.Collection) string {
+	switch {
+	case s.SearchAll != "":
+		return "search_all"
+	case collection.ID != 0 || collection.VirtualID != "":
+		return cache.GetCollectionCacheKey(collection) + "_" + cache.GetPlatformCacheKey(platform.ID)
+	}
+	return cache.GetPlatformCacheKey(platform.ID)
+}
+
 func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, quitOnBack bool, showCollections bool) *gaba.FSM {
 	fsm := gaba.NewFSM()
 
@@ -95,13 +129,14 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 
 	gaba.Set(fsm.Context(), config)
 	gaba.Set(fsm.Context(), c)
-	gaba.Set(fsm.Context(), config.Hosts[0])
+	gaba.Set(fsm.Context(), *config.CurrentHost())
 	gaba.Set(fsm.Context(), platforms)
 	gaba.Set(fsm.Context(), nav)
 
 	// Initialize cache manager
-	if err := cache.InitCacheManager(config.Hosts[0], config); err != nil {
+	if err := cache.InitCacheManager(*config.CurrentHost(), config); err != nil {
 		gaba.GetLogger().Error("Failed to initialize cache manager", "error", err)
+		showCacheError(err)
 	}
 
 	// Check if this is a first run and populate cache if needed
@@ -109,14 +144,14 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		gaba.GetLogger().Info("First run detected, populating cache")
 		progress := uatomic.NewFloat64(0)
 		gaba.ProcessMessage(
-			i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil),
+			cacheBuildingMessage(cm),
 			gaba.ProcessMessageOptions{
 				ShowThemeBackground: true,
 				ShowProgressBar:     true,
 				Progress:            progress,
 			},
 			func() (interface{}, error) {
-				return nil, cm.PopulateFullCacheWithProgress(platforms, progress)
+				return nil, cm.PopulateFullCacheWithProgress(context.Background(), platforms, progress)
 			},
 		)
 	}
@@ -124,18 +159,30 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 	// Validate artwork cache in background
 	cache.RunArtworkValidation()
 
+	// Parse cached game tags again in background if the tag parser changed
+	cache.RunTagRefresh()
+
 	gaba.AddState(fsm, platformSelection, func(ctx *gaba.Context) (ui.PlatformSelectionOutput, gaba.ExitCode) {
+		resumeOnline(ctx)
+
 		platforms, _ := gaba.Get[[]romm.Platform](ctx)
 		nav, _ := gaba.Get[*NavState](ctx)
 
 		screen := ui.NewPlatformSelectionScreen()
 		config, _ := gaba.Get[*internal.Config](ctx)
 		currentCFW, _ := gaba.Get[cfw.CFW](ctx)
+		host, _ := gaba.Get[romm.Host](ctx)
+
+		// Servers older than the save sync endpoints get no sync button or auto-sync, and neither
+		// does a server that can't be reached
+		saveSyncMode := config.SaveSyncMode
+		if sync.CheckServerSupport(host, config) != nil || connectivity.IsOffline() {
+			saveSyncMode = "off"
+		}
 
 		// Start auto-sync on first platform menu view
-		if config.SaveSyncMode == "automatic" {
+		if saveSyncMode == "automatic" {
 			autoSyncOnce.Do(func() {
-				host, _ := gaba.Get[romm.Host](ctx)
 				autoSync = sync.NewAutoSync(host, config)
 				ui.AddStatusBarIcon(autoSync.Icon())
 				autoSync.Start()
@@ -156,7 +203,7 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		// - "manual": always true, shows "Sync" button
 		// - "automatic": controlled by auto-sync
 		var showSaveSync *atomic.Bool
-		switch config.SaveSyncMode {
+		switch saveSyncMode {
 		case "manual":
 			showSaveSync = &atomic.Bool{}
 			showSaveSync.Store(true)
@@ -170,6 +217,7 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 			Platforms:            platforms,
 			QuitOnBack:           nav.QuitOnBack,
 			ShowCollections:      nav.ShowCollections,
+			ShowSearch:           cache.GetCacheManager().HasCache(),
 			ShowSaveSync:         showSaveSync,
 			LastSelectedIndex:    nav.PlatformListPos.Index,
 			LastSelectedPosition: nav.PlatformListPos.VisibleStartIndex,
@@ -216,10 +264,45 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 			nav.CollectionListPos = ListPosition{}
 			return nil
 		}).
+		On(constants.ExitCodeSearchAll, searchAll).
 		On(gaba.ExitCodeAction, settings).
 		On(constants.ExitCodeSaveSync, saveSync).
 		Exit(gaba.ExitCodeQuit)
 
+	gaba.AddState(fsm, searchAll, func(ctx *gaba.Context) (ui.SearchAllOutput, gaba.ExitCode) {
+		config, _ := gaba.Get[*internal.Config](ctx)
+		nav, _ := gaba.Get[*NavState](ctx)
+
+		screen := ui.NewSearchAllScreen()
+		result, err := screen.Draw(ui.SearchAllInput{
+			Config:      config,
+			InitialText: nav.SearchAll,
+		})
+
+		if err != nil {
+			return ui.SearchAllOutput{}, gaba.ExitCodeError
+		}
+
+		return result.Value, result.ExitCode
+	}).
+		OnWithHook(gaba.ExitCodeSuccess, gameList, func(ctx *gaba.Context) error {
+			output, _ := gaba.Get[ui.SearchAllOutput](ctx)
+			nav, _ := gaba.Get[*NavState](ctx)
+			nav.ResetGameList()
+			nav.SearchAll = output.Query
+			nav.FullGames = output.Games
+			nav.CurrentGames = output.Games
+			gaba.Set(ctx, ui.PlatformSelectionOutput{})
+			gaba.Set(ctx, ui.CollectionSelectionOutput{})
+			gaba.Set(ctx, ui.CollectionPlatformSelectionOutput{})
+			return nil
+		}).
+		OnWithHook(gaba.ExitCodeBack, platformSelection, func(ctx *gaba.Context) error {
+			nav, _ := gaba.Get[*NavState](ctx)
+			nav.ResetGameList()
+			return nil
+		})
+
 	gaba.AddState(fsm, collectionList, func(ctx *gaba.Context) (ui.CollectionSelectionOutput, gaba.ExitCode) {
 		config, _ := gaba.Get[*internal.Config](ctx)
 		host, _ := gaba.Get[romm.Host](ctx)
@@ -317,6 +400,8 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		On(gaba.ExitCodeBack, collectionList)
 
 	gaba.AddState(fsm, gameList, func(ctx *gaba.Context) (ui.GameListOutput, gaba.ExitCode) {
+		resumeOnline(ctx)
+
 		config, _ := gaba.Get[*internal.Config](ctx)
 		host, _ := gaba.Get[romm.Host](ctx)
 		platform, _ := gaba.Get[ui.PlatformSelectionOutput](ctx)
@@ -344,6 +429,9 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 			Games:                nav.CurrentGames,
 			HasBIOS:              nav.HasBIOS,
 			SearchFilter:         nav.SearchFilter,
+			SearchAll:            nav.SearchAll,
+			Filters:              nav.Filters[nav.listKey(selectedPlatform, selectedCollection)],
+			Sort:                 nav.Sorts[nav.listKey(selectedPlatform, selectedCollection)],
 			LastSelectedIndex:    nav.GameListPos.Index,
 			LastSelectedPosition: nav.GameListPos.VisibleStartIndex,
 		})
@@ -351,6 +439,9 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		if err != nil {
 			return ui.GameListOutput{}, gaba.ExitCodeError
 		}
+		if result.Value.Unreachable {
+			goOffline(host)
+		}
 
 		nav.FullGames = result.Value.AllGames
 		nav.CurrentGames = result.Value.AllGames
@@ -362,8 +453,9 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		return result.Value, result.ExitCode
 	}).
 		On(gaba.ExitCodeSuccess, gameDetails).
-		On(constants.ExitCodeSearch, search).
+		On(constants.ExitCodeListOptions, listOptions).
 		On(constants.ExitCodeBIOS, biosDownload).
+		On(constants.ExitCodeCollectionMembership, collectionMembership).
 		OnWithHook(constants.ExitCodeClearSearch, gameList, func(ctx *gaba.Context) error {
 			nav, _ := gaba.Get[*NavState](ctx)
 			nav.SearchFilter = ""
@@ -374,6 +466,7 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		OnWithHook(gaba.ExitCodeBack, platformSelection, func(ctx *gaba.Context) error {
 			nav, _ := gaba.Get[*NavState](ctx)
 			nav.CurrentGames = nil
+			nav.SearchAll = ""
 			return nil
 		}).
 		On(constants.ExitCodeBackToCollectionPlatform, collectionPlatformSelection).
@@ -384,6 +477,57 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		}).
 		On(constants.ExitCodeNoResults, search)
 
+	gaba.AddState(fsm, listOptions, func(ctx *gaba.Context) (ui.ListOptionsOutput, gaba.ExitCode) {
+		config, _ := gaba.Get[*internal.Config](ctx)
+		gameListOutput, _ := gaba.Get[ui.GameListOutput](ctx)
+		nav, _ := gaba.Get[*NavState](ctx)
+
+		key := nav.listKey(gameListOutput.Platform, gameListOutput.Collection)
+
+		screen := ui.NewListOptionsScreen()
+		result, err := screen.Draw(ui.ListOptionsInput{
+			Config:        config,
+			GameIDs:       gameListOutput.FacetGameIDs,
+			Filters:       nav.Filters[key],
+			Sort:          nav.Sorts[key],
+			SearchResults: nav.SearchAll != "",
+		})
+
+		if err != nil {
+			return ui.ListOptionsOutput{}, gaba.ExitCodeError
+		}
+
+		return result.Value, result.ExitCode
+	}).
+		OnWithHook(gaba.ExitCodeSuccess, gameList, func(ctx *gaba.Context) error {
+			output, _ := gaba.Get[ui.ListOptionsOutput](ctx)
+			gameListOutput, _ := gaba.Get[ui.GameListOutput](ctx)
+			nav, _ := gaba.Get[*NavState](ctx)
+			if nav.Filters == nil {
+				nav.Filters = make(map[string]cache.Filters)
+			}
+			if nav.Sorts == nil {
+				nav.Sorts = make(map[string]cache.Sort)
+			}
+			key := nav.listKey(gameListOutput.Platform, gameListOutput.Collection)
+			nav.Filters[key] = output.Filters
+			nav.Sorts[key] = output.Sort
+			nav.GameListPos = ListPosition{}
+			return nil
+		}).
+		OnWithHook(gaba.ExitCodeBack, gameList, func(ctx *gaba.Context) error {
+			gameListOutput, _ := gaba.Get[ui.GameListOutput](ctx)
+			nav, _ := gaba.Get[*NavState](ctx)
+			// Leaving the filters that emptied the list as they were would only empty it again
+			if gameListOutput.NoFilterMatches {
+				delete(nav.Filters, nav.listKey(gameListOutput.Platform, gameListOutput.Collection))
+				nav.GameListPos = ListPosition{}
+			}
+			return nil
+		}).
+		On(constants.ExitCodeSearch, search).
+		On(constants.ExitCodeSearchAll, searchAll)
+
 	gaba.AddState(fsm, gameDetails, func(ctx *gaba.Context) (ui.GameDetailsOutput, gaba.ExitCode) {
 		config, _ := gaba.Get[*internal.Config](ctx)
 		host, _ := gaba.Get[romm.Host](ctx)
@@ -392,10 +536,18 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 
 		// If multiple games selected, skip details and go straight to download
 		if len(gameListOutput.SelectedGames) != 1 {
+			if connectivity.IsOffline() {
+				queueDownloads(gameListOutput.SelectedGames)
+				return ui.GameDetailsOutput{}, gaba.ExitCodeBack
+			}
+
 			downloadScreen := ui.NewDownloadScreen()
 			downloadOutput := downloadScreen.Execute(*config, host, gameListOutput.Platform, gameListOutput.SelectedGames, gameListOutput.AllGames, nav.SearchFilter)
 			nav.CurrentGames = downloadOutput.AllGames
 			nav.SearchFilter = downloadOutput.SearchFilter
+			if downloadOutput.Unreachable {
+				goOffline(host)
+			}
 			triggerAutoSync()
 			return ui.GameDetailsOutput{}, gaba.ExitCodeBack
 		}
@@ -406,6 +558,7 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 			Host:     host,
 			Platform: gameListOutput.Platform,
 			Game:     gameListOutput.SelectedGames[0],
+			Games:    gameListOutput.AllGames,
 		})
 
 		if err != nil {
@@ -421,11 +574,16 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 			gameListOutput, _ := gaba.Get[ui.GameListOutput](ctx)
 			nav, _ := gaba.Get[*NavState](ctx)
 
-			if detailsOutput.DownloadRequested {
+			if detailsOutput.DownloadRequested && connectivity.IsOffline() {
+				queueDownloads([]romm.Rom{detailsOutput.Game})
+			} else if detailsOutput.DownloadRequested {
 				downloadScreen := ui.NewDownloadScreen()
 				downloadOutput := downloadScreen.Execute(*config, host, detailsOutput.Platform, []romm.Rom{detailsOutput.Game}, gameListOutput.AllGames, nav.SearchFilter)
 				nav.CurrentGames = downloadOutput.AllGames
 				nav.SearchFilter = downloadOutput.SearchFilter
+				if downloadOutput.Unreachable {
+					goOffline(host)
+				}
 				triggerAutoSync()
 			}
 
@@ -437,6 +595,7 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 	// Game options state
 	gaba.AddState(fsm, gameOptions, func(ctx *gaba.Context) (ui.GameOptionsOutput, gaba.ExitCode) {
 		config, _ := gaba.Get[*internal.Config](ctx)
+		host, _ := gaba.Get[romm.Host](ctx)
 		gameListOutput, _ := gaba.Get[ui.GameListOutput](ctx)
 
 		if len(gameListOutput.SelectedGames) != 1 {
@@ -446,6 +605,7 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		screen := ui.NewGameOptionsScreen()
 		result, err := screen.Draw(ui.GameOptionsInput{
 			Config: config,
+			Host:   host,
 			Game:   gameListOutput.SelectedGames[0],
 		})
 
@@ -457,11 +617,28 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 	}).
 		OnWithHook(gaba.ExitCodeSuccess, gameDetails, func(ctx *gaba.Context) error {
 			output, _ := gaba.Get[ui.GameOptionsOutput](ctx)
+			gameListOutput, _ := gaba.Get[ui.GameListOutput](ctx)
+			nav, _ := gaba.Get[*NavState](ctx)
 			gaba.Set(ctx, output.Config)
+			pruneCollectionGames(nav, gameListOutput.Collection, output.UpdatedCollections)
 			return nil
 		}).
 		On(gaba.ExitCodeBack, gameDetails)
 
+	gaba.AddState(fsm, collectionMembership, func(ctx *gaba.Context) (ui.CollectionMembershipOutput, gaba.ExitCode) {
+		config, _ := gaba.Get[*internal.Config](ctx)
+		host, _ := gaba.Get[romm.Host](ctx)
+		gameListOutput, _ := gaba.Get[ui.GameListOutput](ctx)
+		nav, _ := gaba.Get[*NavState](ctx)
+
+		screen := ui.NewCollectionMembershipScreen()
+		output := screen.Execute(*config, host, gameListOutput.SelectedGames)
+		pruneCollectionGames(nav, gameListOutput.Collection, output.Updated)
+
+		return output, gaba.ExitCodeBack
+	}).
+		On(gaba.ExitCodeBack, gameList)
+
 	gaba.AddState(fsm, search, func(ctx *gaba.Context) (ui.SearchOutput, gaba.ExitCode) {
 		nav, _ := gaba.Get[*NavState](ctx)
 
@@ -550,10 +727,11 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 			gaba.Set(ctx, output.Config)
 			nav.SettingsPos = ListPosition{}
 
-			nav.ShowCollections = output.Config.ShowCollections(host)
+			nav.ShowCollections = collectionsAvailable(output.Config, host)
 			return nil
 		}).
 		On(constants.ExitCodeGeneralSettings, generalSettings).
+		On(constants.ExitCodeServers, servers).
 		On(constants.ExitCodeCollectionsSettings, collectionsSettings).
 		On(constants.ExitCodeEditMappings, settingsPlatformMapping).
 		On(constants.ExitCodeAdvancedSettings, advancedSettings).
@@ -585,8 +763,30 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 			gaba.Set(ctx, output.Config)
 			return nil
 		}).
+		OnWithHook(constants.ExitCodeRegionPriority, regionPriority, func(ctx *gaba.Context) error {
+			output, _ := gaba.Get[ui.GeneralSettingsOutput](ctx)
+			gaba.Set(ctx, output.Config)
+			return nil
+		}).
 		On(gaba.ExitCodeBack, settings)
 
+	gaba.AddState(fsm, regionPriority, func(ctx *gaba.Context) (ui.RegionPriorityOutput, gaba.ExitCode) {
+		config, _ := gaba.Get[*internal.Config](ctx)
+
+		screen := ui.NewRegionPriorityScreen()
+		result, err := screen.Draw(ui.RegionPriorityInput{
+			Config: config,
+		})
+
+		if err != nil {
+			return ui.RegionPriorityOutput{Config: config}, gaba.ExitCodeError
+		}
+
+		return result.Value, result.ExitCode
+	}).
+		On(gaba.ExitCodeSuccess, generalSettings).
+		On(gaba.ExitCodeBack, generalSettings)
+
 	gaba.AddState(fsm, collectionsSettings, func(ctx *gaba.Context) (ui.CollectionsSettingsOutput, gaba.ExitCode) {
 		config, _ := gaba.Get[*internal.Config](ctx)
 
@@ -606,11 +806,23 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 			host, _ := gaba.Get[romm.Host](ctx)
 			nav, _ := gaba.Get[*NavState](ctx)
 			nav.CollectionsSettingsPos = ListPosition{}
-			nav.ShowCollections = config.ShowCollections(host)
+			nav.ShowCollections = collectionsAvailable(config, host)
 			return nil
 		}).
+		On(constants.ExitCodeManageCollections, manageCollections).
 		On(gaba.ExitCodeBack, settings)
 
+	gaba.AddState(fsm, manageCollections, func(ctx *gaba.Context) (ui.ManageCollectionsOutput, gaba.ExitCode) {
+		config, _ := gaba.Get[*internal.Config](ctx)
+		host, _ := gaba.Get[romm.Host](ctx)
+
+		screen := ui.NewManageCollectionsScreen()
+		output := screen.Execute(*config, host)
+
+		return output, gaba.ExitCodeBack
+	}).
+		On(gaba.ExitCodeBack, collectionsSettings)
+
 	gaba.AddState(fsm, saveSyncSettings, func(ctx *gaba.Context) (ui.SaveSyncSettingsOutput, gaba.ExitCode) {
 		config, _ := gaba.Get[*internal.Config](ctx)
 		currentCFW, _ := gaba.Get[cfw.CFW](ctx)
@@ -660,6 +872,7 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		On(gaba.ExitCodeSuccess, settings).
 		On(constants.ExitCodeRefreshCache, refreshCache).
 		On(constants.ExitCodeSyncArtwork, artworkSync).
+		On(constants.ExitCodeUnknownRoms, unknownRoms).
 		On(gaba.ExitCodeBack, settings)
 
 	gaba.AddState(fsm, settingsPlatformMapping, func(ctx *gaba.Context) (ui.PlatformMappingOutput, gaba.ExitCode) {
@@ -735,43 +948,46 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 		OnWithHook(constants.ExitCodeLogout, platformSelection, func(ctx *gaba.Context) error {
 			config, _ := gaba.Get[*internal.Config](ctx)
 			currentCFW, _ := gaba.Get[cfw.CFW](ctx)
+			host := *config.CurrentHost()
 
-			// Delete the entire cache folder on logout
-			if err := cache.DeleteCacheFolder(); err != nil {
+			// Delete the host's cache folder on logout
+			if err := cache.DeleteHostCacheFolder(host); err != nil {
 				gaba.GetLogger().Error("Failed to delete cache folder", "error", err)
 				// Continue with logout even if cache deletion fails
 			}
 
-			config.Hosts = nil
-			config.DirectoryMappings = nil
-			config.PlatformOrder = nil
+			// Other servers carry on with the mappings, only the last logout starts over
+			config.RemoveHost(host.Key())
+			if len(config.Hosts) == 0 {
+				config.DirectoryMappings = nil
+				config.PlatformOrder = nil
+			}
 
 			if err := internal.SaveConfig(config); err != nil {
 				gaba.GetLogger().Error("Failed to save config after logout", "error", err)
 				return err
 			}
 
-			gaba.GetLogger().Info("User logged out successfully")
+			gaba.GetLogger().Info("User logged out successfully", "host", host.URL())
 
-			loginConfig, err := ui.LoginFlow(romm.Host{})
-			if err != nil {
-				gaba.GetLogger().Error("Login flow failed after logout", "error", err)
-				return err
-			}
+			if len(config.Hosts) == 0 {
+				loginConfig, err := ui.LoginFlow(romm.Host{})
+				if err != nil {
+					gaba.GetLogger().Error("Login flow failed after logout", "error", err)
+					return err
+				}
 
-			config.Hosts = loginConfig.Hosts
-			if err := internal.SaveConfig(config); err != nil {
-				gaba.GetLogger().Error("Failed to save config after re-login", "error", err)
-				return err
-			}
+				config.AddHost(loginConfig.Hosts[0])
+				if err := internal.SaveConfig(config); err != nil {
+					gaba.GetLogger().Error("Failed to save config after re-login", "error", err)
+					return err
+				}
 
-			gaba.Set(ctx, config)
-			gaba.Set(ctx, config.Hosts[0])
+				gaba.Set(ctx, config)
 
-			if len(config.DirectoryMappings) == 0 {
 				screen := ui.NewPlatformMappingScreen()
 				result, err := screen.Draw(ui.PlatformMappingInput{
-					Host:           config.Hosts[0],
+					Host:           *config.CurrentHost(),
 					ApiTimeout:     config.ApiTimeout,
 					CFW:            currentCFW,
 					RomDirectory:   cfw.GetRomDirectory(),
@@ -785,40 +1001,63 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 				}
 			}
 
-			// Re-initialize cache manager for the new host
-			if err := cache.InitCacheManager(config.Hosts[0], config); err != nil {
-				gaba.GetLogger().Error("Failed to initialize cache manager after re-login", "error", err)
-			}
-
-			platforms, err := internal.GetMappedPlatforms(config.Hosts[0], config.DirectoryMappings, config.ApiTimeout)
-			if err != nil {
-				gaba.GetLogger().Error("Failed to load platforms after re-login", "error", err)
+			if err := activateHost(ctx, config); err != nil {
+				gaba.GetLogger().Error("Failed to load platforms after logout", "error", err)
 				return err
 			}
-			gaba.Set(ctx, platforms)
 
-			// Populate cache for the new login
-			if cm := cache.GetCacheManager(); cm != nil && cm.IsFirstRun() {
-				progress := uatomic.NewFloat64(0)
-				gaba.ProcessMessage(
-					i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil),
-					gaba.ProcessMessageOptions{
-						ShowThemeBackground: true,
-						ShowProgressBar:     true,
-						Progress:            progress,
-					},
-					func() (interface{}, error) {
-						return nil, cm.PopulateFullCacheWithProgress(platforms, progress)
-					},
-				)
-			}
+			return nil
+		})
+
+	gaba.AddState(fsm, servers, func(ctx *gaba.Context) (ui.ServersOutput, gaba.ExitCode) {
+		config, _ := gaba.Get[*internal.Config](ctx)
+
+		screen := ui.NewServersScreen()
+		result, err := screen.Draw(ui.ServersInput{
+			Config: config,
+		})
+
+		if err != nil {
+			return ui.ServersOutput{}, gaba.ExitCodeError
+		}
 
+		return result.Value, result.ExitCode
+	}).
+		OnWithHook(gaba.ExitCodeSuccess, platformSelection, func(ctx *gaba.Context) error {
+			output, _ := gaba.Get[ui.ServersOutput](ctx)
+			config, _ := gaba.Get[*internal.Config](ctx)
 			nav, _ := gaba.Get[*NavState](ctx)
-			nav.ResetGameList()
-			nav.PlatformListPos = ListPosition{}
+			logger := gaba.GetLogger()
+
+			previous := *config.CurrentHost()
+			config.ActiveHost = output.SelectedHost.Key()
+			internal.SaveConfig(config)
+			nav.SettingsPos = ListPosition{}
+
+			err := activateHost(ctx, config)
+			if err == nil {
+				logger.Info("Switched RomM server", "from", previous.URL(), "to", output.SelectedHost.URL())
+				return nil
+			}
 
+			logger.Error("Failed to switch RomM server", "host", output.SelectedHost.URL(), "error", err)
+			gaba.ConfirmationMessage(
+				i18n.Localize(&goi18n.Message{ID: "servers_switch_failed", Other: "Unable to connect to {{.Name}}!\nStaying on {{.Previous}}."}, map[string]interface{}{
+					"Name":     output.SelectedHost.Label(),
+					"Previous": previous.Label(),
+				}),
+				ui.ContinueFooter(),
+				gaba.MessageOptions{},
+			)
+
+			config.ActiveHost = previous.Key()
+			internal.SaveConfig(config)
+			if err := activateHost(ctx, config); err != nil {
+				logger.Error("Failed to restore previous RomM server", "host", previous.URL(), "error", err)
+			}
 			return nil
-		})
+		}).
+		On(gaba.ExitCodeBack, settings)
 
 	gaba.AddState(fsm, refreshCache, func(ctx *gaba.Context) (ui.RefreshCacheOutput, gaba.ExitCode) {
 		screen := ui.NewRefreshCacheScreen()
@@ -879,19 +1118,29 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 				// Update platforms in context
 				gaba.Set(ctx, platforms)
 
-				// Re-populate cache with progress
+				// Re-populate cache with progress, pressing B aborts the in-flight requests
+				populateCtx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				defer ui.BindCancelButton("cancel-cache-refresh", cancel)()
+
 				progress := uatomic.NewFloat64(0)
-				gaba.ProcessMessage(
-					i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil),
+				_, err = gaba.ProcessMessage(
+					i18n.Localize(&goi18n.Message{ID: "cache_building_cancellable", Other: "Building cache...\nPress B to cancel"}, nil),
 					gaba.ProcessMessageOptions{
 						ShowThemeBackground: true,
 						ShowProgressBar:     true,
 						Progress:            progress,
+						ProcessInput:        true,
 					},
 					func() (interface{}, error) {
-						return nil, cm.PopulateFullCacheWithProgress(platforms, progress)
+						return nil, cm.PopulateFullCacheWithProgress(populateCtx, platforms, progress)
 					},
 				)
+				if errors.Is(err, context.Canceled) {
+					logger.Info("Cache refresh cancelled by user")
+					return nil
+				}
 			}
 
 			logger.Info("Cache refresh completed",
@@ -949,6 +1198,17 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 	}).
 		On(gaba.ExitCodeBack, advancedSettings)
 
+	gaba.AddState(fsm, unknownRoms, func(ctx *gaba.Context) (ui.UnknownRomsOutput, gaba.ExitCode) {
+		config, _ := gaba.Get[*internal.Config](ctx)
+		host, _ := gaba.Get[romm.Host](ctx)
+
+		screen := ui.NewUnknownRomsScreen()
+		output := screen.Execute(*config, host)
+
+		return output, gaba.ExitCodeBack
+	}).
+		On(gaba.ExitCodeBack, advancedSettings)
+
 	gaba.AddState(fsm, updateCheck, func(ctx *gaba.Context) (ui.UpdateOutput, gaba.ExitCode) {
 		currentCFW, _ := gaba.Get[cfw.CFW](ctx)
 
@@ -973,8 +1233,242 @@ func buildFSM(config *internal.Config, c cfw.CFW, platforms []romm.Platform, qui
 	return fsm.Start(platformSelection)
 }
 
-func triggerAutoSync() {
+// activateHost moves the app over to the config's active host: its cache, platforms, save
+// sync and the lists built from them.
+func activateHost(ctx *gaba.Context, config *internal.Config) error {
+	logger := gaba.GetLogger()
+	host := *config.CurrentHost()
+
+	// A sync in flight belongs to the previous host, SetHost lets it unwind before retargeting
 	if autoSync != nil {
+		autoSync.SetHost(host)
+	}
+
+	gaba.Set(ctx, host)
+	romm.InstallDefaultTransport(host)
+
+	if err := cache.SwitchHost(host, config); err != nil {
+		logger.Error("Failed to initialize cache manager for host", "host", host.URL(), "error", err)
+		showCacheError(err)
+	} else {
+		cache.RunTagRefresh()
+	}
+
+	var platforms []romm.Platform
+	_, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "servers_connecting", Other: "Connecting to {{.Name}}..."}, map[string]interface{}{"Name": host.Label()}),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			var err error
+			platforms, err = internal.GetMappedPlatforms(host, config.DirectoryMappings, config.ApiTimeout)
+			return nil, err
+		},
+	)
+
+	// A server that can't be reached is browsed offline from its cache, if it has one
+	if err == nil {
+		platforms = internal.SortPlatformsByOrder(platforms, config.PlatformOrder)
+		hostMonitor.SetOnline()
+	} else if cached := cachedPlatforms(config); romm.IsUnreachable(err) && len(cached) > 0 {
+		logger.Info("RomM unreachable, browsing offline", "host", host.URL(), "error", err)
+		platforms = cached
+		hostMonitor.GoOffline(host)
+		ui.ShowOfflineNotice()
+	} else {
+		return err
+	}
+	gaba.Set(ctx, platforms)
+
+	// Populate the cache the first time this host is used
+	if cm := cache.GetCacheManager(); cm != nil && cm.IsFirstRun() {
+		progress := uatomic.NewFloat64(0)
+		gaba.ProcessMessage(
+			cacheBuildingMessage(cm),
+			gaba.ProcessMessageOptions{
+				ShowThemeBackground: true,
+				ShowProgressBar:     true,
+				Progress:            progress,
+			},
+			func() (interface{}, error) {
+				return nil, cm.PopulateFullCacheWithProgress(context.Background(), platforms, progress)
+			},
+		)
+	}
+
+	nav, _ := gaba.Get[*NavState](ctx)
+	nav.ResetGameList()
+	nav.PlatformListPos = ListPosition{}
+	nav.CollectionListPos = ListPosition{}
+	nav.CollectionSearchFilter = ""
+	nav.CollectionGames = nil
+	nav.ShowCollections = collectionsAvailable(config, host)
+	// Filters and sorts are keyed by platform and collection IDs, which mean other lists on another server
+	nav.Filters = nil
+	nav.Sorts = nil
+	// Each server's cache has its own queue of downloads
+	queuedDownloadsOffered = false
+
+	if sync.CheckServerSupport(host, config) == nil {
+		triggerAutoSync()
+	}
+	return nil
+}
+
+// cacheBuildingMessage tells apart a cache built for the first time from one rebuilt after its
+// database couldn't be migrated.
+func cacheBuildingMessage(cm *cache.Manager) string {
+	if cm.Recovered() {
+		return i18n.Localize(&goi18n.Message{ID: "cache_rebuilding", Other: "Cache could not be upgraded.\nRebuilding cache..."}, nil)
+	}
+	return i18n.Localize(&goi18n.Message{ID: "cache_building", Other: "Building cache..."}, nil)
+}
+
+// showCacheError tells the user why Grout runs without its games cache when they can do
+// something about it.
+func showCacheError(err error) {
+	if errors.Is(err, cache.ErrSchemaTooNew) {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "cache_schema_too_new", Other: "The games cache was made by a newer version of Grout!\nUpdate Grout to use it again."}, nil),
+			ui.ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+	}
+}
+
+func triggerAutoSync() {
+	if autoSync != nil && !connectivity.IsOffline() {
 		autoSync.Trigger()
 	}
 }
+
+// collectionsAvailable reports whether the Collections entry is offered. Offline, only the
+// collections in the cache can be browsed.
+func collectionsAvailable(config *internal.Config, host romm.Host) bool {
+	if connectivity.IsOffline() {
+		showsAny := config.ShowRegularCollections || config.ShowSmartCollections || config.ShowVirtualCollections
+		return showsAny && cache.GetCacheManager().HasCollections()
+	}
+	return config.ShowCollections(host)
+}
+
+// goOffline switches to browsing the cache after a screen lost RomM.
+func goOffline(host romm.Host) {
+	if hostMonitor == nil {
+		return
+	}
+
+	gaba.GetLogger().Info("RomM unreachable, browsing offline", "host", host.URL())
+	hostMonitor.GoOffline(host)
+	ui.ShowOfflineNotice()
+}
+
+// queueDownloads keeps games picked for download while offline until RomM can be reached again.
+func queueDownloads(games []romm.Rom) {
+	ids := make([]int, len(games))
+	for i, game := range games {
+		ids[i] = game.ID
+	}
+
+	if err := cache.GetCacheManager().QueueDownloads(ids); err != nil {
+		gaba.GetLogger().Error("Failed to queue downloads", "error", err)
+		ui.RequireOnline()
+		return
+	}
+
+	gaba.GetLogger().Info("Queued downloads until RomM is back", "count", len(ids))
+	ui.ShowDownloadsQueued(len(ids))
+}
+
+// resumeOnline picks up what offline mode left for later. Once RomM came back, the platforms are
+// reloaded and saves are synced, and whenever RomM can be reached the queued downloads are
+// offered, including those queued before Grout was last closed. Games stay queued until they
+// have downloaded.
+func resumeOnline(ctx *gaba.Context) {
+	if connectivity.IsOffline() {
+		return
+	}
+
+	logger := gaba.GetLogger()
+	config, _ := gaba.Get[*internal.Config](ctx)
+	host, _ := gaba.Get[romm.Host](ctx)
+
+	resumed := hostMonitor != nil && hostMonitor.Resumed()
+	if resumed {
+		nav, _ := gaba.Get[*NavState](ctx)
+
+		platforms, err := internal.GetMappedPlatforms(host, config.DirectoryMappings, config.ApiTimeout)
+		if err != nil {
+			logger.Error("Failed to reload platforms after reconnecting", "error", err)
+		} else {
+			gaba.Set(ctx, internal.SortPlatformsByOrder(platforms, config.PlatformOrder))
+		}
+		nav.ShowCollections = collectionsAvailable(config, host)
+		queuedDownloadsOffered = false
+	}
+
+	cm := cache.GetCacheManager()
+	if ids := cm.QueuedDownloads(); len(ids) > 0 && !queuedDownloadsOffered {
+		games, err := cm.GetGamesByIDs(ids)
+		if err != nil {
+			logger.Error("Failed to load queued downloads", "error", err)
+		} else {
+			queuedDownloadsOffered = true
+
+			// Games that left the cache can't be downloaded anymore
+			done := slices.DeleteFunc(slices.Clone(ids), func(id int) bool {
+				return slices.ContainsFunc(games, func(game romm.Rom) bool { return game.ID == id })
+			})
+
+			if len(games) > 0 && ui.ConfirmQueuedDownloads(len(games)) {
+				logger.Info("Starting queued downloads", "count", len(games))
+				// Queued games can come from any platform, the download screen places each with its own
+				output := ui.NewDownloadScreen().Execute(*config, host, romm.Platform{}, games, nil, "")
+				for _, game := range output.DownloadedGames {
+					done = append(done, game.ID)
+				}
+				if output.Unreachable {
+					goOffline(host)
+					resumed = false
+				} else {
+					resumed = true
+				}
+			}
+
+			if len(done) > 0 {
+				if err := cm.DequeueDownloads(done); err != nil {
+					logger.Error("Failed to dequeue downloads", "error", err)
+				}
+			}
+		}
+	}
+
+	if resumed {
+		triggerAutoSync()
+	}
+}
+
+// pruneCollectionGames drops games from the browsed lists once an edit has taken them out of
+// the collection being viewed, so going back doesn't show them until the next refresh.
+func pruneCollectionGames(nav *NavState, viewed romm.Collection, updated []romm.Collection) {
+	if viewed.ID == 0 || viewed.IsSmart || viewed.IsVirtual {
+		return
+	}
+
+	idx := slices.IndexFunc(updated, func(c romm.Collection) bool { return c.ID == viewed.ID })
+	if idx < 0 {
+		return
+	}
+
+	keep := func(games []romm.Rom) []romm.Rom {
+		return slices.DeleteFunc(slices.Clone(games), func(game romm.Rom) bool {
+			return !slices.Contains(updated[idx].ROMIDs, game.ID)
+		})
+	}
+
+	nav.CurrentGames = keep(nav.CurrentGames)
+	nav.FullGames = keep(nav.FullGames)
+	nav.CollectionGames = keep(nav.CollectionGames)
+	if nav.GameListPos.Index >= len(nav.CurrentGames) {
+		nav.GameListPos = ListPosition{}
+	}
+}
@@ -157,9 +157,9 @@ func DownloadAndCacheArtwork(rom romm.Rom, host romm.Host) error {
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Authorization", host.BasicAuthHeader())
+	req.Header.Set("Authorization", romm.NewClientFromHost(host).AuthorizationHeader())
 
-	client := &http.Client{Timeout: romm.DefaultClientTimeout}
+	client := host.HTTPClient(romm.DefaultClientTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to download artwork: %w", err)
@@ -172,3 +172,109 @@ func (cm *Manager) SaveCollections(collections []romm.Collection) error {
 	logger.Debug("Saved collections to cache", "count", len(collections))
 	return nil
 }
+
+// SaveCollection writes a single regular collection and its game mappings, keeping the
+// cache in step with an edit made on the server without a full collections refresh.
+func (cm *Manager) SaveCollection(collection romm.Collection) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	key := GetCollectionCacheKey(collection)
+
+	dataJSON, err := json.Marshal(collection)
+	if err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+	defer tx.Rollback()
+
+	// Upsert rather than INSERT OR REPLACE, a replaced row gets a new id and orphans its mappings
+	_, err = tx.Exec(`
+		INSERT INTO collections
+		(romm_id, virtual_id, type, name, rom_count, data_json, updated_at, cached_at)
+		VALUES (?, NULL, 'regular', ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(romm_id, type) DO UPDATE SET
+			name = excluded.name,
+			rom_count = excluded.rom_count,
+			data_json = excluded.data_json,
+			updated_at = excluded.updated_at,
+			cached_at = CURRENT_TIMESTAMP
+	`, collection.ID, collection.Name, len(collection.ROMIDs), string(dataJSON), collection.UpdatedAt)
+	if err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+
+	var collectionID int64
+	err = tx.QueryRow(`SELECT id FROM collections WHERE romm_id = ? AND type = 'regular'`, collection.ID).Scan(&collectionID)
+	if err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM game_collections WHERE collection_id = ?`, collectionID); err != nil {
+		return newCacheError("save", "collection_mappings", key, err)
+	}
+
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO game_collections (game_id, collection_id) VALUES (?, ?)`)
+	if err != nil {
+		return newCacheError("save", "collection_mappings", key, err)
+	}
+	defer stmt.Close()
+
+	for _, romID := range collection.ROMIDs {
+		if _, err := stmt.Exec(romID, collectionID); err != nil {
+			return newCacheError("save", "collection_mappings", key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCacheError("save", "collections", key, err)
+	}
+
+	gaba.GetLogger().Debug("Saved collection to cache", "collection", collection.Name, "games", len(collection.ROMIDs))
+	return nil
+}
+
+// DeleteCollection removes a regular collection and its game mappings from the cache.
+func (cm *Manager) DeleteCollection(collection romm.Collection) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	key := GetCollectionCacheKey(collection)
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return newCacheError("delete", "collections", key, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM game_collections WHERE collection_id IN
+		(SELECT id FROM collections WHERE romm_id = ? AND type = 'regular')
+	`, collection.ID)
+	if err != nil {
+		return newCacheError("delete", "collection_mappings", key, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM collections WHERE romm_id = ? AND type = 'regular'`, collection.ID); err != nil {
+		return newCacheError("delete", "collections", key, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newCacheError("delete", "collections", key, err)
+	}
+
+	gaba.GetLogger().Debug("Deleted collection from cache", "collection", collection.Name)
+	return nil
+}
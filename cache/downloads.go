@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// MetaKeyQueuedDownloads holds the IDs of the games picked for download while RomM couldn't be reached.
+const MetaKeyQueuedDownloads = "queued_downloads"
+
+// QueueDownloads adds games to the downloads waiting for RomM. Games already queued stay in place.
+func (cm *Manager) QueueDownloads(gameIDs []int) error {
+	queued := cm.QueuedDownloads()
+	for _, id := range gameIDs {
+		if !slices.Contains(queued, id) {
+			queued = append(queued, id)
+		}
+	}
+
+	data, err := json.Marshal(queued)
+	if err != nil {
+		return newCacheError("save", "queued_downloads", "", err)
+	}
+	return cm.SetMetadata(MetaKeyQueuedDownloads, string(data))
+}
+
+// QueuedDownloads returns the IDs of the games waiting for RomM, in the order they were queued.
+func (cm *Manager) QueuedDownloads() []int {
+	value, err := cm.GetMetadata(MetaKeyQueuedDownloads)
+	if err != nil {
+		return nil
+	}
+
+	var ids []int
+	if err := json.Unmarshal([]byte(value), &ids); err != nil {
+		return nil
+	}
+	return ids
+}
+
+// DequeueDownloads takes games off the downloads waiting for RomM once they no longer need to be
+// downloaded. Games it doesn't name stay queued.
+func (cm *Manager) DequeueDownloads(gameIDs []int) error {
+	if cm == nil || !cm.initialized {
+		return ErrNotInitialized
+	}
+
+	queued := slices.DeleteFunc(cm.QueuedDownloads(), func(id int) bool {
+		return slices.Contains(gameIDs, id)
+	})
+	if len(queued) > 0 {
+		data, err := json.Marshal(queued)
+		if err != nil {
+			return newCacheError("save", "queued_downloads", "", err)
+		}
+		return cm.SetMetadata(MetaKeyQueuedDownloads, string(data))
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if _, err := cm.db.Exec(`DELETE FROM cache_metadata WHERE key = ?`, MetaKeyQueuedDownloads); err != nil {
+		return newCacheError("delete_metadata", MetaKeyQueuedDownloads, "", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDequeueDownloads(t *testing.T) {
+	cm := newTestManager(t)
+
+	if err := cm.QueueDownloads([]int{3, 1, 2}); err != nil {
+		t.Fatalf("QueueDownloads() error = %v", err)
+	}
+	if err := cm.QueueDownloads([]int{1, 4}); err != nil {
+		t.Fatalf("QueueDownloads() error = %v", err)
+	}
+	if got, want := cm.QueuedDownloads(), []int{3, 1, 2, 4}; !slices.Equal(got, want) {
+		t.Fatalf("QueuedDownloads() = %v, want %v", got, want)
+	}
+
+	// Games that didn't download stay queued, in their order
+	if err := cm.DequeueDownloads([]int{1, 4}); err != nil {
+		t.Fatalf("DequeueDownloads() error = %v", err)
+	}
+	if got, want := cm.QueuedDownloads(), []int{3, 2}; !slices.Equal(got, want) {
+		t.Errorf("QueuedDownloads() after dequeue = %v, want %v", got, want)
+	}
+
+	if err := cm.DequeueDownloads([]int{2, 3, 99}); err != nil {
+		t.Fatalf("DequeueDownloads() error = %v", err)
+	}
+	if got := cm.QueuedDownloads(); len(got) != 0 {
+		t.Errorf("QueuedDownloads() after dequeuing everything = %v, want none", got)
+	}
+	if _, err := cm.GetMetadata(MetaKeyQueuedDownloads); err == nil {
+		t.Error("queued downloads are still stored after the queue emptied")
+	}
+}
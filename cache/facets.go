@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Facet is a property game lists can be filtered by.
+type Facet string
+
+const (
+	FacetGenre    Facet = "genre"
+	FacetYear     Facet = "year"
+	FacetRating   Facet = "rating"
+	FacetPlayers  Facet = "players"
+	FacetLanguage Facet = "language"
+)
+
+// Facets lists every facet in the order they are offered.
+var Facets = []Facet{FacetGenre, FacetYear, FacetRating, FacetPlayers, FacetLanguage}
+
+// FacetValue is one value of a facet and how many games of a list have it.
+type FacetValue struct {
+	Value string
+	Count int
+}
+
+// Filters holds the values picked for each facet. A game matches when it has one of the picked
+// values of every facet that has any.
+type Filters map[Facet][]string
+
+// Active reports whether any facet has values picked.
+func (f Filters) Active() bool {
+	for _, values := range f {
+		if len(values) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns how many facets have values picked.
+func (f Filters) Count() int {
+	count := 0
+	for _, values := range f {
+		if len(values) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// With returns a copy of the filters with the facet's values replaced.
+func (f Filters) With(facet Facet, values []string) Filters {
+	filters := maps.Clone(f)
+	if filters == nil {
+		filters = Filters{}
+	}
+	if len(values) == 0 {
+		delete(filters, facet)
+	} else {
+		filters[facet] = values
+	}
+	return filters
+}
+
+// GetFacets counts the values of every facet among the given games. The counts of a facet take
+// the filters of the other facets into account, so they tell how many games picking a value
+// would show, and values already picked are listed even when no game is left with them.
+func (cm *Manager) GetFacets(gameIDs []int, filters Filters) (map[Facet][]FacetValue, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	scope, err := json.Marshal(gameIDs)
+	if err != nil {
+		return nil, newCacheError("get", "facets", "", err)
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	facets := make(map[Facet][]FacetValue, len(Facets))
+	for _, facet := range Facets {
+		where, args := filterClause("f.game_id", filters, facet)
+
+		rows, err := cm.db.Query(`
+			SELECT f.value, COUNT(*) FROM game_facets f
+			WHERE f.facet = ? AND f.game_id IN (SELECT value FROM json_each(?))`+where+`
+			GROUP BY f.value
+		`, append([]any{string(facet), string(scope)}, args...)...)
+		if err != nil {
+			cm.stats.recordError()
+			return nil, newCacheError("get", "facets", string(facet), err)
+		}
+
+		var values []FacetValue
+		for rows.Next() {
+			var value FacetValue
+			if err := rows.Scan(&value.Value, &value.Count); err != nil {
+				rows.Close()
+				return nil, newCacheError("get", "facets", string(facet), err)
+			}
+			values = append(values, value)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, newCacheError("get", "facets", string(facet), err)
+		}
+
+		for _, picked := range filters[facet] {
+			if !slices.ContainsFunc(values, func(v FacetValue) bool { return v.Value == picked }) {
+				values = append(values, FacetValue{Value: picked})
+			}
+		}
+
+		sortFacetValues(facet, values)
+		facets[facet] = values
+	}
+
+	cm.stats.recordHit()
+	return facets, nil
+}
+
+// FilterGameIDs returns the given game IDs that match the filters, in the order given.
+func (cm *Manager) FilterGameIDs(gameIDs []int, filters Filters) ([]int, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	if !filters.Active() {
+		return gameIDs, nil
+	}
+
+	scope, err := json.Marshal(gameIDs)
+	if err != nil {
+		return nil, newCacheError("filter", "games", "", err)
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	where, args := filterClause("s.value", filters, "")
+	rows, err := cm.db.Query(`
+		SELECT s.value FROM json_each(?) s
+		WHERE 1`+where+`
+		ORDER BY s.key
+	`, append([]any{string(scope)}, args...)...)
+	if err != nil {
+		cm.stats.recordError()
+		return nil, newCacheError("filter", "games", "", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, newCacheError("filter", "games", "", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newCacheError("filter", "games", "", err)
+	}
+
+	return ids, nil
+}
+
+// filterClause returns the conditions a game ID column has to meet for the filters, leaving
+// out the except facet.
+func filterClause(column string, filters Filters, except Facet) (string, []any) {
+	var b strings.Builder
+	var args []any
+
+	for _, facet := range Facets {
+		values := filters[facet]
+		if facet == except || len(values) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, " AND %s IN (SELECT game_id FROM game_facets WHERE facet = ? AND value IN (?%s))",
+			column, strings.Repeat(", ?", len(values)-1))
+		args = append(args, string(facet))
+		for _, value := range values {
+			args = append(args, value)
+		}
+	}
+
+	return b.String(), args
+}
+
+// sortFacetValues puts the newest years and the best ratings first and everything else in
+// alphabetical order.
+func sortFacetValues(facet Facet, values []FacetValue) {
+	switch facet {
+	case FacetYear, FacetRating:
+		slices.SortFunc(values, func(a, b FacetValue) int {
+			x, _ := strconv.Atoi(a.Value)
+			y, _ := strconv.Atoi(b.Value)
+			return cmp.Compare(y, x)
+		})
+	default:
+		slices.SortFunc(values, func(a, b FacetValue) int {
+			return cmp.Compare(strings.ToLower(a.Value), strings.ToLower(b.Value))
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"grout/romm"
+)
+
+func releasedIn(year int) int64 {
+	return time.Date(year, time.June, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+}
+
+func newFacetManager(t *testing.T) *Manager {
+	t.Helper()
+
+	cm := newTestManager(t)
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 1, Name: "Tetris", FsName: "Tetris (World).gb", Languages: []string{"English"},
+			Metadatum: romm.RomMetadata{Genres: []string{"Puzzle"}, GameModes: []string{"Single player", "Multiplayer"},
+				FirstReleaseDate: releasedIn(1989), AverageRating: 88}},
+		{ID: 2, PlatformID: 1, Name: "Dr. Mario", FsName: "Dr. Mario (Japan) (Ja).gb",
+			Metadatum: romm.RomMetadata{Genres: []string{"Puzzle"}, GameModes: []string{"Single player"},
+				FirstReleaseDate: releasedIn(1990), AverageRating: 95}},
+		{ID: 3, PlatformID: 1, Name: "The Legend of Zelda", FsName: "Legend of Zelda, The (USA).nes", Languages: []string{"English"},
+			Metadatum: romm.RomMetadata{Genres: []string{"Adventure", "Action"}, GameModes: []string{"Single player"},
+				FirstReleaseDate: releasedIn(1986), AverageRating: 92}},
+		{ID: 4, PlatformID: 1, Name: "Homebrew", FsName: "Homebrew.gb"},
+	}
+	if err := cm.SavePlatformGames(1, games); err != nil {
+		t.Fatalf("SavePlatformGames() error = %v", err)
+	}
+	return cm
+}
+
+func TestGetFacets(t *testing.T) {
+	cm := newFacetManager(t)
+
+	tests := []struct {
+		name    string
+		gameIDs []int
+		filters Filters
+		want    map[Facet][]FacetValue
+	}{
+		{
+			name:    "every game",
+			gameIDs: []int{1, 2, 3, 4},
+			want: map[Facet][]FacetValue{
+				FacetGenre:    {{"Action", 1}, {"Adventure", 1}, {"Puzzle", 2}},
+				FacetYear:     {{"1990", 1}, {"1989", 1}, {"1986", 1}},
+				FacetRating:   {{"90", 2}, {"80", 1}},
+				FacetPlayers:  {{"Multiplayer", 1}, {"Single player", 3}},
+				FacetLanguage: {{"English", 2}, {"Japanese", 1}},
+			},
+		},
+		{
+			name:    "only the games given",
+			gameIDs: []int{1, 4},
+			want: map[Facet][]FacetValue{
+				FacetGenre:    {{"Puzzle", 1}},
+				FacetYear:     {{"1989", 1}},
+				FacetRating:   {{"80", 1}},
+				FacetPlayers:  {{"Multiplayer", 1}, {"Single player", 1}},
+				FacetLanguage: {{"English", 1}},
+			},
+		},
+		{
+			name:    "counts leave out the facet's own filter",
+			gameIDs: []int{1, 2, 3, 4},
+			filters: Filters{FacetGenre: {"Puzzle"}},
+			want: map[Facet][]FacetValue{
+				FacetGenre:    {{"Action", 1}, {"Adventure", 1}, {"Puzzle", 2}},
+				FacetYear:     {{"1990", 1}, {"1989", 1}},
+				FacetRating:   {{"90", 1}, {"80", 1}},
+				FacetPlayers:  {{"Multiplayer", 1}, {"Single player", 2}},
+				FacetLanguage: {{"English", 1}, {"Japanese", 1}},
+			},
+		},
+		{
+			name:    "picked values without games stay listed",
+			gameIDs: []int{1, 2, 3, 4},
+			filters: Filters{FacetGenre: {"Puzzle"}, FacetYear: {"1986"}},
+			want: map[Facet][]FacetValue{
+				FacetGenre:    {{"Action", 1}, {"Adventure", 1}, {"Puzzle", 0}},
+				FacetYear:     {{"1990", 1}, {"1989", 1}, {"1986", 0}},
+				FacetRating:   nil,
+				FacetPlayers:  nil,
+				FacetLanguage: nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cm.GetFacets(tt.gameIDs, tt.filters)
+			if err != nil {
+				t.Fatalf("GetFacets() error = %v", err)
+			}
+			for _, facet := range Facets {
+				if !reflect.DeepEqual(got[facet], tt.want[facet]) {
+					t.Errorf("%s facet = %v, want %v", facet, got[facet], tt.want[facet])
+				}
+			}
+		})
+	}
+}
+
+func TestGetFacetsAfterUpdate(t *testing.T) {
+	cm := newFacetManager(t)
+
+	if _, err := cm.db.Exec(`UPDATE games SET data_json = json_set(data_json, '$.metadatum.genres', json('["Strategy"]')) WHERE id = 1`); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cm.GetFacets([]int{1, 2}, nil)
+	if err != nil {
+		t.Fatalf("GetFacets() error = %v", err)
+	}
+	if want := []FacetValue{{"Puzzle", 1}, {"Strategy", 1}}; !reflect.DeepEqual(got[FacetGenre], want) {
+		t.Errorf("genre facet after update = %v, want %v", got[FacetGenre], want)
+	}
+}
+
+func TestFilterGameIDs(t *testing.T) {
+	cm := newFacetManager(t)
+
+	tests := []struct {
+		name    string
+		gameIDs []int
+		filters Filters
+		want    []int
+	}{
+		{"no filters", []int{4, 3, 2, 1}, nil, []int{4, 3, 2, 1}},
+		{"keeps the order given", []int{4, 3, 2, 1}, Filters{FacetGenre: {"Puzzle"}}, []int{2, 1}},
+		{"any value of a facet", []int{1, 2, 3, 4}, Filters{FacetGenre: {"Puzzle", "Action"}}, []int{1, 2, 3}},
+		{"every facet", []int{1, 2, 3, 4}, Filters{FacetGenre: {"Puzzle"}, FacetRating: {"90"}}, []int{2}},
+		{"language from file name tags", []int{1, 2, 3, 4}, Filters{FacetLanguage: {"Japanese"}}, []int{2}},
+		{"nothing matches", []int{1, 2, 3, 4}, Filters{FacetYear: {"2001"}}, nil},
+		{"games missing from the cache", []int{99, 1}, Filters{FacetPlayers: {"Multiplayer"}}, []int{1}},
+		{"empty values are ignored", []int{2, 1}, Filters{FacetGenre: {}}, []int{2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cm.FilterGameIDs(tt.gameIDs, tt.filters)
+			if err != nil {
+				t.Fatalf("FilterGameIDs() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FilterGameIDs(%v, %v) = %v, want %v", tt.gameIDs, tt.filters, got, tt.want)
+			}
+		})
+	}
+}
@@ -48,7 +48,7 @@ func (cm *Manager) GetPlatformGames(platformID int) ([]romm.Rom, error) {
 	defer cm.mu.RUnlock()
 
 	rows, err := cm.db.Query(`
-		SELECT data_json FROM games WHERE platform_id = ? ORDER BY name
+		SELECT data_json, parsed_tags FROM games WHERE platform_id = ? ORDER BY name
 	`, platformID)
 	if err != nil {
 		cm.stats.recordError()
@@ -58,14 +58,8 @@ func (cm *Manager) GetPlatformGames(platformID int) ([]romm.Rom, error) {
 
 	var games []romm.Rom
 	for rows.Next() {
-		var dataJSON string
-		if err := rows.Scan(&dataJSON); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", GetPlatformCacheKey(platformID), err)
-		}
-
-		var game romm.Rom
-		if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
+		game, err := scanGame(rows)
+		if err != nil {
 			cm.stats.recordError()
 			return nil, newCacheError("get", "games", GetPlatformCacheKey(platformID), err)
 		}
@@ -108,8 +102,9 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 	}
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO games (id, platform_id, platform_fs_slug, name, fs_name, fs_name_no_ext, crc_hash, md5_hash, sha1_hash, data_json, updated_at, cached_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO games (id, platform_id, platform_fs_slug, name, fs_name, fs_name_no_ext, crc_hash, md5_hash, sha1_hash, data_json, updated_at, cached_at,
+			sort_release_date, sort_rating, sort_size, sort_created, sort_updated, parsed_tags)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
@@ -118,6 +113,12 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 
 	now := time.Now()
 	for _, game := range games {
+		game.ParsedTags = nil
+		tagsJSON, err := json.Marshal(game.NameTags())
+		if err != nil {
+			return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
+		}
+
 		dataJSON, err := json.Marshal(game)
 		if err != nil {
 			return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
@@ -136,6 +137,12 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 			string(dataJSON),
 			game.UpdatedAt,
 			now,
+			nullIfZero(game.Metadatum.FirstReleaseDate),
+			nullIfZero(game.Metadatum.AverageRating),
+			nullIfZero(game.FsSizeBytes),
+			unixOrNull(game.CreatedAt),
+			unixOrNull(game.UpdatedAt),
+			string(tagsJSON),
 		)
 		if err != nil {
 			return newCacheError("save", "games", GetPlatformCacheKey(platformID), err)
@@ -150,6 +157,130 @@ func (cm *Manager) SavePlatformGames(platformID int, games []romm.Rom) error {
 	return nil
 }
 
+// MetaKeyTagParserVersion records the romm.TagParserVersion that parsed the tags stored with cached games.
+const MetaKeyTagParserVersion = "tag_parser_version"
+
+// tagRefreshBatchSize is how many games refreshParsedTags parses in one transaction. The cache is
+// only locked for one batch at a time, so browsing isn't held up while a large library is parsed.
+const tagRefreshBatchSize = 500
+
+// RunTagRefresh parses the tags of cached games again in the background, see refreshParsedTags.
+func RunTagRefresh() {
+	if cm := GetCacheManager(); cm != nil {
+		go func() {
+			if err := cm.refreshParsedTags(); err != nil {
+				gaba.GetLogger().Warn("Failed to parse cached game tags again", "error", err)
+			}
+		}()
+	}
+}
+
+// refreshParsedTags parses the file name tags of every cached game again when they were stored by
+// an older parser, so parser fixes reach cached games without waiting for a cache refresh.
+func (cm *Manager) refreshParsedTags() error {
+	version := strconv.Itoa(romm.TagParserVersion)
+	if stored, err := cm.GetMetadata(MetaKeyTagParserVersion); err == nil && stored == version {
+		return nil
+	}
+
+	afterID, changed := 0, 0
+	for {
+		lastID, n, err := cm.reparseTags(afterID)
+		if err != nil {
+			return newCacheError("reparse", "games", "", err)
+		}
+		if lastID == 0 {
+			break
+		}
+		afterID = lastID
+		changed += n
+	}
+
+	gaba.GetLogger().Debug("Parsed cached game tags again", "changed", changed)
+	return cm.SetMetadata(MetaKeyTagParserVersion, version)
+}
+
+// reparseTags parses the tags of the next tagRefreshBatchSize games with an ID above afterID and
+// stores those that changed. It returns the last ID it parsed, 0 once no games are left.
+func (cm *Manager) reparseTags(afterID int) (lastID int, changed int, err error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	// Switching hosts closes the cache between batches
+	if !cm.initialized {
+		return 0, 0, ErrNotInitialized
+	}
+
+	tx, err := cm.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id, name, fs_name, parsed_tags FROM games WHERE id > ? ORDER BY id LIMIT ?`,
+		afterID, tagRefreshBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	updates := make(map[int]string)
+	for rows.Next() {
+		var game romm.Rom
+		var stored sql.NullString
+		if err := rows.Scan(&game.ID, &game.Name, &game.FsName, &stored); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		lastID = game.ID
+
+		tagsJSON, err := json.Marshal(game.NameTags())
+		if err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		if !stored.Valid || stored.String != string(tagsJSON) {
+			updates[game.ID] = string(tagsJSON)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	for id, tagsJSON := range updates {
+		if _, err := tx.Exec(`UPDATE games SET parsed_tags = ? WHERE id = ?`, tagsJSON, id); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return lastID, len(updates), tx.Commit()
+}
+
+// scanGame reads a games row selected as data_json and parsed_tags.
+func scanGame(rows *sql.Rows) (romm.Rom, error) {
+	var dataJSON string
+	var parsedTags sql.NullString
+	if err := rows.Scan(&dataJSON, &parsedTags); err != nil {
+		return romm.Rom{}, err
+	}
+
+	var game romm.Rom
+	if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
+		return romm.Rom{}, err
+	}
+
+	// Caches from before version 5 kept the tags in data_json, the column has the current ones
+	game.ParsedTags = nil
+	if parsedTags.Valid {
+		var tags romm.RomTags
+		if err := json.Unmarshal([]byte(parsedTags.String), &tags); err != nil {
+			return romm.Rom{}, err
+		}
+		game.ParsedTags = &tags
+	}
+	return game, nil
+}
+
 func (cm *Manager) GetCollectionGames(collection romm.Collection) ([]romm.Rom, error) {
 	if cm == nil || !cm.initialized {
 		return nil, ErrNotInitialized
@@ -164,7 +295,7 @@ func (cm *Manager) GetCollectionGames(collection romm.Collection) ([]romm.Rom, e
 	}
 
 	rows, err := cm.db.Query(`
-		SELECT g.data_json FROM games g
+		SELECT g.data_json, g.parsed_tags FROM games g
 		INNER JOIN game_collections gc ON g.id = gc.game_id
 		WHERE gc.collection_id = ?
 		ORDER BY g.name
@@ -177,14 +308,8 @@ func (cm *Manager) GetCollectionGames(collection romm.Collection) ([]romm.Rom, e
 
 	var games []romm.Rom
 	for rows.Next() {
-		var dataJSON string
-		if err := rows.Scan(&dataJSON); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", GetCollectionCacheKey(collection), err)
-		}
-
-		var game romm.Rom
-		if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
+		game, err := scanGame(rows)
+		if err != nil {
 			cm.stats.recordError()
 			return nil, newCacheError("get", "games", GetCollectionCacheKey(collection), err)
 		}
@@ -417,7 +542,7 @@ func (cm *Manager) GetGamesByIDs(gameIDs []int) ([]romm.Rom, error) {
 		args[i] = id
 	}
 
-	query := "SELECT data_json FROM games WHERE id IN (" + strings.Join(placeholders, ",") + ") ORDER BY name"
+	query := "SELECT data_json, parsed_tags FROM games WHERE id IN (" + strings.Join(placeholders, ",") + ") ORDER BY name"
 
 	rows, err := cm.db.Query(query, args...)
 	if err != nil {
@@ -428,14 +553,8 @@ func (cm *Manager) GetGamesByIDs(gameIDs []int) ([]romm.Rom, error) {
 
 	var games []romm.Rom
 	for rows.Next() {
-		var dataJSON string
-		if err := rows.Scan(&dataJSON); err != nil {
-			cm.stats.recordError()
-			return nil, newCacheError("get", "games", "batch", err)
-		}
-
-		var game romm.Rom
-		if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
+		game, err := scanGame(rows)
+		if err != nil {
 			cm.stats.recordError()
 			return nil, newCacheError("get", "games", "batch", err)
 		}
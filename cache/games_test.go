@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"grout/romm"
+)
+
+// newTestManager returns a Manager on an empty database at the current schema version.
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	db, err := openDB(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("openDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+
+	return &Manager{db: db, initialized: true, stats: &CacheStats{}}
+}
+
+func TestRefreshParsedTags(t *testing.T) {
+	cm := newTestManager(t)
+
+	game := romm.Rom{ID: 1, PlatformID: 1, Name: "Alpha", FsName: "Alpha (Japan) (Ja) (Rev 1).zip"}
+	if err := cm.SavePlatformGames(1, []romm.Rom{game}); err != nil {
+		t.Fatalf("SavePlatformGames() error = %v", err)
+	}
+
+	// Tags written by an older parser that got the name wrong
+	if _, err := cm.db.Exec(`UPDATE games SET parsed_tags = '{"languages":["Klingon"]}'`); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.SetMetadata(MetaKeyTagParserVersion, "0"); err != nil {
+		t.Fatal(err)
+	}
+
+	var before string
+	if err := cm.db.QueryRow(`SELECT data_json FROM games WHERE id = 1`).Scan(&before); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cm.refreshParsedTags(); err != nil {
+		t.Fatalf("refreshParsedTags() error = %v", err)
+	}
+
+	// Only the tags are written again, not the rest of the game
+	var after string
+	if err := cm.db.QueryRow(`SELECT data_json FROM games WHERE id = 1`).Scan(&after); err != nil {
+		t.Fatal(err)
+	}
+	if after != before {
+		t.Errorf("data_json after refresh = %s, want it unchanged", after)
+	}
+	if version, _ := cm.GetMetadata(MetaKeyTagParserVersion); version != strconv.Itoa(romm.TagParserVersion) {
+		t.Errorf("tag parser version after refresh = %q, want %d", version, romm.TagParserVersion)
+	}
+
+	games, err := cm.GetGamesByIDs([]int{1})
+	if err != nil || len(games) != 1 {
+		t.Fatalf("GetGamesByIDs() = %v, %v", games, err)
+	}
+	if got, want := games[0].NameTags(), romm.ParseTags(game.FsName); !reflect.DeepEqual(got, want) {
+		t.Errorf("NameTags() after refresh = %+v, want %+v", got, want)
+	}
+
+	facets, err := cm.GetFacets([]int{1}, nil)
+	if err != nil {
+		t.Fatalf("GetFacets() error = %v", err)
+	}
+	if got, want := facets[FacetLanguage], []FacetValue{{Value: "Japanese", Count: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("language facet after refresh = %v, want %v", got, want)
+	}
+}
@@ -1,7 +1,11 @@
 package cache
 
 import (
+	"context"
+	"crypto/sha1"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"grout/internal/fileutil"
 	"grout/romm"
 	"os"
@@ -21,6 +25,7 @@ type Manager struct {
 	host        romm.Host
 	config      Config
 	initialized bool
+	recovered   bool
 
 	stats *CacheStats
 }
@@ -54,22 +59,71 @@ func (s *CacheStats) recordError() {
 }
 
 var (
-	cacheManager     *Manager
-	cacheManagerOnce sync.Once
-	cacheManagerErr  error
+	// cacheManagerMu guards the cache manager globals, switching hosts swaps them while
+	// background work may still be reading them
+	cacheManagerMu    sync.RWMutex
+	cacheManager      *Manager
+	cacheManagerReady bool
+	cacheManagerErr   error
+
+	// hostNamespace names the folder under .cache/hosts holding the active host's database and artwork
+	hostNamespace atomic.String
 )
 
 func GetCacheManager() *Manager {
+	cacheManagerMu.RLock()
+	defer cacheManagerMu.RUnlock()
 	return cacheManager
 }
 
 func InitCacheManager(host romm.Host, config Config) error {
-	cacheManagerOnce.Do(func() {
+	cacheManagerMu.Lock()
+	defer cacheManagerMu.Unlock()
+	return initCacheManagerLocked(host, config)
+}
+
+// SwitchHost closes the cache of the current host and opens the one belonging to host.
+func SwitchHost(host romm.Host, config Config) error {
+	cacheManagerMu.Lock()
+	defer cacheManagerMu.Unlock()
+
+	resetCacheManagerLocked()
+	return initCacheManagerLocked(host, config)
+}
+
+// initCacheManagerLocked opens the cache once, later calls return the outcome of the first.
+// cacheManagerMu must be held.
+func initCacheManagerLocked(host romm.Host, config Config) error {
+	if !cacheManagerReady {
+		hostNamespace.Store(namespaceForHost(host))
 		cacheManager, cacheManagerErr = newCacheManager(host, config)
-	})
+		cacheManagerReady = true
+	}
 	return cacheManagerErr
 }
 
+func resetCacheManager() {
+	cacheManagerMu.Lock()
+	defer cacheManagerMu.Unlock()
+	resetCacheManagerLocked()
+}
+
+// resetCacheManagerLocked closes the open cache, cacheManagerMu must be held.
+func resetCacheManagerLocked() {
+	if cacheManager != nil {
+		cacheManager.Close()
+		cacheManager = nil
+	}
+
+	cacheManagerReady = false
+	cacheManagerErr = nil
+}
+
+func namespaceForHost(host romm.Host) string {
+	sum := sha1.Sum([]byte(host.Key()))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
 func newCacheManager(host romm.Host, config Config) (*Manager, error) {
 	logger := gaba.GetLogger()
 
@@ -81,18 +135,28 @@ func newCacheManager(host romm.Host, config Config) (*Manager, error) {
 	}
 
 	cleanupLegacyCache()
+	migrateSingleHostCache(cacheDir)
 
-	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)")
+	db, err := openDB(dbPath)
 	if err != nil {
 		return nil, newCacheError("init", "", "", err)
 	}
 
-	db.SetMaxOpenConns(1)
-	db.SetMaxIdleConns(1)
-
-	if err := createTables(db); err != nil {
+	// A cache that can't be migrated is rebuilt from RomM rather than leaving the app without one.
+	// One written by a newer Grout is left alone, it is still good once Grout is updated.
+	recovered := false
+	if err := migrate(db); errors.Is(err, ErrSchemaTooNew) {
+		logger.Error("Cache was created by a newer version of Grout, running without it", "path", dbPath, "error", err)
 		db.Close()
 		return nil, newCacheError("init", "", "", err)
+	} else if err != nil {
+		logger.Error("Cache migration failed, rebuilding cache", "path", dbPath, "error", err)
+		db.Close()
+
+		if db, err = recreateDB(dbPath); err != nil {
+			return nil, newCacheError("init", "", "", err)
+		}
+		recovered = true
 	}
 
 	cm := &Manager{
@@ -101,6 +165,7 @@ func newCacheManager(host romm.Host, config Config) (*Manager, error) {
 		host:        host,
 		config:      config,
 		initialized: true,
+		recovered:   recovered,
 		stats:       &CacheStats{},
 	}
 
@@ -108,6 +173,38 @@ func newCacheManager(host romm.Host, config Config) (*Manager, error) {
 	return cm, nil
 }
 
+func openDB(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	return db, nil
+}
+
+// recreateDB deletes the database at dbPath and creates an empty one at the current schema
+// version. Artwork is kept, it doesn't depend on the database.
+func recreateDB(dbPath string) (*sql.DB, error) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	db, err := openDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
 func (cm *Manager) Close() error {
 	if cm == nil || cm.db == nil {
 		return nil
@@ -137,6 +234,28 @@ func (cm *Manager) IsFirstRun() bool {
 	return count == 0
 }
 
+// Recovered reports whether the cache was rebuilt empty because its database couldn't be
+// migrated. The caller refills it from RomM.
+func (cm *Manager) Recovered() bool {
+	return cm != nil && cm.recovered
+}
+
+// SchemaVersion returns the schema version of the cache database, 0 when it is unavailable.
+func (cm *Manager) SchemaVersion() int {
+	if cm == nil || !cm.initialized {
+		return 0
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	version, err := readSchemaVersion(cm.db)
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
 func (cm *Manager) HasCache() bool {
 	if cm == nil || !cm.initialized {
 		return false
@@ -317,28 +436,25 @@ func (cm *Manager) GetAllRefreshTimes() map[string]time.Time {
 	return result
 }
 
-func (cm *Manager) PopulateFullCacheWithProgress(platforms []romm.Platform, progress *atomic.Float64) error {
+func (cm *Manager) PopulateFullCacheWithProgress(ctx context.Context, platforms []romm.Platform, progress *atomic.Float64) error {
 	if cm == nil || !cm.initialized {
 		return ErrNotInitialized
 	}
 
-	return cm.populateCache(platforms, progress)
+	return cm.populateCache(ctx, platforms, progress)
 }
 
 func getCacheDBPath() string {
-	wd, err := os.Getwd()
-	if err != nil {
-		return filepath.Join(os.TempDir(), ".cache", "grout.db")
-	}
-	return filepath.Join(wd, ".cache", "grout.db")
+	return filepath.Join(GetHostCacheDir(), "grout.db")
 }
 
 func GetArtworkCacheDir() string {
-	wd, err := os.Getwd()
-	if err != nil {
-		return filepath.Join(os.TempDir(), ".cache", "artwork")
-	}
-	return filepath.Join(wd, ".cache", "artwork")
+	return filepath.Join(GetHostCacheDir(), "artwork")
+}
+
+// GetHostCacheDir is the cache folder of the active host.
+func GetHostCacheDir() string {
+	return filepath.Join(GetCacheDir(), "hosts", hostNamespace.Load())
 }
 
 func GetCacheDir() string {
@@ -349,27 +465,50 @@ func GetCacheDir() string {
 	return filepath.Join(wd, ".cache")
 }
 
-func DeleteCacheFolder() error {
+// DeleteHostCacheFolder removes the database and artwork cached for host, closing the cache
+// first when host is the active one.
+func DeleteHostCacheFolder(host romm.Host) error {
 	logger := gaba.GetLogger()
 
-	if cacheManager != nil {
-		cacheManager.Close()
-		cacheManager = nil
+	namespace := namespaceForHost(host)
+	if namespace == hostNamespace.Load() {
+		resetCacheManager()
 	}
 
-	cacheManagerOnce = sync.Once{}
-	cacheManagerErr = nil
-
-	cacheDir := GetCacheDir()
-	if err := os.RemoveAll(cacheDir); err != nil {
-		logger.Error("Failed to delete cache folder", "path", cacheDir, "error", err)
+	hostDir := filepath.Join(GetCacheDir(), "hosts", namespace)
+	if err := os.RemoveAll(hostDir); err != nil {
+		logger.Error("Failed to delete host cache folder", "path", hostDir, "error", err)
 		return err
 	}
 
-	logger.Info("Cache folder deleted", "path", cacheDir)
+	logger.Info("Host cache folder deleted", "host", host.URL(), "path", hostDir)
 	return nil
 }
 
+// migrateSingleHostCache moves the database and artwork that versions with a single host kept
+// directly in .cache into the active host's folder, so upgrading doesn't rebuild the cache.
+func migrateSingleHostCache(hostDir string) {
+	logger := gaba.GetLogger()
+	cacheDir := GetCacheDir()
+
+	legacyDB := filepath.Join(cacheDir, "grout.db")
+	if !fileutil.FileExists(legacyDB) || fileutil.FileExists(filepath.Join(hostDir, "grout.db")) {
+		return
+	}
+
+	for _, name := range []string{"grout.db", "grout.db-wal", "grout.db-shm", "artwork"} {
+		from := filepath.Join(cacheDir, name)
+		if !fileutil.FileExists(from) {
+			continue
+		}
+		if err := os.Rename(from, filepath.Join(hostDir, name)); err != nil {
+			logger.Warn("Failed to move cache into host folder", "path", from, "error", err)
+		}
+	}
+
+	logger.Info("Moved cache into host folder", "path", hostDir)
+}
+
 func cleanupLegacyCache() {
 	logger := gaba.GetLogger()
 
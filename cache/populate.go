@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"grout/romm"
 	"sync"
 
@@ -8,12 +9,9 @@ import (
 	"go.uber.org/atomic"
 )
 
-const (
-	DefaultRomPageSize           = 200
-	MaxConcurrentPlatformFetches = 5
-)
+const MaxConcurrentPlatformFetches = 5
 
-func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Float64) error {
+func (cm *Manager) populateCache(ctx context.Context, platforms []romm.Platform, progress *atomic.Float64) error {
 	logger := gaba.GetLogger()
 
 	if len(platforms) == 0 {
@@ -57,10 +55,14 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 		wg.Add(1)
 		go func(p romm.Platform) {
 			defer wg.Done()
-			sem <- struct{}{}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-sem }()
 
-			if err := cm.fetchAndCachePlatformGamesWithProgress(p, updateProgress); err != nil {
+			if err := cm.fetchAndCachePlatformGamesWithProgress(ctx, p, updateProgress); err != nil {
 				logger.Error("Failed to cache platform", "platform", p.Name, "error", err)
 				errMu.Lock()
 				if firstErr == nil {
@@ -74,16 +76,26 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		cm.fetchBIOSAvailability(platforms)
+		cm.fetchBIOSAvailability(ctx, platforms)
 	}()
 
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		logger.Info("Cache population cancelled", "games", gamesFetched.Load())
+		return err
+	}
+
 	if firstErr == nil {
 		cm.RecordRefreshTime(MetaKeyGamesRefreshedAt)
 	}
 
-	cm.fetchAndCacheCollectionsWithProgress(progress)
+	cm.fetchAndCacheCollectionsWithProgress(ctx, progress)
+
+	if err := ctx.Err(); err != nil {
+		logger.Info("Cache population cancelled", "games", gamesFetched.Load())
+		return err
+	}
 
 	cm.RecordRefreshTime(MetaKeyCollectionsRefreshedAt)
 
@@ -95,51 +107,34 @@ func (cm *Manager) populateCache(platforms []romm.Platform, progress *atomic.Flo
 	return firstErr
 }
 
-func (cm *Manager) fetchAndCachePlatformGames(platform romm.Platform) error {
-	return cm.fetchAndCachePlatformGamesWithProgress(platform, nil)
+func (cm *Manager) fetchAndCachePlatformGames(ctx context.Context, platform romm.Platform) error {
+	return cm.fetchAndCachePlatformGamesWithProgress(ctx, platform, nil)
 }
 
-func (cm *Manager) fetchAndCachePlatformGamesWithProgress(platform romm.Platform, onProgress func(count int)) error {
+func (cm *Manager) fetchAndCachePlatformGamesWithProgress(ctx context.Context, platform romm.Platform, onProgress func(count int)) error {
 	logger := gaba.GetLogger()
 
 	client := romm.NewClientFromHost(cm.host, cm.config.GetApiTimeout())
 
 	var allGames []romm.Rom
-	offset := 0
-	expectedTotal := 0
-
-	for {
-		opt := romm.GetRomsQuery{
-			PlatformID: platform.ID,
-			Offset:     offset,
-			Limit:      DefaultRomPageSize,
-		}
-
-		res, err := client.GetRoms(opt)
-		if err != nil {
-			logger.Error("Failed to fetch games",
-				"platform", platform.Name,
-				"offset", offset,
-				"error", err)
-			return err
-		}
-
-		if offset == 0 {
-			expectedTotal = res.Total
-		}
-
-		allGames = append(allGames, res.Items...)
-
-		if onProgress != nil && len(res.Items) > 0 {
-			onProgress(len(res.Items))
-		}
+	query := romm.GetRomsQuery{
+		PlatformID: platform.ID,
+		Limit:      romm.DefaultRomPageSize,
+	}
 
-		// Terminate when: got all expected, empty batch, or partial page (last batch)
-		if len(allGames) >= expectedTotal || len(res.Items) == 0 || len(res.Items) < DefaultRomPageSize {
-			break
+	err := client.WalkRoms(ctx, query, func(page romm.PaginatedRoms) error {
+		allGames = append(allGames, page.Items...)
+		if onProgress != nil {
+			onProgress(len(page.Items))
 		}
-
-		offset += len(res.Items)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to fetch games",
+			"platform", platform.Name,
+			"fetched", len(allGames),
+			"error", err)
+		return err
 	}
 
 	logger.Info("Cached platform games",
@@ -149,10 +144,11 @@ func (cm *Manager) fetchAndCachePlatformGamesWithProgress(platform romm.Platform
 	return cm.SavePlatformGames(platform.ID, allGames)
 }
 
-func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64) {
+func (cm *Manager) fetchAndCacheCollectionsWithProgress(ctx context.Context, progress *atomic.Float64) {
 	logger := gaba.GetLogger()
 
 	client := romm.NewClientFromHost(cm.host, cm.config.GetApiTimeout())
+	capabilities := cm.host.Capabilities()
 
 	var allCollections []romm.Collection
 	var mu sync.Mutex
@@ -161,7 +157,7 @@ func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		collections, err := client.GetCollections()
+		collections, err := client.GetCollectionsContext(ctx)
 		if err != nil {
 			logger.Error("Failed to fetch regular collections", "error", err)
 			return
@@ -171,39 +167,48 @@ func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64
 		mu.Unlock()
 	}()
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		collections, err := client.GetSmartCollections()
-		if err != nil {
-			logger.Error("Failed to fetch smart collections", "error", err)
-			return
-		}
-		for i := range collections {
-			collections[i].IsSmart = true
-		}
-		mu.Lock()
-		allCollections = append(allCollections, collections...)
-		mu.Unlock()
-	}()
+	// Older servers don't have smart or virtual collections, asking would only log a 404
+	if capabilities.Has(romm.CapabilitySmartCollections) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			collections, err := client.GetSmartCollectionsContext(ctx)
+			if err != nil {
+				logger.Error("Failed to fetch smart collections", "error", err)
+				return
+			}
+			for i := range collections {
+				collections[i].IsSmart = true
+			}
+			mu.Lock()
+			allCollections = append(allCollections, collections...)
+			mu.Unlock()
+		}()
+	}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		virtualCollections, err := client.GetVirtualCollections()
-		if err != nil {
-			logger.Error("Failed to fetch virtual collections", "error", err)
-			return
-		}
-		mu.Lock()
-		for _, vc := range virtualCollections {
-			allCollections = append(allCollections, vc.ToCollection())
-		}
-		mu.Unlock()
-	}()
+	if capabilities.Has(romm.CapabilityVirtualCollections) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			virtualCollections, err := client.GetVirtualCollectionsContext(ctx)
+			if err != nil {
+				logger.Error("Failed to fetch virtual collections", "error", err)
+				return
+			}
+			mu.Lock()
+			for _, vc := range virtualCollections {
+				allCollections = append(allCollections, vc.ToCollection())
+			}
+			mu.Unlock()
+		}()
+	}
 
 	wg.Wait()
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	// Update progress to 92% after fetching collection metadata, arbitrary I know
 	if progress != nil {
 		progress.Store(0.92)
@@ -232,9 +237,17 @@ func (cm *Manager) fetchAndCacheCollectionsWithProgress(progress *atomic.Float64
 	logger.Debug("Cached collections", "count", len(allCollections))
 }
 
-func (cm *Manager) fetchBIOSAvailability(platforms []romm.Platform) {
+func (cm *Manager) fetchBIOSAvailability(ctx context.Context, platforms []romm.Platform) {
 	logger := gaba.GetLogger()
 
+	if !cm.host.Capabilities().Has(romm.CapabilityFirmware) {
+		logger.Debug("Server does not support firmware, skipping BIOS availability")
+		for _, platform := range platforms {
+			cm.SetBIOSAvailability(platform.ID, false)
+		}
+		return
+	}
+
 	client := romm.NewClientFromHost(cm.host, cm.config.GetApiTimeout())
 
 	var wg sync.WaitGroup
@@ -244,11 +257,18 @@ func (cm *Manager) fetchBIOSAvailability(platforms []romm.Platform) {
 		wg.Add(1)
 		go func(p romm.Platform) {
 			defer wg.Done()
-			sem <- struct{}{}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-sem }()
 
-			firmware, err := client.GetFirmware(p.ID)
+			firmware, err := client.GetFirmwareContext(ctx, p.ID)
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				logger.Debug("Failed to fetch BIOS info", "platform", p.Name, "error", err)
 				cm.SetBIOSAvailability(p.ID, false)
 				return
@@ -267,10 +287,14 @@ func (cm *Manager) RefreshPlatformGames(platform romm.Platform) error {
 		return ErrNotInitialized
 	}
 
-	return cm.fetchAndCachePlatformGames(platform)
+	return cm.fetchAndCachePlatformGames(context.Background(), platform)
 }
 
 func (cm *Manager) RefreshPlatformGamesWithProgress(platform romm.Platform, progress *atomic.Float64) error {
+	return cm.RefreshPlatformGamesWithProgressContext(context.Background(), platform, progress)
+}
+
+func (cm *Manager) RefreshPlatformGamesWithProgressContext(ctx context.Context, platform romm.Platform, progress *atomic.Float64) error {
 	if cm == nil || !cm.initialized {
 		return ErrNotInitialized
 	}
@@ -279,45 +303,28 @@ func (cm *Manager) RefreshPlatformGamesWithProgress(platform romm.Platform, prog
 	client := romm.NewClientFromHost(cm.host, cm.config.GetApiTimeout())
 
 	var allGames []romm.Rom
-	offset := 0
-	expectedTotal := 0
-
-	for {
-		opt := romm.GetRomsQuery{
-			PlatformID: platform.ID,
-			Offset:     offset,
-			Limit:      DefaultRomPageSize,
-		}
-
-		res, err := client.GetRoms(opt)
-		if err != nil {
-			logger.Error("Failed to fetch games",
-				"platform", platform.Name,
-				"offset", offset,
-				"error", err)
-			return err
-		}
-
-		if offset == 0 {
-			expectedTotal = res.Total
-		}
-
-		allGames = append(allGames, res.Items...)
+	query := romm.GetRomsQuery{
+		PlatformID: platform.ID,
+		Limit:      romm.DefaultRomPageSize,
+	}
 
-		if progress != nil && expectedTotal > 0 {
-			pct := float64(len(allGames)) / float64(expectedTotal)
+	err := client.WalkRoms(ctx, query, func(page romm.PaginatedRoms) error {
+		allGames = append(allGames, page.Items...)
+		if progress != nil && page.Total > 0 {
+			pct := float64(len(allGames)) / float64(page.Total)
 			if pct > 1.0 {
 				pct = 1.0
 			}
 			progress.Store(pct)
 		}
-
-		// Terminate when: got all expected, empty batch, or partial page (last batch)
-		if len(allGames) >= expectedTotal || len(res.Items) == 0 || len(res.Items) < DefaultRomPageSize {
-			break
-		}
-
-		offset += len(res.Items)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to fetch games",
+			"platform", platform.Name,
+			"fetched", len(allGames),
+			"error", err)
+		return err
 	}
 
 	logger.Info("Refreshed platform games",
@@ -2,18 +2,57 @@ package cache
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 )
 
-const schemaVersion = 1
+// migration moves the cache database from the previous schema version to version. Migrations
+// run in order, each one in its own transaction.
+type migration struct {
+	version     int
+	description string
+	up          func(tx *sql.Tx) error
+}
 
-func createTables(db *sql.DB) error {
-	tx, err := db.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+// migrations lists every schema change in order. Add new steps at the end, released steps
+// must never change since databases in the wild already ran them.
+var migrations = []migration{
+	{version: 1, description: "initial schema", up: createTables},
+	{version: 2, description: "full-text search index", up: createSearchIndex},
+	{version: 3, description: "game facets", up: createFacetIndex},
+	{version: 4, description: "game sort columns", up: addSortColumns},
+	{version: 5, description: "parsed tags column", up: addParsedTagsColumn},
+}
 
-	_, err = tx.Exec(`
+// schemaVersion is the version a fully migrated database is at.
+var schemaVersion = migrations[len(migrations)-1].version
+
+// ErrSchemaTooNew is returned for a database written by a newer Grout, whose schema this
+// version doesn't know how to read.
+var ErrSchemaTooNew = errors.New("cache schema is newer than this version of grout")
+
+// MigrationError is a failed migration step.
+type MigrationError struct {
+	Version     int
+	Description string
+	Err         error
+}
+
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("cache migration %d (%s): %v", e.Version, e.Description, e.Err)
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+// migrate brings the database up to schemaVersion, running the steps it hasn't run yet. The
+// version reached is recorded after every step so a failure resumes where it stopped.
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS cache_metadata (
 			key TEXT PRIMARY KEY,
 			value TEXT NOT NULL,
@@ -24,7 +63,70 @@ func createTables(db *sql.DB) error {
 		return err
 	}
 
+	current, err := readSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if current > schemaVersion {
+		return fmt.Errorf("%w: found %d, expected %d", ErrSchemaTooNew, current, schemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := runMigration(db, m); err != nil {
+			return &MigrationError{Version: m.version, Description: m.description, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func runMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(`
+		INSERT OR REPLACE INTO cache_metadata (key, value, updated_at)
+		VALUES ('schema_version', ?, CURRENT_TIMESTAMP)
+	`, m.version)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// readSchemaVersion returns the version recorded in cache_metadata, 0 for a new database.
+func readSchemaVersion(db *sql.DB) (int, error) {
+	var value string
+	err := db.QueryRow(`SELECT value FROM cache_metadata WHERE key = 'schema_version'`).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", value, err)
+	}
+	return version, nil
+}
+
+// createTables is the first schema. Its tables already existed before migrations were
+// versioned, so it keeps IF NOT EXISTS to adopt those databases as version 1.
+func createTables(tx *sql.Tx) error {
+	_, err := tx.Exec(`
 		CREATE TABLE IF NOT EXISTS platforms (
 			id INTEGER PRIMARY KEY,
 			slug TEXT NOT NULL,
@@ -144,13 +246,213 @@ func createTables(db *sql.DB) error {
 		return err
 	}
 
-	_, err = tx.Exec(`
-		INSERT OR REPLACE INTO cache_metadata (key, value, updated_at)
-		VALUES ('schema_version', ?, CURRENT_TIMESTAMP)
-	`, schemaVersion)
-	if err != nil {
-		return err
+	return nil
+}
+
+// searchIndexColumns are the values a game is indexed with, taken from a games row named new.
+const searchIndexColumns = `
+	new.name,
+	new.fs_name,
+	(SELECT group_concat(value, ' ') FROM json_each(new.data_json, '$.alternative_names')),
+	json_extract(new.data_json, '$.summary'),
+	(SELECT group_concat(value, ' ') FROM json_each(new.data_json, '$.metadatum.genres')),
+	(SELECT group_concat(value, ' ') FROM json_each(new.data_json, '$.metadatum.companies'))`
+
+// indexGameSearch adds the games row named new to both search indexes.
+const indexGameSearch = `
+			INSERT INTO games_fts (rowid, name, fs_name, alternative_names, summary, genres, companies)
+			VALUES (new.id,` + searchIndexColumns + `);
+			INSERT INTO games_trigram (rowid, name, alternative_names)
+			VALUES (new.id, new.name, (SELECT group_concat(value, ' ') FROM json_each(new.data_json, '$.alternative_names')));`
+
+// unindexGameSearch removes the games row named old from both search indexes.
+const unindexGameSearch = `
+			DELETE FROM games_fts WHERE rowid = old.id;
+			DELETE FROM games_trigram WHERE rowid = old.id;`
+
+// createSearchIndex adds the FTS5 tables game search runs on. games_fts matches whole words
+// and games_trigram matches parts of names, which is what finds games despite typos. Triggers
+// keep both in step with the games table, whichever way games are saved or cleared.
+func createSearchIndex(tx *sql.Tx) error {
+	statements := []string{
+		`CREATE VIRTUAL TABLE games_fts USING fts5(
+			name, fs_name, alternative_names, summary, genres, companies,
+			content='', contentless_delete=1, tokenize='unicode61 remove_diacritics 2'
+		)`,
+		`CREATE VIRTUAL TABLE games_trigram USING fts5(
+			name, alternative_names,
+			content='', contentless_delete=1, tokenize='trigram remove_diacritics 1'
+		)`,
+		`CREATE TRIGGER games_search_insert AFTER INSERT ON games BEGIN` + indexGameSearch + `
+		END`,
+		`CREATE TRIGGER games_search_delete AFTER DELETE ON games BEGIN` + unindexGameSearch + `
+		END`,
+		`CREATE TRIGGER games_search_update AFTER UPDATE ON games BEGIN` + unindexGameSearch + indexGameSearch + `
+		END`,
+		// Index the games cached before the update
+		`INSERT INTO games_fts (rowid, name, fs_name, alternative_names, summary, genres, companies)
+		SELECT new.id,` + searchIndexColumns + ` FROM games AS new`,
+		`INSERT INTO games_trigram (rowid, name, alternative_names)
+		SELECT new.id, new.name, (SELECT group_concat(value, ' ') FROM json_each(new.data_json, '$.alternative_names'))
+		FROM games AS new`,
 	}
 
-	return tx.Commit()
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type facetSource struct {
+	facet  Facet
+	values string
+}
+
+// facetSources are the JSON values each facet is read from, for a games row named new. Release
+// years come from the first release date, which RomM gives in milliseconds, and ratings are
+// grouped in steps of ten with 90 covering everything from 90 to 100.
+var facetSources = []facetSource{
+	{FacetGenre, `json_each(new.data_json, '$.metadatum.genres')`},
+	{FacetYear, `json_each(json_array(CASE WHEN json_extract(new.data_json, '$.metadatum.first_release_date') > 0
+		THEN strftime('%Y', json_extract(new.data_json, '$.metadatum.first_release_date') / 1000, 'unixepoch') END))`},
+	{FacetRating, `json_each(json_array(CASE WHEN json_extract(new.data_json, '$.metadatum.average_rating') > 0
+		THEN min(90, CAST(json_extract(new.data_json, '$.metadatum.average_rating') / 10 AS INTEGER) * 10) END))`},
+	{FacetPlayers, `json_each(new.data_json, '$.metadatum.game_modes')`},
+	{FacetLanguage, `json_each(new.data_json, '$.languages')`},
+}
+
+// The languages of the file name tags are a facet too. Up to version 4 the parsed tags were
+// stored in data_json, version 5 gave them their own column.
+var (
+	dataJSONTagsFacetSource = facetSource{FacetLanguage, `json_each(new.data_json, '$.parsed_tags.languages')`}
+	columnTagsFacetSource   = facetSource{FacetLanguage, `json_each(new.parsed_tags, '$.languages')`}
+)
+
+// insertFacets returns the statement adding the facet values of the games row named new. With
+// from naming the games table as new it adds those of every row, which is how existing games
+// are indexed.
+func insertFacets(sources []facetSource, from string) string {
+	selects := make([]string, len(sources))
+	for i, source := range sources {
+		selects[i] = fmt.Sprintf(`SELECT new.id, '%s', value FROM %s%s WHERE value IS NOT NULL AND value != ''`,
+			source.facet, from, source.values)
+	}
+	return `
+			INSERT OR IGNORE INTO game_facets (game_id, facet, value)
+			` + strings.Join(selects, `
+			UNION ALL `) + `;`
+}
+
+// createFacetIndex adds game_facets, one row for each genre, release year, rating, game mode
+// and language of a game, indexed so facet counts and filters don't have to parse data_json.
+// Like the search index it is kept up to date by triggers.
+func createFacetIndex(tx *sql.Tx) error {
+	sources := slices.Concat(facetSources, []facetSource{dataJSONTagsFacetSource})
+	statements := []string{
+		`CREATE TABLE game_facets (
+			game_id INTEGER NOT NULL,
+			facet TEXT NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY (game_id, facet, value)
+		) WITHOUT ROWID`,
+		`CREATE INDEX idx_game_facets_value ON game_facets(facet, value, game_id)`,
+		`CREATE TRIGGER games_facets_insert AFTER INSERT ON games BEGIN` + insertFacets(sources, "") + `
+		END`,
+		`CREATE TRIGGER games_facets_delete AFTER DELETE ON games BEGIN
+			DELETE FROM game_facets WHERE game_id = old.id;
+		END`,
+		`CREATE TRIGGER games_facets_update AFTER UPDATE ON games BEGIN
+			DELETE FROM game_facets WHERE game_id = old.id;` + insertFacets(sources, "") + `
+		END`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	// Index the games cached before the update
+	_, err := tx.Exec(insertFacets(sources, "games AS new, "))
+	return err
+}
+
+// addSortColumns adds the values game lists are sorted by as columns of games, so sorting
+// doesn't parse data_json. Dates are Unix times, in milliseconds for the release date as
+// RomM gives it, and values RomM doesn't have are NULL.
+func addSortColumns(tx *sql.Tx) error {
+	sources := slices.Concat(facetSources, []facetSource{dataJSONTagsFacetSource})
+	statements := []string{
+		// Filling in the new columns must not reindex every game, so the update triggers now only
+		// fire for the columns the indexes are built from
+		`DROP TRIGGER games_search_update`,
+		`CREATE TRIGGER games_search_update AFTER UPDATE OF name, fs_name, data_json ON games BEGIN` +
+			unindexGameSearch + indexGameSearch + `
+		END`,
+		`DROP TRIGGER games_facets_update`,
+		`CREATE TRIGGER games_facets_update AFTER UPDATE OF data_json ON games BEGIN
+			DELETE FROM game_facets WHERE game_id = old.id;` + insertFacets(sources, "") + `
+		END`,
+		`ALTER TABLE games ADD COLUMN sort_release_date INTEGER`,
+		`ALTER TABLE games ADD COLUMN sort_rating REAL`,
+		`ALTER TABLE games ADD COLUMN sort_size INTEGER`,
+		`ALTER TABLE games ADD COLUMN sort_created INTEGER`,
+		`ALTER TABLE games ADD COLUMN sort_updated INTEGER`,
+		`UPDATE games SET
+			sort_release_date = NULLIF(json_extract(data_json, '$.metadatum.first_release_date'), 0),
+			sort_rating = NULLIF(json_extract(data_json, '$.metadatum.average_rating'), 0),
+			sort_size = NULLIF(json_extract(data_json, '$.fs_size_bytes'), 0),
+			sort_created = CASE WHEN unixepoch(json_extract(data_json, '$.created_at')) > 0
+				THEN unixepoch(json_extract(data_json, '$.created_at')) END,
+			sort_updated = CASE WHEN unixepoch(json_extract(data_json, '$.updated_at')) > 0
+				THEN unixepoch(json_extract(data_json, '$.updated_at')) END`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addParsedTagsColumn moves the parsed file name tags of games out of data_json into their own
+// column. Parsing them again after a parser update then only writes that column, which leaves
+// the search index alone and only touches the language facet of games whose tags changed.
+func addParsedTagsColumn(tx *sql.Tx) error {
+	sources := slices.Concat(facetSources, []facetSource{columnTagsFacetSource})
+
+	var languageSources []facetSource
+	for _, source := range sources {
+		if source.facet == FacetLanguage {
+			languageSources = append(languageSources, source)
+		}
+	}
+
+	statements := []string{
+		`ALTER TABLE games ADD COLUMN parsed_tags TEXT`,
+		// No trigger watches the new column yet, so copying the tags over doesn't reindex anything
+		`UPDATE games SET parsed_tags = json_extract(data_json, '$.parsed_tags')`,
+		`DROP TRIGGER games_facets_insert`,
+		`CREATE TRIGGER games_facets_insert AFTER INSERT ON games BEGIN` + insertFacets(sources, "") + `
+		END`,
+		`DROP TRIGGER games_facets_update`,
+		`CREATE TRIGGER games_facets_update AFTER UPDATE OF data_json ON games BEGIN
+			DELETE FROM game_facets WHERE game_id = old.id;` + insertFacets(sources, "") + `
+		END`,
+		`CREATE TRIGGER games_tags_update AFTER UPDATE OF parsed_tags ON games
+		WHEN new.parsed_tags IS NOT old.parsed_tags BEGIN
+			DELETE FROM game_facets WHERE game_id = old.id AND facet = '` + string(FacetLanguage) + `';` +
+			insertFacets(languageSources, "") + `
+		END`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
 }
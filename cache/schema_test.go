@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"grout/romm"
+)
+
+// openVersion1 returns a database migrated only as far as version 1, holding games as that
+// version stored them.
+func openVersion1(t *testing.T, path string, games []romm.Rom) *sql.DB {
+	t.Helper()
+
+	db, err := openDB(path)
+	if err != nil {
+		t.Fatalf("openDB() error = %v", err)
+	}
+
+	all := migrations
+	migrations = migrations[:1]
+	err = migrate(db)
+	migrations = all
+	if err != nil {
+		t.Fatalf("migrate() to version 1 error = %v", err)
+	}
+
+	for _, game := range games {
+		dataJSON, err := json.Marshal(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec(`INSERT INTO games (id, platform_id, platform_fs_slug, name, fs_name, data_json) VALUES (?, ?, 'gb', ?, ?, ?)`,
+			game.ID, game.PlatformID, game.Name, game.FsName, string(dataJSON))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return db
+}
+
+func TestMigrateFromVersion1(t *testing.T) {
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 1, Name: "Tetris", FsName: "Tetris (World).gb", FsSizeBytes: 32768,
+			Metadatum: romm.RomMetadata{Genres: []string{"Puzzle"}, FirstReleaseDate: 613008000000, AverageRating: 88}},
+		{ID: 2, PlatformID: 1, Name: "Dr. Mario", FsName: "Dr. Mario (World).gb",
+			Metadatum: romm.RomMetadata{Genres: []string{"Puzzle"}, AverageRating: 75}},
+	}
+
+	db := openVersion1(t, filepath.Join(t.TempDir(), "cache.db"), games)
+	defer db.Close()
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+
+	version, err := readSchemaVersion(db)
+	if err != nil || version != schemaVersion {
+		t.Fatalf("readSchemaVersion() = %d, %v, want %d", version, err, schemaVersion)
+	}
+
+	// Games stored before the upgrade are kept and in the search, facet and sort indexes
+	cm := &Manager{db: db, initialized: true, stats: &CacheStats{}}
+
+	stored, err := cm.GetGamesByIDs([]int{1, 2})
+	if err != nil || len(stored) != len(games) {
+		t.Errorf("GetGamesByIDs() = %d games, %v, want %d", len(stored), err, len(games))
+	}
+
+	ids, err := cm.SearchGameIDs("tetris", 0, 0)
+	if err != nil || !reflect.DeepEqual(ids, []int{1}) {
+		t.Errorf("SearchGameIDs(tetris) = %v, %v, want [1]", ids, err)
+	}
+
+	facets, err := cm.GetFacets([]int{1, 2}, nil)
+	if err != nil {
+		t.Fatalf("GetFacets() error = %v", err)
+	}
+	if got, want := facets[FacetGenre], []FacetValue{{Value: "Puzzle", Count: 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("genre facet = %v, want %v", got, want)
+	}
+
+	ids, err = cm.SortGameIDs([]int{2, 1}, Sort{Field: SortRating, Descending: true})
+	if err != nil || !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("SortGameIDs(rating) = %v, %v, want [1 2]", ids, err)
+	}
+
+	// Running it again has nothing left to do
+	if err := migrate(db); err != nil {
+		t.Errorf("migrate() on a current database error = %v", err)
+	}
+}
+
+func TestMigrateMovesParsedTags(t *testing.T) {
+	// Versions before 5 stored the parsed tags in data_json
+	tags := romm.ParseTags("Alpha (Japan) (Ja).zip")
+	game := romm.Rom{ID: 1, PlatformID: 1, Name: "Alpha", FsName: "Alpha (Japan) (Ja).zip", ParsedTags: &tags}
+
+	db := openVersion1(t, filepath.Join(t.TempDir(), "cache.db"), []romm.Rom{game})
+	defer db.Close()
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+
+	cm := &Manager{db: db, initialized: true, stats: &CacheStats{}}
+
+	stored, err := cm.GetGamesByIDs([]int{1})
+	if err != nil || len(stored) != 1 || stored[0].ParsedTags == nil {
+		t.Fatalf("GetGamesByIDs() = %+v, %v, want the game with its parsed tags", stored, err)
+	}
+	if !reflect.DeepEqual(*stored[0].ParsedTags, tags) {
+		t.Errorf("ParsedTags = %+v, want %+v", *stored[0].ParsedTags, tags)
+	}
+
+	facets, err := cm.GetFacets([]int{1}, nil)
+	if err != nil {
+		t.Fatalf("GetFacets() error = %v", err)
+	}
+	if got, want := facets[FacetLanguage], []FacetValue{{Value: "Japanese", Count: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("language facet = %v, want %v", got, want)
+	}
+}
+
+func TestMigrationFailureRecreatesCache(t *testing.T) {
+	t.Chdir(t.TempDir())
+	hostNamespace.Store("test")
+	t.Cleanup(func() { hostNamespace.Store("") })
+
+	dbPath := getCacheDBPath()
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	db := openVersion1(t, dbPath, []romm.Rom{{ID: 1, PlatformID: 1, Name: "Tetris"}})
+	if _, err := db.Exec(`UPDATE cache_metadata SET value = 'broken' WHERE key = 'schema_version'`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	cm, err := newCacheManager(romm.Host{}, nil)
+	if err != nil {
+		t.Fatalf("newCacheManager() error = %v", err)
+	}
+	defer cm.Close()
+
+	if !cm.Recovered() {
+		t.Error("Recovered() = false for a cache that couldn't be migrated")
+	}
+	if version, err := readSchemaVersion(cm.db); err != nil || version != schemaVersion {
+		t.Errorf("readSchemaVersion() = %d, %v, want %d", version, err, schemaVersion)
+	}
+	if !cm.IsFirstRun() {
+		t.Error("IsFirstRun() = false, the rebuilt cache should start empty")
+	}
+}
+
+func TestNewerCacheIsKept(t *testing.T) {
+	t.Chdir(t.TempDir())
+	hostNamespace.Store("test")
+	t.Cleanup(func() { hostNamespace.Store("") })
+
+	dbPath := getCacheDBPath()
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	db := openVersion1(t, dbPath, []romm.Rom{{ID: 1, PlatformID: 1, Name: "Tetris"}})
+	newer := strconv.Itoa(schemaVersion + 1)
+	if _, err := db.Exec(`UPDATE cache_metadata SET value = ? WHERE key = 'schema_version'`, newer); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if _, err := newCacheManager(romm.Host{}, nil); !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("newCacheManager() error = %v, want ErrSchemaTooNew", err)
+	}
+
+	// The newer Grout's cache is still there, games and all
+	db, err := openDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM games`).Scan(&count); err != nil || count != 1 {
+		t.Errorf("games left = %d, %v, want 1", count, err)
+	}
+	if version, err := readSchemaVersion(db); err != nil || strconv.Itoa(version) != newer {
+		t.Errorf("readSchemaVersion() = %d, %v, want %s", version, err, newer)
+	}
+}
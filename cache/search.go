@@ -0,0 +1,233 @@
+package cache
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"grout/romm"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// minTrigramScore is the share of the query's trigrams a name needs for a typo match.
+const minTrigramScore = 1.0 / 3
+
+// maxTrigramCandidates caps how many names are scored for typo matches.
+const maxTrigramCandidates = 200
+
+// SearchGames finds cached games matching query, best match first. See SearchGameIDs for how
+// games match.
+func (cm *Manager) SearchGames(query string, platformID int, limit int) ([]romm.Rom, error) {
+	ids, err := cm.SearchGameIDs(query, platformID, limit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	games, err := cm.GetGamesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[int]int, len(ids))
+	for i, id := range ids {
+		rank[id] = i
+	}
+	slices.SortFunc(games, func(a, b romm.Rom) int {
+		return cmp.Compare(rank[a.ID], rank[b.ID])
+	})
+
+	return games, nil
+}
+
+// SearchGameIDs returns the IDs of cached games matching query, best match first, limited to
+// one platform unless platformID is 0 and to limit results unless limit is 0. Every word of
+// the query has to start a word of the game's name, file name, alternative names, summary,
+// genres or companies, with matches in the name ranking highest. When that finds nothing,
+// names sharing enough three letter runs with the query are returned instead, which catches
+// typos.
+func (cm *Manager) SearchGameIDs(query string, platformID int, limit int) ([]int, error) {
+	if cm == nil || !cm.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	words := searchWords(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	ids, err := cm.matchWords(words, platformID, limit)
+	if err != nil {
+		cm.stats.recordError()
+		return nil, newCacheError("search", "games", query, err)
+	}
+
+	if len(ids) == 0 {
+		ids, err = cm.matchTrigrams(words, platformID, limit)
+		if err != nil {
+			cm.stats.recordError()
+			return nil, newCacheError("search", "games", query, err)
+		}
+	}
+
+	if len(ids) > 0 {
+		cm.stats.recordHit()
+	} else {
+		cm.stats.recordMiss()
+	}
+
+	return ids, nil
+}
+
+func (cm *Manager) matchWords(words []string, platformID int, limit int) ([]int, error) {
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = quoteFTS(word) + "*"
+	}
+
+	rows, err := cm.db.Query(`
+		SELECT g.id FROM games_fts f
+		INNER JOIN games g ON g.id = f.rowid
+		WHERE games_fts MATCH ? AND (? = 0 OR g.platform_id = ?)
+		ORDER BY bm25(games_fts, 10.0, 6.0, 4.0, 1.0, 2.0, 2.0), g.name
+		LIMIT ?
+	`, strings.Join(terms, " "), platformID, platformID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (cm *Manager) matchTrigrams(words []string, platformID int, limit int) ([]int, error) {
+	trigrams := queryTrigrams(words)
+	if len(trigrams) == 0 {
+		return nil, nil
+	}
+
+	terms := make([]string, len(trigrams))
+	for i, trigram := range trigrams {
+		terms[i] = quoteFTS(trigram)
+	}
+
+	rows, err := cm.db.Query(`
+		SELECT g.id, g.name, g.data_json FROM games_trigram t
+		INNER JOIN games g ON g.id = t.rowid
+		WHERE games_trigram MATCH ? AND (? = 0 OR g.platform_id = ?)
+		ORDER BY bm25(games_trigram)
+		LIMIT ?
+	`, strings.Join(terms, " OR "), platformID, platformID, maxTrigramCandidates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type candidate struct {
+		id    int
+		score float64
+	}
+
+	var candidates []candidate
+	for rows.Next() {
+		var id int
+		var name, dataJSON string
+		if err := rows.Scan(&id, &name, &dataJSON); err != nil {
+			return nil, err
+		}
+
+		var game romm.Rom
+		if err := json.Unmarshal([]byte(dataJSON), &game); err != nil {
+			return nil, err
+		}
+
+		text := foldText(strings.Join(append([]string{name}, game.AlternativeNames...), " "))
+		matched := 0
+		for _, trigram := range trigrams {
+			if strings.Contains(text, trigram) {
+				matched++
+			}
+		}
+
+		if score := float64(matched) / float64(len(trigrams)); score >= minTrigramScore {
+			candidates = append(candidates, candidate{id: id, score: score})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Stable, so equal scores keep the order bm25 gave them
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	ids := make([]int, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.id
+	}
+	return ids, nil
+}
+
+// searchWords splits the query into lower case words without accents.
+func searchWords(query string) []string {
+	return strings.FieldsFunc(foldText(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// queryTrigrams returns the distinct three letter runs of every word.
+func queryTrigrams(words []string) []string {
+	var trigrams []string
+	for _, word := range words {
+		runes := []rune(word)
+		for i := 0; i+3 <= len(runes); i++ {
+			if trigram := string(runes[i : i+3]); !slices.Contains(trigrams, trigram) {
+				trigrams = append(trigrams, trigram)
+			}
+		}
+	}
+	return trigrams
+}
+
+// foldText lower cases text and drops accents from Latin letters, the way the indexes do.
+func foldText(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if folded, ok := accentFolds[r]; ok {
+			r = folded
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var accentFolds = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c', 'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u', 'ý': 'y', 'ÿ': 'y',
+}
+
+// quoteFTS makes s a literal FTS5 string, so quotes and operators in a query aren't parsed.
+func quoteFTS(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+}
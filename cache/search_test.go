@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"grout/romm"
+)
+
+func newSearchManager(t *testing.T) *Manager {
+	t.Helper()
+
+	cm := newTestManager(t)
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 1, Name: "The Legend of Zelda", FsName: "Legend of Zelda, The (USA).nes"},
+		{ID: 2, PlatformID: 1, Name: "Metroid", FsName: "Metroid (USA).nes", Summary: "Samus explores planet Zebes."},
+		{ID: 3, PlatformID: 2, Name: "Pokémon Red", FsName: "Pokemon - Red Version (USA).gb",
+			AlternativeNames: []string{"Pocket Monsters Aka"}},
+		{ID: 4, PlatformID: 2, Name: "Tetris", FsName: "Tetris (World).gb"},
+	}
+	if err := cm.SavePlatformGames(1, games[:2]); err != nil {
+		t.Fatalf("SavePlatformGames(1) error = %v", err)
+	}
+	if err := cm.SavePlatformGames(2, games[2:]); err != nil {
+		t.Fatalf("SavePlatformGames(2) error = %v", err)
+	}
+	return cm
+}
+
+func TestSearchGameIDs(t *testing.T) {
+	cm := newSearchManager(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		platformID int
+		want       []int
+	}{
+		{"whole word", "zelda", 0, []int{1}},
+		{"prefix", "met", 0, []int{2}},
+		{"every word", "legend zelda", 0, []int{1}},
+		{"accented name", "pokemon", 0, []int{3}},
+		{"accented query", "Pokémon", 0, []int{3}},
+		{"alternative name", "pocket", 0, []int{3}},
+		{"summary", "zebes", 0, []int{2}},
+		{"typo", "tetrix", 0, []int{4}},
+		{"typo in accented name", "pokemn", 0, []int{3}},
+		{"other platform", "zelda", 2, nil},
+		{"quotes are literal", `"zelda"`, 0, []int{1}},
+		{"no words", "  -  ", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cm.SearchGameIDs(tt.query, tt.platformID, 0)
+			if err != nil {
+				t.Fatalf("SearchGameIDs(%q) error = %v", tt.query, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SearchGameIDs(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchGameIDsRanksNameFirst(t *testing.T) {
+	cm := newTestManager(t)
+
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 1, Name: "Super Mario Kart", Summary: "Race as Mario and friends."},
+		{ID: 2, PlatformID: 1, Name: "Yoshi's Island", Summary: "Carry baby Mario to safety."},
+		{ID: 3, PlatformID: 1, Name: "Mario Paint"},
+	}
+	if err := cm.SavePlatformGames(1, games); err != nil {
+		t.Fatalf("SavePlatformGames() error = %v", err)
+	}
+
+	got, err := cm.SearchGameIDs("mario", 0, 0)
+	if err != nil {
+		t.Fatalf("SearchGameIDs() error = %v", err)
+	}
+	if len(got) != 3 || got[2] != 2 {
+		t.Errorf("SearchGameIDs(mario) = %v, want the summary match last", got)
+	}
+
+	got, err = cm.SearchGameIDs("mario", 0, 1)
+	if err != nil || len(got) != 1 {
+		t.Errorf("SearchGameIDs(mario) with limit 1 = %v, %v", got, err)
+	}
+}
+
+func TestSearchAfterResave(t *testing.T) {
+	cm := newSearchManager(t)
+
+	// Saving a platform again replaces its games, the triggers keep both indexes in step
+	renamed := []romm.Rom{
+		{ID: 1, PlatformID: 1, Name: "Zelda II: The Adventure of Link", FsName: "Zelda II - The Adventure of Link (USA).nes"},
+		{ID: 5, PlatformID: 1, Name: "Kid Icarus", FsName: "Kid Icarus (USA).nes"},
+	}
+	if err := cm.SavePlatformGames(1, renamed); err != nil {
+		t.Fatalf("SavePlatformGames() error = %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"adventure", []int{1}},
+		{"legend", nil},
+		{"metroid", nil},
+		{"zebes", nil},
+		{"metrod", nil},
+		{"icarus", []int{5}},
+		{"icrus", []int{5}},
+		{"tetris", []int{4}},
+	}
+	for _, tt := range tests {
+		got, err := cm.SearchGameIDs(tt.query, 0, 0)
+		if err != nil {
+			t.Fatalf("SearchGameIDs(%q) error = %v", tt.query, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SearchGameIDs(%q) after re-save = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	// Updating a row in place reindexes it too
+	if _, err := cm.db.Exec(`UPDATE games SET name = 'Kid Icarus: Of Myths and Monsters' WHERE id = 5`); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := cm.SearchGameIDs("myths", 0, 0); err != nil || !reflect.DeepEqual(got, []int{5}) {
+		t.Errorf("SearchGameIDs(myths) after update = %v, %v, want [5]", got, err)
+	}
+}
+
+func TestSearchWords(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Pokémon Red", []string{"pokemon", "red"}},
+		{"  Zelda: Link's  ", []string{"zelda", "link", "s"}},
+		{"F-Zero X", []string{"f", "zero", "x"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := searchWords(tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("searchWords(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
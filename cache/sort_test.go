@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"grout/romm"
+)
+
+func TestSortGameIDs(t *testing.T) {
+	cm := newTestManager(t)
+
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 1, Name: "alpha", FsSizeBytes: 100, CreatedAt: day(2024, 1, 1), UpdatedAt: day(2024, 3, 1),
+			Metadatum: romm.RomMetadata{FirstReleaseDate: releasedIn(1990), AverageRating: 80}},
+		{ID: 2, PlatformID: 1, Name: "Bravo", FsSizeBytes: 300, CreatedAt: day(2024, 2, 1),
+			Metadatum: romm.RomMetadata{FirstReleaseDate: releasedIn(1985)}},
+		{ID: 3, PlatformID: 1, Name: "charlie", FsSizeBytes: 200, UpdatedAt: day(2024, 1, 15),
+			Metadatum: romm.RomMetadata{AverageRating: 95}},
+		{ID: 4, PlatformID: 1, Name: "Delta", CreatedAt: day(2023, 12, 1),
+			Metadatum: romm.RomMetadata{FirstReleaseDate: releasedIn(1985), AverageRating: 80}},
+	}
+	if err := cm.SavePlatformGames(1, games); err != nil {
+		t.Fatalf("SavePlatformGames() error = %v", err)
+	}
+
+	// 99 isn't cached, so it has no value for any field
+	gameIDs := []int{99, 4, 3, 2, 1}
+
+	tests := []struct {
+		name string
+		sort Sort
+		want []int
+	}{
+		{"name ignores case", Sort{Field: SortName}, []int{1, 2, 3, 4, 99}},
+		{"name descending", Sort{Field: SortName, Descending: true}, []int{4, 3, 2, 1, 99}},
+		{"release date, ties by name", Sort{Field: SortReleaseDate}, []int{2, 4, 1, 3, 99}},
+		{"release date descending", Sort{Field: SortReleaseDate, Descending: true}, []int{1, 2, 4, 3, 99}},
+		{"rating", Sort{Field: SortRating}, []int{1, 4, 3, 2, 99}},
+		{"rating descending", Sort{Field: SortRating, Descending: true}, []int{3, 1, 4, 2, 99}},
+		{"size", Sort{Field: SortSize}, []int{1, 3, 2, 4, 99}},
+		{"added descending", Sort{Field: SortAdded, Descending: true}, []int{2, 1, 4, 3, 99}},
+		{"updated", Sort{Field: SortUpdated}, []int{3, 1, 2, 4, 99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cm.SortGameIDs(gameIDs, tt.sort)
+			if err != nil {
+				t.Fatalf("SortGameIDs() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortGameIDs(%+v) = %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty list", func(t *testing.T) {
+		got, err := cm.SortGameIDs(nil, Sort{Field: SortRating})
+		if err != nil || len(got) != 0 {
+			t.Errorf("SortGameIDs(nil) = %v, %v, want none", got, err)
+		}
+	})
+
+	t.Run("field the cache doesn't have", func(t *testing.T) {
+		if _, err := cm.SortGameIDs(gameIDs, Sort{Field: SortDownloaded}); err == nil {
+			t.Error("SortGameIDs() by download date error = nil, want an error")
+		}
+	})
+}
+
+func TestSortIsDefault(t *testing.T) {
+	tests := []struct {
+		sort Sort
+		want bool
+	}{
+		{Sort{}, true},
+		{Sort{Field: SortName}, true},
+		{Sort{Field: SortName, Descending: true}, false},
+		{Sort{Field: SortRating}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.sort.IsDefault(); got != tt.want {
+			t.Errorf("%+v.IsDefault() = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
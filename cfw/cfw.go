@@ -214,6 +214,54 @@ func BaseSavePath() string {
 	return ""
 }
 
+// BaseStatePath returns the directory emulator state folders live under.
+// Knulli keeps states next to the saves, so it shares BaseSavePath.
+func BaseStatePath() string {
+	cfw := GetCFW()
+	switch cfw {
+	case MuOS:
+		return filepath.Join(getBasePath(cfw), "MUOS", "save", "state")
+	case NextUI:
+		return filepath.Join(getBasePath(cfw), ".userdata", "shared")
+	case Knulli:
+		return filepath.Join(getBasePath(cfw), "saves")
+	case Spruce:
+		return filepath.Join(getBasePath(cfw), "Saves", "states")
+	}
+
+	return ""
+}
+
+// StateFoldersForFSSlug returns the folders under BaseStatePath that may hold states for fsSlug.
+// NextUI names state folders after the platform tag and the core, e.g. "GBA-gpsp", so they are
+// discovered on disk. The other CFWs use the same emulator folders for saves and states.
+func StateFoldersForFSSlug(fsSlug string) []string {
+	emulatorFolders := EmulatorFoldersForFSSlug(fsSlug)
+	if GetCFW() != NextUI {
+		return emulatorFolders
+	}
+
+	entries, err := os.ReadDir(BaseStatePath())
+	if err != nil {
+		return nil
+	}
+
+	var folders []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		for _, tag := range emulatorFolders {
+			if strings.HasPrefix(entry.Name(), tag+"-") {
+				folders = append(folders, entry.Name())
+				break
+			}
+		}
+	}
+
+	return folders
+}
+
 // GetPlatformRomDirectory returns the ROM directory for a platform.
 // relativePath is the configured relative path from directory mappings.
 // platformFSSlug is used as fallback if relativePath is empty.
@@ -245,6 +293,26 @@ func GetArtDirectory(romDir string, platformFSSlug, platformName string) string
 	}
 }
 
+// GetManualDirectory returns the folder game manuals for a platform are kept in. Knulli follows
+// the EmulationStation "manuals" convention, muOS keeps them with the rest of its catalogue and
+// the others get a hidden folder so manuals don't show up as games.
+func GetManualDirectory(romDir string, platformFSSlug, platformName string) string {
+	switch GetCFW() {
+	case NextUI, Spruce:
+		return filepath.Join(romDir, ".manuals")
+	case Knulli:
+		return filepath.Join(romDir, "manuals")
+	case MuOS:
+		systemName, exists := MuOSArtDirectory[platformFSSlug]
+		if !exists {
+			systemName = platformName
+		}
+		return filepath.Join(GetMuOSInfoDirectory(), "catalogue", systemName, "manual")
+	default:
+		return ""
+	}
+}
+
 // RomFolderBase returns the base folder name for ROM matching.
 // tagParser is a function that extracts tags from paths (for NextUI).
 func RomFolderBase(path string, tagParser func(string) string) string {
@@ -0,0 +1,111 @@
+package connectivity
+
+import (
+	"errors"
+	"grout/romm"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+const offlineIcon = "\U000F0164"
+
+// probeInterval is how often an unreachable host is tried again.
+const probeInterval = 30 * time.Second
+
+// ErrOffline is returned for work that needs RomM while it can't be reached.
+var ErrOffline = errors.New("RomM can't be reached")
+
+var offline atomic.Bool
+
+// IsOffline reports whether Grout is running from its cache because RomM can't be reached.
+func IsOffline() bool {
+	return offline.Load()
+}
+
+// Monitor keeps track of whether the active host can be reached. While it can't, the host is
+// tried again in the background and the status bar shows Grout is offline.
+type Monitor struct {
+	mu        sync.Mutex
+	host      romm.Host
+	timeout   time.Duration
+	icon      *gaba.DynamicStatusBarIcon
+	probing   atomic.Bool
+	reachable atomic.Bool
+}
+
+func NewMonitor(timeout time.Duration) *Monitor {
+	return &Monitor{
+		timeout: timeout,
+		icon:    gaba.NewDynamicStatusBarIcon(""), // Start empty, only shown while offline
+	}
+}
+
+func (m *Monitor) Icon() gaba.StatusBarIcon {
+	return gaba.StatusBarIcon{
+		Dynamic: m.icon,
+	}
+}
+
+// GoOffline switches to offline mode for host and starts waiting for it to come back.
+func (m *Monitor) GoOffline(host romm.Host) {
+	m.mu.Lock()
+	m.host = host
+	m.mu.Unlock()
+
+	m.reachable.Store(false)
+	offline.Store(true)
+	m.icon.SetText(offlineIcon)
+
+	if m.probing.CompareAndSwap(false, true) {
+		go m.run()
+	}
+}
+
+// SetOnline leaves offline mode, for when the host was reached some other way.
+func (m *Monitor) SetOnline() {
+	offline.Store(false)
+	m.icon.SetText("")
+}
+
+// Resumed reports whether the host came back since offline mode was entered. It reports it
+// only once, so the caller picks up the work left for later a single time.
+func (m *Monitor) Resumed() bool {
+	return m.reachable.Swap(false)
+}
+
+func (m *Monitor) run() {
+	logger := gaba.GetLogger()
+	defer func() {
+		m.probing.Store(false)
+		// GoOffline may have been called again while this loop was on its way out
+		if offline.Load() && m.probing.CompareAndSwap(false, true) {
+			go m.run()
+		}
+	}()
+
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !offline.Load() {
+			return
+		}
+
+		m.mu.Lock()
+		host := m.host
+		m.mu.Unlock()
+
+		if _, err := romm.NewClientFromHost(host, m.timeout).GetHeartbeat(); err != nil {
+			logger.Debug("Connectivity: RomM still unreachable", "host", host.URL(), "error", err)
+			continue
+		}
+
+		logger.Info("Connectivity: RomM reachable again", "host", host.URL())
+		m.SetOnline()
+		m.reachable.Store(true)
+		return
+	}
+}
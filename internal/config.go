@@ -6,6 +6,8 @@ import (
 	"grout/cfw"
 	"grout/romm"
 	"os"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,19 +17,39 @@ import (
 
 var kidModeEnabled atomic.Bool
 
+// configFileMu serializes writes of config.json, which token refreshes make from background goroutines.
+var configFileMu sync.Mutex
+
+// DefaultRegionPriority is the order regions and languages are preferred in until the user
+// sets their own. Values match the regions and languages RomM reports for ROMs.
+var DefaultRegionPriority = []string{
+	"World", "USA", "Europe", "English", "Japan", "Australia", "Canada", "Asia", "Brazil", "Korea", "China",
+	"France", "Germany", "Spain", "Italy", "Netherlands", "Sweden", "French", "German", "Spanish", "Italian",
+	"Portuguese", "Dutch", "Swedish", "Japanese", "Korean", "Chinese",
+}
+
 type Config struct {
 	Hosts                  []romm.Host                 `json:"hosts,omitempty"`
+	ActiveHost             string                      `json:"active_host,omitempty"`
 	DirectoryMappings      map[string]DirectoryMapping `json:"directory_mappings,omitempty"`
 	SaveSyncMode           string                      `json:"save_sync_mode"`
+	SaveSyncContent        string                      `json:"save_sync_content,omitempty"`
 	SaveDirectoryMappings  map[string]string           `json:"save_directory_mappings,omitempty"`
 	GameSaveOverrides      map[int]string              `json:"game_save_overrides,omitempty"`
 	DownloadArt            bool                        `json:"download_art,omitempty"`
+	DownloadManuals        string                      `json:"download_manuals,omitempty"`
 	ShowBoxArt             bool                        `json:"show_box_art,omitempty"`
 	UnzipDownloads         bool                        `json:"unzip_downloads,omitempty"`
 	ShowRegularCollections bool                        `json:"show_collections"`
 	ShowSmartCollections   bool                        `json:"show_smart_collections"`
 	ShowVirtualCollections bool                        `json:"show_virtual_collections"`
 	DownloadedGames        string                      `json:"downloaded_games,omitempty"`
+	GroupVariants          bool                        `json:"group_variants,omitempty"`
+	RegionPriority         []string                    `json:"region_priority,omitempty"`
+	HideBetas              bool                        `json:"hide_betas,omitempty"`
+	HidePrototypes         bool                        `json:"hide_prototypes,omitempty"`
+	HideHacks              bool                        `json:"hide_hacks,omitempty"`
+	HideUnlicensed         bool                        `json:"hide_unlicensed,omitempty"`
 	ApiTimeout             time.Duration               `json:"api_timeout"`
 	DownloadTimeout        time.Duration               `json:"download_timeout"`
 	LogLevel               string                      `json:"log_level,omitempty"`
@@ -51,18 +73,27 @@ func (c Config) ToLoggable() any {
 
 	return map[string]any{
 		"hosts":                   safeHosts,
+		"active_host":             c.ActiveHost,
 		"directory_mappings":      c.DirectoryMappings,
 		"api_timeout":             c.ApiTimeout,
 		"download_timeout":        c.DownloadTimeout,
 		"unzip_downloads":         c.UnzipDownloads,
 		"download_art":            c.DownloadArt,
+		"download_manuals":        c.DownloadManuals,
 		"show_box_art":            c.ShowBoxArt,
+		"save_sync_content":       c.SaveSyncContent,
 		"save_directory_mappings": c.SaveDirectoryMappings,
 		"game_save_overrides":     c.GameSaveOverrides,
 		"collections":             c.ShowRegularCollections,
 		"smart_collections":       c.ShowSmartCollections,
 		"virtual_collections":     c.ShowVirtualCollections,
 		"downloaded_games_action": c.DownloadedGames,
+		"group_variants":          c.GroupVariants,
+		"region_priority":         c.RegionPriority,
+		"hide_betas":              c.HideBetas,
+		"hide_prototypes":         c.HidePrototypes,
+		"hide_hacks":              c.HideHacks,
+		"hide_unlicensed":         c.HideUnlicensed,
 		"log_level":               c.LogLevel,
 	}
 }
@@ -102,6 +133,10 @@ func LoadConfig() (*Config, error) {
 		config.SaveSyncMode = "off"
 	}
 
+	if config.SaveSyncContent == "" {
+		config.SaveSyncContent = "saves"
+	}
+
 	return &config, nil
 }
 
@@ -126,6 +161,10 @@ func SaveConfig(config *Config) error {
 		config.SaveSyncMode = "off"
 	}
 
+	if config.SaveSyncContent == "" {
+		config.SaveSyncContent = "saves"
+	}
+
 	gaba.SetRawLogLevel(config.LogLevel)
 
 	if err := i18n.SetWithCode(config.Language); err != nil {
@@ -138,6 +177,9 @@ func SaveConfig(config *Config) error {
 		return err
 	}
 
+	configFileMu.Lock()
+	defer configFileMu.Unlock()
+
 	if err := os.WriteFile("config.json", pretty, 0644); err != nil {
 		gaba.GetLogger().Error("Failed to write config file", "error", err)
 		return err
@@ -146,6 +188,117 @@ func SaveConfig(config *Config) error {
 	return nil
 }
 
+// SaveHostToken writes the token of the host with hostKey to config.json and leaves the rest of
+// the file as it is. Tokens are refreshed on whichever goroutine needed them, so this reads the
+// saved config instead of the one the UI is changing, and doesn't apply any of its settings.
+func SaveHostToken(hostKey string, token *romm.Token) error {
+	configFileMu.Lock()
+	defer configFileMu.Unlock()
+
+	data, err := os.ReadFile("config.json")
+	if err != nil {
+		return fmt.Errorf("reading config.json: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("parsing config.json: %w", err)
+	}
+
+	i := slices.IndexFunc(config.Hosts, func(host romm.Host) bool { return host.Key() == hostKey })
+	if i < 0 {
+		// The host was removed or hasn't been saved yet, its token goes with it
+		return nil
+	}
+	config.Hosts[i].Token = token
+
+	pretty, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling config: %w", err)
+	}
+	if err := os.WriteFile("config.json", pretty, 0644); err != nil {
+		return fmt.Errorf("writing config.json: %w", err)
+	}
+	return nil
+}
+
+// ActiveHostIndex returns the position in Hosts of the server Grout is using. Configs written
+// before hosts could be switched have no ActiveHost and use the first one.
+func (c Config) ActiveHostIndex() int {
+	for i, host := range c.Hosts {
+		if host.Key() == c.ActiveHost {
+			return i
+		}
+	}
+	return 0
+}
+
+// CurrentHost returns the active host. Hosts must not be empty.
+func (c *Config) CurrentHost() *romm.Host {
+	return &c.Hosts[c.ActiveHostIndex()]
+}
+
+// AddHost stores host, replacing the entry for the same login if there is one, and makes it active.
+func (c *Config) AddHost(host romm.Host) {
+	c.ActiveHost = host.Key()
+	for i := range c.Hosts {
+		if c.Hosts[i].Key() == host.Key() {
+			c.Hosts[i] = host
+			return
+		}
+	}
+	c.Hosts = append(c.Hosts, host)
+}
+
+// RemoveHost drops the host with the given key. When it was active the first remaining host takes over.
+func (c *Config) RemoveHost(key string) {
+	c.Hosts = slices.DeleteFunc(c.Hosts, func(h romm.Host) bool {
+		return h.Key() == key
+	})
+	if c.ActiveHost == key {
+		c.ActiveHost = ""
+		if len(c.Hosts) > 0 {
+			c.ActiveHost = c.Hosts[0].Key()
+		}
+	}
+}
+
+// SyncsSaves reports whether save sync includes battery saves.
+func (c Config) SyncsSaves() bool {
+	return c.SaveSyncContent != "states"
+}
+
+// SyncsStates reports whether save sync includes save states.
+func (c Config) SyncsStates() bool {
+	return c.SaveSyncContent == "states" || c.SaveSyncContent == "both"
+}
+
+// RegionPreferences returns the user's region and language priority, best first.
+func (c Config) RegionPreferences() []string {
+	if len(c.RegionPriority) == 0 {
+		return DefaultRegionPriority
+	}
+	return c.RegionPriority
+}
+
+// HidesRoms reports whether any of the beta, prototype, hack or unlicensed filters is on.
+// Kid mode turns all of them on.
+func (c Config) HidesRoms() bool {
+	return c.HideBetas || c.HidePrototypes || c.HideHacks || c.HideUnlicensed || IsKidModeEnabled()
+}
+
+// HidesRom reports whether the ROM is left out of game lists by the beta, prototype, hack and
+// unlicensed filters.
+func (c Config) HidesRom(rom romm.Rom) bool {
+	kidMode := IsKidModeEnabled()
+	tags := rom.ReleaseTags()
+
+	return (c.HideBetas || kidMode) && tags.Beta ||
+		(c.HidePrototypes || kidMode) && tags.Proto ||
+		(c.HideHacks || kidMode) && tags.Hack ||
+		(c.HideUnlicensed || kidMode) && (tags.Unlicensed || tags.Pirate)
+}
+
 // SortPlatformsByOrder sorts platforms based on the saved order in config.
 // If no order is saved, platforms are sorted alphabetically.
 func SortPlatformsByOrder(platforms []romm.Platform, order []string) []romm.Platform {
@@ -269,12 +422,28 @@ func (c Config) GetArtDirectory(platform romm.Platform) string {
 	return cfw.GetArtDirectory(romDir, platform.FSSlug, platform.Name)
 }
 
+// GetManualDirectory returns where manuals for platform are stored: next to the ROMs when
+// DownloadManuals is "rom_folder", otherwise the CFW's manual folder.
+func (c Config) GetManualDirectory(platform romm.Platform) string {
+	romDir := c.GetPlatformRomDirectory(platform)
+	if c.DownloadManuals == "rom_folder" {
+		return romDir
+	}
+	return cfw.GetManualDirectory(romDir, platform.FSSlug, platform.Name)
+}
+
+// ShouldDownloadManuals reports whether manuals are fetched along with their games.
+func (c Config) ShouldDownloadManuals() bool {
+	return c.DownloadManuals != "" && c.DownloadManuals != "off"
+}
+
 func (c Config) ShowCollections(host romm.Host) bool {
 	if !c.ShowRegularCollections && !c.ShowSmartCollections && !c.ShowVirtualCollections {
 		return false
 	}
 
 	rc := romm.NewClientFromHost(host, c.ApiTimeout)
+	capabilities := host.Capabilities()
 
 	if c.ShowRegularCollections {
 		col, err := rc.GetCollections()
@@ -283,14 +452,14 @@ func (c Config) ShowCollections(host romm.Host) bool {
 		}
 	}
 
-	if c.ShowSmartCollections {
+	if c.ShowSmartCollections && capabilities.Has(romm.CapabilitySmartCollections) {
 		smartCol, err := rc.GetSmartCollections()
 		if err == nil && len(smartCol) > 0 {
 			return true
 		}
 	}
 
-	if c.ShowVirtualCollections {
+	if c.ShowVirtualCollections && capabilities.Has(romm.CapabilityVirtualCollections) {
 		virtualCol, err := rc.GetVirtualCollections()
 		if err == nil && len(virtualCol) > 0 {
 			return true
@@ -19,8 +19,15 @@ const (
 	ExitCodeGameOptions              gaba.ExitCode = 113
 	ExitCodeGeneralSettings          gaba.ExitCode = 114
 	ExitCodeCheckUpdate              gaba.ExitCode = 115
+	ExitCodeUnknownRoms              gaba.ExitCode = 116
+	ExitCodeCollectionMembership     gaba.ExitCode = 117
+	ExitCodeManageCollections        gaba.ExitCode = 118
+	ExitCodeServers                  gaba.ExitCode = 119
+	ExitCodeRegionPriority           gaba.ExitCode = 120
+	ExitCodeListOptions              gaba.ExitCode = 121
 	ExitCodeSearch                   gaba.ExitCode = 200
 	ExitCodeClearSearch              gaba.ExitCode = 201
+	ExitCodeSearchAll                gaba.ExitCode = 202
 	ExitCodeCollections              gaba.ExitCode = 300
 	ExitCodeBackToCollection         gaba.ExitCode = 301
 	ExitCodeBackToCollectionPlatform gaba.ExitCode = 302
@@ -0,0 +1,158 @@
+package fileutil
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// followInterval is how long FollowChecksums waits for more data before reading again.
+const followInterval = 100 * time.Millisecond
+
+// Checksums holds hex-encoded digests of a file. Empty fields are unknown.
+type Checksums struct {
+	CRC  string
+	MD5  string
+	SHA1 string
+}
+
+func (c Checksums) IsEmpty() bool {
+	return c.CRC == "" && c.MD5 == "" && c.SHA1 == ""
+}
+
+// Matches reports whether every digest known to both sides agrees. Nothing to compare counts as a match.
+func (c Checksums) Matches(expected Checksums) bool {
+	return digestMatches(c.CRC, expected.CRC) &&
+		digestMatches(c.MD5, expected.MD5) &&
+		digestMatches(c.SHA1, expected.SHA1)
+}
+
+func digestMatches(actual, expected string) bool {
+	if actual == "" || expected == "" {
+		return true
+	}
+	// RomM drops leading zeros from some CRCs
+	return strings.EqualFold(strings.TrimLeft(actual, "0"), strings.TrimLeft(expected, "0"))
+}
+
+// ChecksumWriter computes CRC32, MD5 and SHA1 of everything written to it in a single pass.
+type ChecksumWriter struct {
+	crc  hash.Hash32
+	md5  hash.Hash
+	sha1 hash.Hash
+	w    io.Writer
+}
+
+func NewChecksumWriter() *ChecksumWriter {
+	cw := &ChecksumWriter{
+		crc:  crc32.NewIEEE(),
+		md5:  md5.New(),
+		sha1: sha1.New(),
+	}
+	cw.w = io.MultiWriter(cw.crc, cw.md5, cw.sha1)
+	return cw
+}
+
+func (cw *ChecksumWriter) Write(p []byte) (int, error) {
+	return cw.w.Write(p)
+}
+
+func (cw *ChecksumWriter) Sum() Checksums {
+	return Checksums{
+		CRC:  fmt.Sprintf("%08x", cw.crc.Sum32()),
+		MD5:  hex.EncodeToString(cw.md5.Sum(nil)),
+		SHA1: hex.EncodeToString(cw.sha1.Sum(nil)),
+	}
+}
+
+func FileChecksums(path string) (Checksums, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Checksums{}, err
+	}
+	defer file.Close()
+
+	cw := NewChecksumWriter()
+	if _, err := io.CopyBuffer(cw, file, make([]byte, DefaultBufferSize)); err != nil {
+		return Checksums{}, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	return cw.Sum(), nil
+}
+
+// FollowChecksums hashes a file while another writer is still filling it, so the checksums are
+// ready as soon as the write is done instead of needing a second read. done must be closed once
+// the writer has finished. A file rewritten from the start in the meantime returns an error.
+func FollowChecksums(path string, done <-chan struct{}) (Checksums, error) {
+	file, err := waitForFile(path, done)
+	if err != nil {
+		return Checksums{}, err
+	}
+	defer file.Close()
+
+	cw := NewChecksumWriter()
+	buf := make([]byte, DefaultBufferSize)
+	var hashed int64
+	finished := false
+
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			cw.Write(buf[:n])
+			hashed += int64(n)
+		}
+		if err == nil {
+			continue
+		}
+		if err != io.EOF {
+			return Checksums{}, fmt.Errorf("failed to hash %s: %w", path, err)
+		}
+
+		// The writer is done once done is closed, so the read after that reaches the real end
+		if finished {
+			break
+		}
+		select {
+		case <-done:
+			finished = true
+		case <-time.After(followInterval):
+		}
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		return Checksums{}, err
+	}
+	if stat.Size() != hashed {
+		return Checksums{}, fmt.Errorf("%s changed while it was hashed", path)
+	}
+
+	return cw.Sum(), nil
+}
+
+func waitForFile(path string, done <-chan struct{}) (*os.File, error) {
+	for {
+		file, err := os.Open(path)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		select {
+		case <-done:
+			// The writer may have created the file just before finishing
+			return os.Open(path)
+		case <-time.After(followInterval):
+		}
+	}
+}
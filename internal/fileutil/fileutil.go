@@ -33,6 +33,27 @@ func (pw *progressWriter) Write(p []byte) (int, error) {
 	return n, err
 }
 
+type progressReader struct {
+	reader     io.Reader
+	totalBytes int64
+	readBytes  int64
+	progress   *atomic.Float64
+}
+
+// NewProgressReader returns a reader that stores the fraction of totalBytes read from r in progress.
+func NewProgressReader(r io.Reader, totalBytes int64, progress *atomic.Float64) io.Reader {
+	return &progressReader{reader: r, totalBytes: totalBytes, progress: progress}
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.reader.Read(p)
+	if n > 0 && pr.progress != nil && pr.totalBytes > 0 {
+		pr.readBytes += int64(n)
+		pr.progress.Store(float64(pr.readBytes) / float64(pr.totalBytes))
+	}
+	return n, err
+}
+
 func TempDir() string {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -76,6 +97,19 @@ func DeleteFile(path string) error {
 }
 
 func Unzip(zipPath string, destDir string, progress *atomic.Float64) error {
+	return unzip(zipPath, destDir, progress, nil)
+}
+
+// UnzipWithChecksums extracts like Unzip and hashes each file as it is written, keyed by its path in the archive.
+func UnzipWithChecksums(zipPath string, destDir string, progress *atomic.Float64) (map[string]Checksums, error) {
+	checksums := make(map[string]Checksums)
+	if err := unzip(zipPath, destDir, progress, checksums); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+func unzip(zipPath string, destDir string, progress *atomic.Float64, checksums map[string]Checksums) error {
 	reader, err := zip.OpenReader(zipPath)
 	if err != nil {
 		return fmt.Errorf("failed to open zip file: %w", err)
@@ -121,9 +155,18 @@ func Unzip(zipPath string, destDir string, progress *atomic.Float64) error {
 			createdDirs[parentDir] = true
 		}
 
-		if err := extractFile(file, filePath, buffer, totalBytes, &extractedBytes, progress); err != nil {
+		var cw *ChecksumWriter
+		if checksums != nil {
+			cw = NewChecksumWriter()
+		}
+
+		if err := extractFile(file, filePath, buffer, totalBytes, &extractedBytes, progress, cw); err != nil {
 			return fmt.Errorf("failed to extract file %s: %w", file.Name, err)
 		}
+
+		if cw != nil {
+			checksums[file.Name] = cw.Sum()
+		}
 	}
 
 	return nil
@@ -134,7 +177,7 @@ func FileExists(path string) bool {
 	return err == nil
 }
 
-func extractFile(file *zip.File, destPath string, buffer []byte, totalBytes uint64, extractedBytes *uint64, progress *atomic.Float64) error {
+func extractFile(file *zip.File, destPath string, buffer []byte, totalBytes uint64, extractedBytes *uint64, progress *atomic.Float64, cw *ChecksumWriter) error {
 	srcFile, err := file.Open()
 	if err != nil {
 		return err
@@ -150,8 +193,13 @@ func extractFile(file *zip.File, destPath string, buffer []byte, totalBytes uint
 	bufWriter := bufio.NewWriterSize(destFile, SmallBufferSize)
 	defer bufWriter.Flush()
 
+	var dest io.Writer = bufWriter
+	if cw != nil {
+		dest = io.MultiWriter(bufWriter, cw)
+	}
+
 	progressW := &progressWriter{
-		writer:         bufWriter,
+		writer:         dest,
 		totalBytes:     totalBytes,
 		extractedBytes: extractedBytes,
 		progress:       progress,
@@ -89,6 +89,12 @@ func nameCleaner(name string, stripTag bool) (string, string) {
 	return cleaned, foundTag
 }
 
+// CleanRomName returns the name without its tags, as shown in game lists.
+func CleanRomName(name string) string {
+	cleaned, _ := nameCleaner(name, true)
+	return cleaned
+}
+
 func PrepareRomNames(games []romm.Rom) []romm.Rom {
 	for i := range games {
 		regions := strings.Join(games[i].Regions, ", ")
@@ -101,6 +107,11 @@ func PrepareRomNames(games []romm.Rom) []romm.Rom {
 			games[i].DisplayName = dn
 		}
 
+		// Every disc of a multi-disc game would otherwise get the same name
+		if disc := games[i].NameTags().Disc; disc != "" {
+			games[i].DisplayName = fmt.Sprintf("%s (Disc %s)", games[i].DisplayName, disc)
+		}
+
 	}
 
 	slices.SortFunc(games, func(a, b romm.Rom) int {
@@ -1,13 +1,23 @@
 package romm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 )
 
 func (c *Client) ValidateConnection() error {
-	req, err := http.NewRequest("GET", c.baseURL+endpointHeartbeat, nil)
+	return c.ValidateConnectionContext(context.Background())
+}
+
+func (c *Client) ValidateConnectionContext(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+endpointHeartbeat, nil)
 	if err != nil {
 		return ClassifyError(fmt.Errorf("failed to create validation request: %w", err))
 	}
@@ -18,10 +28,12 @@ func (c *Client) ValidateConnection() error {
 
 		shouldTryProtocolSwitch := !errors.Is(classifiedErr, ErrTimeout) &&
 			!errors.Is(classifiedErr, ErrConnectionRefused) &&
-			!errors.Is(classifiedErr, ErrInvalidHostname)
+			!errors.Is(classifiedErr, ErrInvalidHostname) &&
+			!errors.Is(classifiedErr, ErrUntrustedCertificate) &&
+			!errors.Is(classifiedErr, ErrCertificateMismatch)
 
 		if shouldTryProtocolSwitch {
-			if protocolErr := c.tryAlternateProtocol(req.URL.Scheme, func(r *http.Response) bool {
+			if protocolErr := c.tryAlternateProtocol(ctx, req.URL.Scheme, func(r *http.Response) bool {
 				return r.StatusCode >= 200 && r.StatusCode < 300
 			}); protocolErr != nil {
 				return protocolErr
@@ -50,7 +62,7 @@ func (c *Client) ValidateConnection() error {
 			Err:        ErrServerError,
 		}
 	default:
-		if protocolErr := c.tryAlternateProtocol(req.URL.Scheme, func(r *http.Response) bool {
+		if protocolErr := c.tryAlternateProtocol(ctx, req.URL.Scheme, func(r *http.Response) bool {
 			return r.StatusCode >= 200 && r.StatusCode < 300
 		}); protocolErr != nil {
 			return protocolErr
@@ -71,13 +83,13 @@ func switchProtocol(baseURL string) string {
 
 // tryAlternateProtocol tests if the alternate protocol works and returns a ProtocolError if it does.
 // The isSuccess function determines if the response indicates the alternate protocol is working.
-func (c *Client) tryAlternateProtocol(originalScheme string, isSuccess func(resp *http.Response) bool) *ProtocolError {
+func (c *Client) tryAlternateProtocol(ctx context.Context, originalScheme string, isSuccess func(resp *http.Response) bool) *ProtocolError {
 	switchedURL := switchProtocol(c.baseURL)
 	if switchedURL == c.baseURL {
 		return nil
 	}
 
-	testReq, err := http.NewRequest("GET", switchedURL+endpointHeartbeat, nil)
+	testReq, err := http.NewRequestWithContext(ctx, "GET", switchedURL+endpointHeartbeat, nil)
 	if err != nil {
 		return nil
 	}
@@ -98,60 +110,72 @@ func (c *Client) tryAlternateProtocol(originalScheme string, isSuccess func(resp
 	return nil
 }
 
-func (c *Client) Login(username, password string) error {
-	req, err := http.NewRequest("POST", c.baseURL+endpointLogin, nil)
-	if err != nil {
-		return ClassifyError(fmt.Errorf("failed to create login request: %w", err))
-	}
+// Login exchanges the user's credentials for an OAuth2 token pair. The client uses
+// the token for subsequent requests, the caller is expected to persist it on the Host
+// instead of the password.
+func (c *Client) Login(username, password string) (*Token, error) {
+	return c.LoginContext(context.Background(), username, password)
+}
 
-	req.SetBasicAuth(username, password)
+func (c *Client) LoginContext(ctx context.Context, username, password string) (*Token, error) {
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {username},
+		"password":   {password},
+		"scope":      {strings.Join(DefaultTokenScopes, " ")},
+	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return ClassifyError(fmt.Errorf("failed to login: %w", err))
+	res, status, err := c.requestToken(ctx, c.baseURL, form)
+	if err == nil {
+		token := &Token{}
+		token.update(res)
+		c.token = token
+		c.requestUploadScopes(ctx, form, token)
+		return token, nil
 	}
-	defer resp.Body.Close()
 
-	switch {
-	case resp.StatusCode >= 200 && resp.StatusCode < 300:
-		return nil
-	case resp.StatusCode == 401:
-		return &AuthError{
-			StatusCode: 401,
-			Message:    "Invalid username or password",
-			Err:        ErrUnauthorized,
-		}
-	case resp.StatusCode == 403:
-		return &AuthError{
-			StatusCode: 403,
-			Message:    "Access forbidden",
-			Err:        ErrForbidden,
-		}
-	case resp.StatusCode >= 500:
-		return &AuthError{
-			StatusCode: resp.StatusCode,
-			Message:    "Server error",
-			Err:        ErrServerError,
-		}
-	case resp.StatusCode == 405:
+	if status == 405 {
 		if switchedURL := switchProtocol(c.baseURL); switchedURL != c.baseURL {
-			testClient := NewClient(switchedURL, WithTimeout(c.httpClient.Timeout))
-			if testReq, testErr := http.NewRequest("POST", switchedURL+endpointLogin, nil); testErr == nil {
-				testReq.SetBasicAuth(username, password)
-				if testResp, testRespErr := testClient.httpClient.Do(testReq); testRespErr == nil {
-					defer testResp.Body.Close()
-					if testResp.StatusCode != 405 && testResp.StatusCode < 500 {
-						return &ProtocolError{
-							RequestedProtocol: req.URL.Scheme,
-							CorrectProtocol:   testReq.URL.Scheme,
-							Err:               ErrWrongProtocol,
-						}
-					}
+			if _, testStatus, _ := c.requestToken(ctx, switchedURL, form); testStatus != 0 && testStatus != 405 && testStatus < 500 {
+				return nil, &ProtocolError{
+					RequestedProtocol: schemeOf(c.baseURL),
+					CorrectProtocol:   schemeOf(switchedURL),
+					Err:               ErrWrongProtocol,
 				}
 			}
 		}
-		return fmt.Errorf("login failed with status: %d", resp.StatusCode)
-	default:
-		return fmt.Errorf("login failed with status: %d", resp.StatusCode)
 	}
+
+	return nil, err
+}
+
+// requestUploadScopes logs in again with UploadTokenScopes when the user's role has them, so
+// editors and admins can upload ROMs. The role is only known once logged in. When any of
+// this fails the token keeps the scopes every user has.
+func (c *Client) requestUploadScopes(ctx context.Context, form url.Values, token *Token) {
+	logger := gabagool.GetLogger()
+
+	user, err := c.GetCurrentUserContext(ctx)
+	if err != nil {
+		logger.Warn("Unable to read the user's role, ROM uploads stay disabled", "error", err)
+		return
+	}
+	if !user.CanUpload() {
+		return
+	}
+
+	form.Set("scope", strings.Join(slices.Concat(DefaultTokenScopes, UploadTokenScopes), " "))
+	res, _, err := c.requestToken(ctx, c.baseURL, form)
+	if err != nil {
+		logger.Warn("Unable to get a token that can upload ROMs", "role", user.Role, "error", err)
+		return
+	}
+	token.update(res)
+}
+
+func schemeOf(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil {
+		return u.Scheme
+	}
+	return ""
 }
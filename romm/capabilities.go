@@ -0,0 +1,137 @@
+package romm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Heartbeat is the subset of /api/heartbeat Grout cares about. RomM 3.x and later nest the
+// version under SYSTEM, older releases reported it at the top level.
+type Heartbeat struct {
+	System struct {
+		Version string `json:"VERSION"`
+	} `json:"SYSTEM"`
+	LegacyVersion string `json:"VERSION"`
+}
+
+func (h Heartbeat) Version() string {
+	if h.System.Version != "" {
+		return h.System.Version
+	}
+	return h.LegacyVersion
+}
+
+func (c *Client) GetHeartbeat() (Heartbeat, error) {
+	return c.GetHeartbeatContext(context.Background())
+}
+
+func (c *Client) GetHeartbeatContext(ctx context.Context) (Heartbeat, error) {
+	var heartbeat Heartbeat
+	err := c.doRequest(ctx, "GET", endpointHeartbeat, nil, nil, &heartbeat)
+	return heartbeat, err
+}
+
+// ServerVersion is a parsed RomM release version. Development builds and anything else that
+// doesn't parse are treated as the newest release.
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+	Known bool
+}
+
+func ParseServerVersion(raw string) ServerVersion {
+	raw = strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	// Drop pre-release and build suffixes like 4.5.0-beta.1
+	if i := strings.IndexAny(raw, "-+"); i >= 0 {
+		raw = raw[:i]
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) < 2 {
+		return ServerVersion{}
+	}
+
+	var numbers [3]int
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return ServerVersion{}
+		}
+		numbers[i] = n
+	}
+
+	return ServerVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Known: true}
+}
+
+func (v ServerVersion) AtLeast(major, minor, patch int) bool {
+	if !v.Known {
+		return true
+	}
+	if v.Major != major {
+		return v.Major > major
+	}
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+	return v.Patch >= patch
+}
+
+func (v ServerVersion) String() string {
+	if !v.Known {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+type Capability string
+
+const (
+	CapabilitySavesByPlatform    Capability = "saves_by_platform"
+	CapabilityStates             Capability = "states"
+	CapabilityUserProps          Capability = "user_props"
+	CapabilityFirmware           Capability = "firmware"
+	CapabilitySmartCollections   Capability = "smart_collections"
+	CapabilityVirtualCollections Capability = "virtual_collections"
+)
+
+// capabilityMinimums lists the first RomM release that shipped each capability.
+var capabilityMinimums = map[Capability]ServerVersion{
+	CapabilityFirmware:           {Major: 3, Minor: 0, Patch: 0, Known: true},
+	CapabilityUserProps:          {Major: 3, Minor: 5, Patch: 0, Known: true},
+	CapabilityVirtualCollections: {Major: 3, Minor: 8, Patch: 0, Known: true},
+	CapabilitySmartCollections:   {Major: 4, Minor: 0, Patch: 0, Known: true},
+	CapabilitySavesByPlatform:    {Major: 4, Minor: 5, Patch: 0, Known: true},
+	CapabilityStates:             {Major: 4, Minor: 5, Patch: 0, Known: true},
+}
+
+// Capabilities describes which optional API features a RomM server supports.
+type Capabilities struct {
+	Version ServerVersion
+}
+
+func CapabilitiesFor(version string) Capabilities {
+	return Capabilities{Version: ParseServerVersion(version)}
+}
+
+func (c Capabilities) Has(capability Capability) bool {
+	minimum, ok := capabilityMinimums[capability]
+	if !ok {
+		return false
+	}
+	return c.Version.AtLeast(minimum.Major, minimum.Minor, minimum.Patch)
+}
+
+// Require returns an UnsupportedError when the server is too old for capability.
+func (c Capabilities) Require(capability Capability) error {
+	if c.Has(capability) {
+		return nil
+	}
+	return &UnsupportedError{
+		Capability: capability,
+		Version:    c.Version,
+		Minimum:    capabilityMinimums[capability],
+	}
+}
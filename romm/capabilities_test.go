@@ -0,0 +1,49 @@
+package romm_test
+
+import (
+	"errors"
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func TestHeartbeatCapabilities(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{Version: "4.0.1"})
+	defer server.Close()
+
+	heartbeat, err := server.Client().GetHeartbeat()
+	if err != nil {
+		t.Fatalf("GetHeartbeat() error = %v", err)
+	}
+
+	capabilities := romm.CapabilitiesFor(heartbeat.Version())
+	if !capabilities.Has(romm.CapabilitySmartCollections) {
+		t.Errorf("RomM %s should support smart collections", heartbeat.Version())
+	}
+	if err := capabilities.Require(romm.CapabilitySavesByPlatform); !errors.Is(err, romm.ErrUnsupported) {
+		t.Errorf("Require(saves by platform) on RomM %s error = %v, want ErrUnsupported", heartbeat.Version(), err)
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  romm.ServerVersion
+	}{
+		{"4.5.0", romm.ServerVersion{Major: 4, Minor: 5, Patch: 0, Known: true}},
+		{"v3.10.2", romm.ServerVersion{Major: 3, Minor: 10, Patch: 2, Known: true}},
+		{"4.6.0-beta.1", romm.ServerVersion{Major: 4, Minor: 6, Patch: 0, Known: true}},
+		{"4.1", romm.ServerVersion{Major: 4, Minor: 1, Patch: 0, Known: true}},
+		{"development", romm.ServerVersion{}},
+		{"", romm.ServerVersion{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := romm.ParseServerVersion(tt.input); got != tt.want {
+				t.Errorf("ParseServerVersion(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
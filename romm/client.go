@@ -2,13 +2,17 @@ package romm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	"github.com/sonh/qs"
 )
 
@@ -21,6 +25,10 @@ type Client struct {
 	httpClient *http.Client
 	username   string
 	password   string
+	token      *Token
+	hostKey    string
+	retry      RetryPolicy
+	headers    map[string]string
 }
 
 type queryParam interface {
@@ -35,6 +43,13 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithTransport sends requests through transport, used for hosts with their own TLS settings.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
 func WithBasicAuth(username, password string) ClientOption {
 	return func(c *Client) {
 		c.username = username
@@ -42,30 +57,60 @@ func WithBasicAuth(username, password string) ClientOption {
 	}
 }
 
+// WithHeaders adds custom headers to every request sent to the server, including logins and
+// token refreshes.
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		c.headers = headers
+	}
+}
+
+// WithToken authenticates requests with a bearer token, refreshing it on expiry or a 401.
+// It takes precedence over basic auth.
+func WithToken(token *Token) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
 func NewClient(baseURL string, opts ...ClientOption) *Client {
 	c := &Client{
 		baseURL: strings.TrimSuffix(baseURL, "/"),
 		httpClient: &http.Client{
 			Timeout: DefaultClientTimeout,
 		},
+		retry: DefaultRetryPolicy,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if len(c.headers) > 0 {
+		c.httpClient.Transport = newHeaderTransport(c.baseURL, c.headers, c.httpClient.Transport)
+	}
+
 	return c
 }
 
 func NewClientFromHost(host Host, timeout ...time.Duration) *Client {
-	opts := []ClientOption{WithBasicAuth(host.Username, host.Password)}
+	opts := []ClientOption{
+		WithBasicAuth(host.Username, host.Password),
+		WithTransport(host.Transport()),
+		WithHeaders(host.Headers),
+	}
+	if host.Token != nil {
+		opts = append(opts, WithToken(host.Token))
+	}
 	if len(timeout) > 0 {
 		opts = append(opts, WithTimeout(timeout[0]))
 	}
-	return NewClient(host.URL(), opts...)
+	c := NewClient(host.URL(), opts...)
+	c.hostKey = host.Key()
+	return c
 }
 
-func (c *Client) doRequest(method string, path string, queryParams queryParam, body interface{}, result interface{}) error {
+func (c *Client) doRequest(ctx context.Context, method string, path string, queryParams queryParam, body interface{}, result interface{}) error {
 	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -77,7 +122,7 @@ func (c *Client) doRequest(method string, path string, queryParams queryParam, b
 
 	u := c.baseURL + path
 
-	req, err := http.NewRequest(method, u, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -93,11 +138,7 @@ func (c *Client) doRequest(method string, path string, queryParams queryParam, b
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
-	}
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -105,7 +146,7 @@ func (c *Client) doRequest(method string, path string, queryParams queryParam, b
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return newAPIError(req, resp.StatusCode, bodyBytes)
 	}
 
 	if result != nil && resp.StatusCode != http.StatusNoContent {
@@ -117,61 +158,45 @@ func (c *Client) doRequest(method string, path string, queryParams queryParam, b
 	return nil
 }
 
-func (c *Client) doRequestRaw(method, path string, body interface{}) ([]byte, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonData)
-	}
-
+// doRequestStream copies the response body into w without buffering it in memory.
+func (c *Client) doRequestStream(ctx context.Context, method, path string, w io.Writer) (int64, error) {
 	fullURL := c.baseURL + strings.ReplaceAll(path, " ", "%20")
 
-	req, err := http.NewRequest(method, fullURL, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return 0, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return 0, newAPIError(req, resp.StatusCode, bodyBytes)
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	return bodyBytes, nil
+	return n, nil
 }
 
-func (c *Client) doMultipartRequest(method, path string, queryParams queryParam, body io.Reader, contentType string, result interface{}) error {
+func (c *Client) doMultipartRequest(ctx context.Context, method, path string, queryParams queryParam, body io.Reader, contentType string, header http.Header, result interface{}) error {
 	u := c.baseURL + path
-	req, err := http.NewRequest(method, u, body)
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", contentType)
-
-	if c.username != "" && c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
+	for key, values := range header {
+		req.Header[key] = values
 	}
+	req.Header.Set("Content-Type", contentType)
 
 	if queryParams != nil && queryParams.Valid() {
 		values, err := qs.NewEncoder().Values(queryParams)
@@ -180,7 +205,7 @@ func (c *Client) doMultipartRequest(method, path string, queryParams queryParam,
 		}
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -188,7 +213,7 @@ func (c *Client) doMultipartRequest(method, path string, queryParams queryParam,
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return newAPIError(req, resp.StatusCode, bodyBytes)
 	}
 
 	if result != nil && resp.StatusCode != http.StatusNoContent {
@@ -199,3 +224,119 @@ func (c *Client) doMultipartRequest(method, path string, queryParams queryParam,
 
 	return nil
 }
+
+// do executes the request, retrying idempotent requests on transient failures
+// according to the client's RetryPolicy.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	attempts := c.retry.attempts(req.Method)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doAuthorized(req)
+		if attempt >= attempts {
+			return resp, err
+		}
+
+		if err != nil {
+			if !isRetryableError(err) {
+				return nil, err
+			}
+		} else if !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		wait := c.retry.backoff(attempt, resp)
+		if resp != nil {
+			gabagool.GetLogger().Debug("Retrying request", "path", req.URL.Path, "status", resp.StatusCode, "attempt", attempt, "wait", wait)
+			_, _ = io.CopyN(io.Discard, resp.Body, 64*1024)
+			resp.Body.Close()
+		} else {
+			gabagool.GetLogger().Debug("Retrying request", "path", req.URL.Path, "error", err, "attempt", attempt, "wait", wait)
+		}
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		req = req.Clone(req.Context())
+	}
+}
+
+// doAuthorized applies authentication and executes the request. With a token, a 401 triggers
+// a single refresh and replay as long as the request body can be rewound.
+func (c *Client) doAuthorized(req *http.Request) (*http.Response, error) {
+	if err := c.authorize(req, false); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !c.token.HasRefreshToken() {
+		return resp, err
+	}
+
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+
+	if err := c.authorize(retry, true); err != nil {
+		// A rejected refresh token says more than the 401 it was meant to recover from
+		if errors.Is(err, ErrUnauthorized) {
+			resp.Body.Close()
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	resp.Body.Close()
+	return c.httpClient.Do(retry)
+}
+
+func (c *Client) authorize(req *http.Request, forceRefresh bool) error {
+	if c.token != nil {
+		accessToken, err := c.accessTokenFor(req.Context(), forceRefresh)
+		if err == nil {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+			return nil
+		}
+		if c.password == "" {
+			return err
+		}
+	}
+
+	if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return nil
+}
+
+// AuthorizationHeader returns the Authorization header value for requests made
+// outside the client, such as the download manager or artwork fetches.
+func (c *Client) AuthorizationHeader() string {
+	req, _ := http.NewRequest("GET", c.baseURL, nil)
+	if err := c.authorize(req, false); err != nil {
+		return ""
+	}
+	return req.Header.Get("Authorization")
+}
+
+// DownloadHeaders returns the headers for downloads made outside the client, such as by the
+// download manager: the custom headers and the Authorization header.
+func (c *Client) DownloadHeaders() map[string]string {
+	headers := maps.Clone(c.headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	if auth := c.AuthorizationHeader(); auth != "" {
+		headers["Authorization"] = auth
+	}
+	return headers
+}
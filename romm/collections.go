@@ -1,7 +1,11 @@
 package romm
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"time"
 )
 
@@ -53,27 +57,117 @@ func (v VirtualCollectionsQuery) Valid() bool {
 }
 
 func (c *Client) GetCollections() ([]Collection, error) {
+	return c.GetCollectionsContext(context.Background())
+}
+
+func (c *Client) GetCollectionsContext(ctx context.Context) ([]Collection, error) {
 	var collections []Collection
-	err := c.doRequest("GET", endpointCollections, nil, nil, &collections)
+	err := c.doRequest(ctx, "GET", endpointCollections, nil, nil, &collections)
 	return collections, err
 }
 
 func (c *Client) GetCollection(id int) (Collection, error) {
+	return c.GetCollectionContext(context.Background(), id)
+}
+
+func (c *Client) GetCollectionContext(ctx context.Context, id int) (Collection, error) {
 	var collection Collection
 	path := fmt.Sprintf(endpointCollectionByID, id)
-	err := c.doRequest("GET", path, nil, nil, &collection)
+	err := c.doRequest(ctx, "GET", path, nil, nil, &collection)
 	return collection, err
 }
 
+// CreateCollection creates an empty regular collection owned by the logged in user.
+func (c *Client) CreateCollection(name, description string) (Collection, error) {
+	return c.CreateCollectionContext(context.Background(), name, description)
+}
+
+func (c *Client) CreateCollectionContext(ctx context.Context, name, description string) (Collection, error) {
+	body, contentType, err := collectionForm(map[string]string{
+		"name":        name,
+		"description": description,
+	})
+	if err != nil {
+		return Collection{}, err
+	}
+
+	var collection Collection
+	err = c.doMultipartRequest(ctx, "POST", endpointCollections, nil, body, contentType, nil, &collection)
+	return collection, err
+}
+
+// UpdateCollection saves the name, description and games of a regular collection. RomM
+// replaces the game list wholesale, so ROMIDs must hold every game that should remain.
+func (c *Client) UpdateCollection(collection Collection) (Collection, error) {
+	return c.UpdateCollectionContext(context.Background(), collection)
+}
+
+func (c *Client) UpdateCollectionContext(ctx context.Context, collection Collection) (Collection, error) {
+	romIDs := collection.ROMIDs
+	if romIDs == nil {
+		romIDs = []int{}
+	}
+	romIDsJSON, err := json.Marshal(romIDs)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	body, contentType, err := collectionForm(map[string]string{
+		"name":        collection.Name,
+		"description": collection.Description,
+		"rom_ids":     string(romIDsJSON),
+	})
+	if err != nil {
+		return Collection{}, err
+	}
+
+	var updated Collection
+	path := fmt.Sprintf(endpointCollectionByID, collection.ID)
+	err = c.doMultipartRequest(ctx, "PUT", path, nil, body, contentType, nil, &updated)
+	return updated, err
+}
+
+func (c *Client) DeleteCollection(id int) error {
+	return c.DeleteCollectionContext(context.Background(), id)
+}
+
+func (c *Client) DeleteCollectionContext(ctx context.Context, id int) error {
+	path := fmt.Sprintf(endpointCollectionByID, id)
+	return c.doRequest(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// collectionForm encodes fields the way RomM's collection endpoints read them, as multipart form values.
+func collectionForm(fields map[string]string) (*bytes.Buffer, string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, writer.FormDataContentType(), nil
+}
+
 func (c *Client) GetSmartCollections() ([]Collection, error) {
+	return c.GetSmartCollectionsContext(context.Background())
+}
+
+func (c *Client) GetSmartCollectionsContext(ctx context.Context) ([]Collection, error) {
 	var collections []Collection
-	err := c.doRequest("GET", endpointSmartCollections, nil, nil, &collections)
+	err := c.doRequest(ctx, "GET", endpointSmartCollections, nil, nil, &collections)
 	return collections, err
 }
 
 func (c *Client) GetVirtualCollections() ([]VirtualCollection, error) {
+	return c.GetVirtualCollectionsContext(context.Background())
+}
+
+func (c *Client) GetVirtualCollectionsContext(ctx context.Context) ([]VirtualCollection, error) {
 	var collections []VirtualCollection
-	err := c.doRequest("GET", endpointVirtualCollections, VirtualCollectionsQuery{Type: "collection"}, nil, &collections)
+	err := c.doRequest(ctx, "GET", endpointVirtualCollections, VirtualCollectionsQuery{Type: "collection"}, nil, &collections)
 	return collections, err
 }
 
@@ -0,0 +1,51 @@
+package romm_test
+
+import (
+	"errors"
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func TestCollectionEditing(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{
+		Platforms: []romm.Platform{{ID: 1, Name: "Game Boy", Slug: "gb", FSSlug: "gb"}},
+		Roms:      platformRoms(1, 3, 1),
+	})
+	defer server.Close()
+
+	client := server.Client()
+
+	created, err := client.CreateCollection("Favourites", "Best of the Game Boy")
+	if err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+	if created.ID == 0 || created.Name != "Favourites" || len(created.ROMIDs) != 0 {
+		t.Fatalf("CreateCollection() = %+v, want an empty collection named Favourites", created)
+	}
+
+	created.Name = "Top Picks"
+	created.ROMIDs = []int{1, 3}
+	if _, err := client.UpdateCollection(created); err != nil {
+		t.Fatalf("UpdateCollection() error = %v", err)
+	}
+
+	got, err := client.GetCollection(created.ID)
+	if err != nil {
+		t.Fatalf("GetCollection() error = %v", err)
+	}
+	if got.Name != "Top Picks" || got.Description != "Best of the Game Boy" || len(got.ROMIDs) != 2 || got.ROMIDs[1] != 3 {
+		t.Fatalf("GetCollection() after update = %+v, want renamed with ROMs [1 3]", got)
+	}
+
+	if err := client.DeleteCollection(created.ID); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	if _, err := client.GetCollection(created.ID); !errors.Is(err, romm.ErrNotFound) {
+		t.Errorf("GetCollection() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := client.DeleteCollection(created.ID); !errors.Is(err, romm.ErrNotFound) {
+		t.Errorf("DeleteCollection() of a missing collection error = %v, want ErrNotFound", err)
+	}
+}
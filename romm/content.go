@@ -0,0 +1,147 @@
+package romm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ContentInfo describes a ROM file as reported by the server without downloading it.
+type ContentInfo struct {
+	Size         int64
+	ETag         string
+	LastModified string
+	AcceptRanges bool
+}
+
+// Validator returns the value to send as If-Range when resuming, preferring the strong ETag.
+func (i ContentInfo) Validator() string {
+	if i.ETag != "" && !strings.HasPrefix(i.ETag, "W/") {
+		return i.ETag
+	}
+	return i.LastModified
+}
+
+// RomContent is an open ROM file response. Offset is where Body starts within the file,
+// which is zero whenever the server ignored the requested range.
+type RomContent struct {
+	Body   io.ReadCloser
+	Offset int64
+	Info   ContentInfo
+}
+
+func romContentPath(romID int, fileName string) string {
+	return fmt.Sprintf(endpointRomContent, romID, url.PathEscape(fileName))
+}
+
+func (c *Client) GetRomContentInfo(romID int, fileName string) (ContentInfo, error) {
+	return c.GetRomContentInfoContext(context.Background(), romID, fileName)
+}
+
+func (c *Client) GetRomContentInfoContext(ctx context.Context, romID int, fileName string) (ContentInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL+romContentPath(romID, fileName), nil)
+	if err != nil {
+		return ContentInfo{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return ContentInfo{}, fmt.Errorf("failed to execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return ContentInfo{}, newAPIError(req, resp.StatusCode, nil)
+	}
+
+	return contentInfoFrom(resp, resp.ContentLength), nil
+}
+
+// OpenRomContent requests a ROM file starting at offset. When validator is set it is sent as
+// If-Range, so a server whose copy changed answers with the whole file instead of the range.
+// The caller must close Body.
+func (c *Client) OpenRomContent(romID int, fileName string, offset int64, validator string) (RomContent, error) {
+	return c.OpenRomContentContext(context.Background(), romID, fileName, offset, validator)
+}
+
+func (c *Client) OpenRomContentContext(ctx context.Context, romID int, fileName string, offset int64, validator string) (RomContent, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+romContentPath(romID, fileName), nil)
+	if err != nil {
+		return RomContent{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator != "" {
+			req.Header.Set("If-Range", validator)
+		}
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return RomContent{}, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return RomContent{Body: resp.Body, Info: contentInfoFrom(resp, resp.ContentLength)}, nil
+	case http.StatusPartialContent:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			resp.Body.Close()
+			return RomContent{}, fmt.Errorf("unexpected content range %q for offset %d", resp.Header.Get("Content-Range"), offset)
+		}
+		return RomContent{Body: resp.Body, Offset: start, Info: contentInfoFrom(resp, size)}, nil
+	default:
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return RomContent{}, newAPIError(req, resp.StatusCode, bodyBytes)
+	}
+}
+
+func contentInfoFrom(resp *http.Response, size int64) ContentInfo {
+	return ContentInfo{
+		Size:         size,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		AcceptRanges: resp.Header.Get("Accept-Ranges") == "bytes",
+	}
+}
+
+// parseContentRange reads "bytes start-end/size", returning -1 for an unknown size.
+func parseContentRange(value string) (start, size int64, ok bool) {
+	rangeSpec, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+
+	span, total, found := strings.Cut(rangeSpec, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	first, _, found := strings.Cut(span, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if total == "*" {
+		return start, -1, true
+	}
+
+	size, err = strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return start, size, true
+}
@@ -0,0 +1,81 @@
+package romm_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"grout/romm/rommtest"
+)
+
+func TestOpenRomContentResume(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100_000)
+	server := rommtest.NewServer(rommtest.Fixture{
+		Roms:    platformRoms(1, 1, 1),
+		Content: map[rommtest.ContentKey][]byte{{RomID: 1, FileName: "Game (USA).iso"}: content},
+	})
+	defer server.Close()
+
+	client := server.Client()
+
+	info, err := client.GetRomContentInfo(1, "Game (USA).iso")
+	if err != nil {
+		t.Fatalf("GetRomContentInfo() error = %v", err)
+	}
+	if info.Size != int64(len(content)) || info.Validator() == "" {
+		t.Fatalf("GetRomContentInfo() = %+v, want size %d and a validator", info, len(content))
+	}
+
+	const offset = 123_456
+	resumed, err := client.OpenRomContent(1, "Game (USA).iso", offset, info.Validator())
+	if err != nil {
+		t.Fatalf("OpenRomContent() error = %v", err)
+	}
+	rest, err := io.ReadAll(resumed.Body)
+	resumed.Body.Close()
+	if err != nil {
+		t.Fatalf("reading resumed content: %v", err)
+	}
+	if resumed.Offset != offset || !bytes.Equal(rest, content[offset:]) {
+		t.Errorf("OpenRomContent() resumed at %d with %d bytes, want %d with %d", resumed.Offset, len(rest), offset, len(content)-offset)
+	}
+
+	// A changed file fails If-Range and comes back whole
+	changed := bytes.Repeat([]byte("abcdefghij"), 100_000)
+	server.SetContent(1, "Game (USA).iso", changed)
+
+	restarted, err := client.OpenRomContent(1, "Game (USA).iso", offset, info.Validator())
+	if err != nil {
+		t.Fatalf("OpenRomContent() error = %v", err)
+	}
+	full, _ := io.ReadAll(restarted.Body)
+	restarted.Body.Close()
+	if restarted.Offset != 0 || !bytes.Equal(full, changed) {
+		t.Errorf("OpenRomContent() after change resumed at %d, want a full download", restarted.Offset)
+	}
+}
+
+func TestTruncatedContentDownload(t *testing.T) {
+	content := bytes.Repeat([]byte{1}, 256*1024)
+	server := rommtest.NewServer(rommtest.Fixture{
+		Content: map[rommtest.ContentKey][]byte{{RomID: 1, FileName: "game.gba"}: content},
+	})
+	defer server.Close()
+	server.AddHook(server.TruncateBody(rommtest.Match(http.MethodGet, "/api/roms/1/content/"), 1000, 1))
+
+	res, err := server.Client().OpenRomContent(1, "game.gba", 0, "")
+	if err != nil {
+		t.Fatalf("OpenRomContent() error = %v", err)
+	}
+	got, err := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("reading truncated body error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if len(got) != 1000 {
+		t.Errorf("read %d bytes before the connection dropped, want 1000", len(got))
+	}
+}
@@ -2,7 +2,9 @@ package romm
 
 const (
 	endpointHeartbeat = "/api/heartbeat"
-	endpointLogin     = "/api/login"
+	endpointToken     = "/api/token"
+
+	endpointCurrentUser = "/api/users/me"
 
 	endpointPlatforms    = "/api/platforms"
 	endpointPlatformByID = "/api/platforms/%d"
@@ -11,6 +13,7 @@ const (
 	endpointRomByID      = "/api/roms/%d"
 	endpointRomsDownload = "/api/roms/download"
 	endpointRomsByHash   = "/api/roms/by-hash"
+	endpointRomContent   = "/api/roms/%d/content/%s"
 
 	endpointCollections        = "/api/collections"
 	endpointCollectionByID     = "/api/collections/%d"
@@ -19,5 +22,6 @@ const (
 
 	endpointFirmware = "/api/firmware"
 
-	endpointSaves = "/api/saves"
+	endpointSaves  = "/api/saves"
+	endpointStates = "/api/states"
 )
@@ -1,9 +1,12 @@
 package romm
 
 import (
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 	"syscall"
@@ -19,6 +22,12 @@ var (
 	ErrUnauthorized      = errors.New("invalid credentials")
 	ErrForbidden         = errors.New("access forbidden")
 	ErrServerError       = errors.New("server error")
+	ErrNotFound          = errors.New("not found")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrUnsupported       = errors.New("not supported by this RomM version")
+
+	ErrUntrustedCertificate = errors.New("untrusted certificate")
+	ErrCertificateMismatch  = errors.New("certificate does not match the pinned fingerprint")
 )
 
 type AuthError struct {
@@ -35,6 +44,125 @@ func (e *AuthError) Unwrap() error {
 	return e.Err
 }
 
+// UnsupportedError is returned when a feature needs a newer RomM than the server runs.
+type UnsupportedError struct {
+	Capability Capability
+	Version    ServerVersion
+	Minimum    ServerVersion
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s requires RomM %s or newer, server runs %s", e.Capability, e.Minimum, e.Version)
+}
+
+func (e *UnsupportedError) Unwrap() error {
+	return ErrUnsupported
+}
+
+// APIError is returned when RomM answers with a non-2xx status.
+// It matches the sentinel errors above via errors.Is, e.g. a 401 is ErrUnauthorized.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Detail     string
+}
+
+func (e *APIError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("API error: %s %s returned status %d", e.Method, e.Path, e.StatusCode)
+	}
+	return fmt.Sprintf("API error: %s %s returned status %d: %s", e.Method, e.Path, e.StatusCode, e.Detail)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a failed response, pulling the message out of
+// RomM's {"detail": ...} body when there is one.
+func newAPIError(req *http.Request, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		StatusCode: statusCode,
+	}
+
+	var payload struct {
+		Detail any `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Detail != nil {
+		switch d := payload.Detail.(type) {
+		case string:
+			apiErr.Detail = d
+		default:
+			if b, err := json.Marshal(d); err == nil {
+				apiErr.Detail = string(b)
+			}
+		}
+	} else {
+		apiErr.Detail = strings.TrimSpace(string(body))
+	}
+
+	if len(apiErr.Detail) > maxErrorDetailLength {
+		apiErr.Detail = apiErr.Detail[:maxErrorDetailLength] + "..."
+	}
+
+	return apiErr
+}
+
+const maxErrorDetailLength = 256
+
+// CertificateError is returned when the server's certificate isn't trusted, or doesn't match
+// the host's pinned fingerprint. Fingerprint is the SHA-256 of the certificate the server presented.
+type CertificateError struct {
+	Fingerprint string
+	Err         error
+}
+
+func (e *CertificateError) Error() string {
+	if e.Fingerprint == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: %s", e.Err, FormatFingerprint(e.Fingerprint))
+}
+
+func (e *CertificateError) Unwrap() error {
+	return e.Err
+}
+
+// AsCertificateError reports whether err was caused by the server's certificate, turning
+// verification failures into an ErrUntrustedCertificate CertificateError.
+func AsCertificateError(err error) (*CertificateError, bool) {
+	var certErr *CertificateError
+	if errors.As(err, &certErr) {
+		return certErr, true
+	}
+
+	var verifyErr *tls.CertificateVerificationError
+	if errors.As(err, &verifyErr) {
+		certErr = &CertificateError{Err: ErrUntrustedCertificate}
+		if len(verifyErr.UnverifiedCertificates) > 0 {
+			certErr.Fingerprint = CertificateFingerprint(verifyErr.UnverifiedCertificates[0])
+		}
+		return certErr, true
+	}
+
+	return nil, false
+}
+
 type ProtocolError struct {
 	RequestedProtocol string
 	CorrectProtocol   string
@@ -54,6 +182,10 @@ func ClassifyError(err error) error {
 		return nil
 	}
 
+	if certErr, ok := AsCertificateError(err); ok {
+		return certErr
+	}
+
 	errMsg := err.Error()
 
 	var urlErr *url.Error
@@ -100,3 +232,24 @@ func ClassifyError(err error) error {
 
 	return err
 }
+
+// IsUnreachable reports whether err means the server couldn't be reached at all, as opposed to
+// the server answering with an error. These are the failures worth waiting out offline.
+func IsUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if _, ok := AsCertificateError(err); ok {
+		return false
+	}
+
+	classified := ClassifyError(err)
+	if errors.Is(classified, ErrInvalidHostname) || errors.Is(classified, ErrConnectionRefused) || errors.Is(classified, ErrTimeout) {
+		return true
+	}
+
+	// No route to the host, the network being down and the like
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
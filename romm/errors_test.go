@@ -0,0 +1,49 @@
+package romm_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func TestAPIErrors(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{})
+	defer server.Close()
+
+	_, err := server.Client().GetRom(42)
+
+	if !errors.Is(err, romm.ErrNotFound) {
+		t.Fatalf("GetRom() error = %v, want ErrNotFound", err)
+	}
+
+	var apiErr *romm.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetRom() error = %T, want *romm.APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Detail != "Rom with ID 42 not found" {
+		t.Errorf("APIError = %+v, want status 404 with RomM's detail", apiErr)
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{})
+	client := server.Client()
+
+	_, err := client.GetRom(42)
+	if romm.IsUnreachable(err) {
+		t.Errorf("IsUnreachable(%v) = true for a server that answered", err)
+	}
+
+	server.Close()
+
+	_, err = client.GetPlatforms()
+	if !romm.IsUnreachable(err) {
+		t.Errorf("IsUnreachable(%v) = false for a server that is down", err)
+	}
+	if romm.IsUnreachable(nil) {
+		t.Error("IsUnreachable(nil) = true")
+	}
+}
@@ -1,6 +1,7 @@
 package romm
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -34,8 +35,12 @@ func (fo FirmwareOptions) Valid() bool {
 }
 
 func (c *Client) GetFirmware(platformID int) ([]Firmware, error) {
+	return c.GetFirmwareContext(context.Background(), platformID)
+}
+
+func (c *Client) GetFirmwareContext(ctx context.Context, platformID int) ([]Firmware, error) {
 	var firmware []Firmware
-	err := c.doRequest("GET", endpointFirmware, FirmwareOptions{PlatformID: platformID}, nil, &firmware)
+	err := c.doRequest(ctx, "GET", endpointFirmware, FirmwareOptions{PlatformID: platformID}, nil, &firmware)
 	if err != nil {
 		return nil, err
 	}
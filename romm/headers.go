@@ -0,0 +1,43 @@
+package romm
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// headerTransport adds custom headers to the requests it sends to one host. Requests that end
+// up anywhere else, like artwork served from another domain, go out without them.
+type headerTransport struct {
+	host    string
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func newHeaderTransport(baseURL string, headers map[string]string, base http.RoundTripper) http.RoundTripper {
+	u, err := url.Parse(baseURL)
+	if len(headers) == 0 || err != nil {
+		return base
+	}
+	return &headerTransport{host: u.Host, headers: headers, base: base}
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if !strings.EqualFold(req.URL.Host, t.host) {
+		return base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,47 @@
+package romm_test
+
+import (
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func TestHostHeaders(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{Username: "player", Password: "secret", Roms: platformRoms(1, 5, 1)})
+	defer server.Close()
+
+	host := server.Host()
+	host.Headers = map[string]string{
+		"CF-Access-Client-Id":     "grout.access",
+		"CF-Access-Client-Secret": "s3cret",
+	}
+
+	client := romm.NewClientFromHost(host)
+	if err := client.ValidateConnection(); err != nil {
+		t.Fatalf("ValidateConnection() = %v", err)
+	}
+	if _, err := client.GetRoms(romm.GetRomsQuery{PlatformID: 1}); err != nil {
+		t.Fatalf("GetRoms() = %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) == 0 {
+		t.Fatal("server received no requests")
+	}
+	for _, r := range requests {
+		if got := r.Header.Get("CF-Access-Client-Secret"); got != "s3cret" {
+			t.Errorf("%s %s sent CF-Access-Client-Secret %q, want %q", r.Method, r.Path, got, "s3cret")
+		}
+	}
+
+	headers := client.DownloadHeaders()
+	if headers["CF-Access-Client-Id"] != "grout.access" || headers["Authorization"] == "" {
+		t.Errorf("DownloadHeaders() = %v, want the custom headers and Authorization", headers)
+	}
+
+	logged := host.ToLoggable()["headers"].(map[string]string)
+	if logged["CF-Access-Client-Secret"] != "******" {
+		t.Errorf("ToLoggable() headers = %v, want masked values", logged)
+	}
+}
@@ -1,7 +1,6 @@
 package romm
 
 import (
-	"encoding/base64"
 	"fmt"
 	"strings"
 )
@@ -12,21 +11,51 @@ type Host struct {
 	Port        int    `json:"port,omitempty"`
 
 	Username string `json:"username,omitempty"`
+	// Password is only kept until it has been exchanged for a Token, legacy configs still carry it
 	Password string `json:"password,omitempty"`
+	Token    *Token `json:"token,omitempty"`
+
+	// ServerVersion is read from the heartbeat at login and on startup
+	ServerVersion string `json:"server_version,omitempty"`
+
+	// CABundle is a PEM file of extra certificate authorities to trust for this host,
+	// relative paths are resolved from Grout's folder
+	CABundle string `json:"ca_bundle,omitempty"`
+	// PinnedCertSHA256 is the hex SHA-256 of the server certificate accepted at login,
+	// when set the host is trusted by this certificate alone
+	PinnedCertSHA256 string `json:"pinned_cert_sha256,omitempty"`
+
+	// Headers are sent with every request to the host, for reverse proxies and zero-trust
+	// gateways that need their own tokens
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 func (h Host) ToLoggable() map[string]any {
 	temp := map[string]any{
-		"display_name": h.DisplayName,
-		"root_uri":     h.RootURI,
-		"port":         h.Port,
-		"username":     h.Username,
-		"password":     strings.Repeat("*", len(h.Password)),
+		"display_name":   h.DisplayName,
+		"root_uri":       h.RootURI,
+		"port":           h.Port,
+		"username":       h.Username,
+		"password":       strings.Repeat("*", len(h.Password)),
+		"has_token":      h.Token != nil,
+		"server_version": h.ServerVersion,
+		"ca_bundle":      h.CABundle,
+		"pinned_cert":    h.PinnedCertSHA256,
+		"headers":        maskHeaders(h.Headers),
 	}
 
 	return temp
 }
 
+// maskHeaders keeps the header names for the log but hides their values, which are usually secrets.
+func maskHeaders(headers map[string]string) map[string]string {
+	masked := make(map[string]string, len(headers))
+	for name, value := range headers {
+		masked[name] = strings.Repeat("*", len(value))
+	}
+	return masked
+}
+
 func (h Host) URL() string {
 	if h.Port != 0 {
 		return fmt.Sprintf("%s:%d", h.RootURI, h.Port)
@@ -34,7 +63,24 @@ func (h Host) URL() string {
 	return h.RootURI
 }
 
-func (h Host) BasicAuthHeader() string {
-	auth := h.Username + ":" + h.Password
-	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+// Key identifies the account on a server, two hosts with the same key are the same login.
+func (h Host) Key() string {
+	return strings.ToLower(fmt.Sprintf("%s@%s", h.Username, h.URL()))
+}
+
+// Label is the name shown for the host when choosing between servers.
+func (h Host) Label() string {
+	if h.DisplayName != "" {
+		return h.DisplayName
+	}
+	address := strings.TrimPrefix(strings.TrimPrefix(h.URL(), "https://"), "http://")
+	if h.Username == "" {
+		return address
+	}
+	return fmt.Sprintf("%s@%s", h.Username, address)
+}
+
+// Capabilities reports the optional API features of this host's RomM version.
+func (h Host) Capabilities() Capabilities {
+	return CapabilitiesFor(h.ServerVersion)
 }
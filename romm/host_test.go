@@ -0,0 +1,37 @@
+package romm_test
+
+import (
+	"testing"
+
+	"grout/romm"
+)
+
+func TestHostKey(t *testing.T) {
+	home := romm.Host{RootURI: "http://romm.local", Port: 8080, Username: "Player"}
+
+	if got, want := home.Key(), "player@http://romm.local:8080"; got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+	if got, want := home.Label(), "Player@romm.local:8080"; got != want {
+		t.Errorf("Label() = %q, want %q", got, want)
+	}
+
+	relogin := home
+	relogin.Username = "player"
+	relogin.Token = &romm.Token{}
+	if relogin.Key() != home.Key() {
+		t.Errorf("Key() changed with username case or token: %q != %q", relogin.Key(), home.Key())
+	}
+
+	other := home
+	other.Port = 8081
+	if other.Key() == home.Key() {
+		t.Errorf("Key() = %q for hosts on different ports", other.Key())
+	}
+
+	named := home
+	named.DisplayName = "Home"
+	if got := named.Label(); got != "Home" {
+		t.Errorf("Label() with a display name = %q, want %q", got, "Home")
+	}
+}
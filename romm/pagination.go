@@ -0,0 +1,72 @@
+package romm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// DefaultRomPageSize is the page size used when a query doesn't set a limit.
+const DefaultRomPageSize = 200
+
+var errStopWalk = errors.New("stop walking pages")
+
+// WalkRoms fetches every page matching query, calling onPage for each non-empty page in order.
+// Paging starts at query.Offset and stops on an empty or short page or once Total items have
+// been seen. An error from onPage stops the walk and is returned as is.
+func (c *Client) WalkRoms(ctx context.Context, query GetRomsQuery, onPage func(page PaginatedRoms) error) error {
+	if query.Limit <= 0 {
+		query.Limit = DefaultRomPageSize
+	}
+
+	fetched := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := c.GetRomsContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to fetch roms at offset %d: %w", query.Offset, err)
+		}
+
+		fetched += len(page.Items)
+
+		if len(page.Items) > 0 {
+			if err := onPage(page); err != nil {
+				return err
+			}
+		}
+
+		// The server may cap the page size below what was asked for
+		limit := query.Limit
+		if page.Limit > 0 && page.Limit < limit {
+			limit = page.Limit
+		}
+
+		if len(page.Items) == 0 || len(page.Items) < limit || (page.Total > 0 && fetched >= page.Total) {
+			return nil
+		}
+
+		query.Offset += len(page.Items)
+	}
+}
+
+// AllRoms iterates over every ROM matching query, fetching pages as the loop advances.
+// A failure is yielded once as the final element; breaking out of the loop stops paging.
+func (c *Client) AllRoms(ctx context.Context, query GetRomsQuery) iter.Seq2[Rom, error] {
+	return func(yield func(Rom, error) bool) {
+		err := c.WalkRoms(ctx, query, func(page PaginatedRoms) error {
+			for _, rom := range page.Items {
+				if !yield(rom, nil) {
+					return errStopWalk
+				}
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopWalk) {
+			yield(Rom{}, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package romm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func platformRoms(platformID, count, firstID int) []romm.Rom {
+	roms := make([]romm.Rom, count)
+	for i := range roms {
+		roms[i] = romm.Rom{
+			ID:         firstID + i,
+			PlatformID: platformID,
+			Name:       fmt.Sprintf("Game %04d", i),
+		}
+	}
+	return roms
+}
+
+func TestWalkRomsPagination(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int
+		limit       int
+		maxPageSize int
+		wantPages   int
+	}{
+		{"exact multiple of page size", 400, 100, 0, 4},
+		{"short last page", 450, 100, 0, 5},
+		{"single partial page", 7, 100, 0, 1},
+		{"empty platform", 0, 100, 0, 1},
+		{"server caps page size", 250, 200, 50, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roms := append(platformRoms(1, tt.total, 1), platformRoms(2, 30, 100000)...)
+			server := rommtest.NewServer(rommtest.Fixture{Roms: roms, MaxPageSize: tt.maxPageSize})
+			defer server.Close()
+
+			var got []romm.Rom
+			pages := 0
+			err := server.Client().WalkRoms(context.Background(), romm.GetRomsQuery{PlatformID: 1, Limit: tt.limit}, func(page romm.PaginatedRoms) error {
+				pages++
+				got = append(got, page.Items...)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WalkRoms() error = %v", err)
+			}
+
+			if len(got) != tt.total {
+				t.Errorf("WalkRoms() returned %d roms, want %d", len(got), tt.total)
+			}
+			for i, rom := range got {
+				if rom.ID != i+1 {
+					t.Fatalf("rom %d has ID %d, want %d", i, rom.ID, i+1)
+				}
+			}
+			if requests := countRequests(server, http.MethodGet, "/api/roms"); requests != tt.wantPages {
+				t.Errorf("made %d page requests, want %d", requests, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestAllRomsStopsWhenLoopBreaks(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{Roms: platformRoms(1, 500, 1)})
+	defer server.Close()
+
+	seen := 0
+	for _, err := range server.Client().AllRoms(context.Background(), romm.GetRomsQuery{PlatformID: 1, Limit: 100}) {
+		if err != nil {
+			t.Fatalf("AllRoms() error = %v", err)
+		}
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+
+	if requests := countRequests(server, http.MethodGet, "/api/roms"); requests != 1 {
+		t.Errorf("made %d page requests after breaking on the first page, want 1", requests)
+	}
+}
+
+func TestAllRomsYieldsPageErrors(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{Roms: platformRoms(1, 300, 1)})
+	defer server.Close()
+
+	// Let the first page through, then fail every request
+	server.AddHook(func() rommtest.Hook {
+		served := 0
+		fail := rommtest.FailTimes(rommtest.Match(http.MethodGet, "/api/roms"), http.StatusInternalServerError, -1)
+		return func(w http.ResponseWriter, r *http.Request) bool {
+			if r.URL.Path != "/api/roms" {
+				return false
+			}
+			served++
+			return served > 1 && fail(w, r)
+		}
+	}())
+
+	var gotErr error
+	seen := 0
+	for _, err := range server.Client().AllRoms(context.Background(), romm.GetRomsQuery{PlatformID: 1, Limit: 100}) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		seen++
+	}
+
+	if seen != 100 {
+		t.Errorf("AllRoms() yielded %d roms before failing, want 100", seen)
+	}
+	if !errors.Is(gotErr, romm.ErrServerError) {
+		t.Errorf("AllRoms() error = %v, want ErrServerError", gotErr)
+	}
+}
+
+func TestWalkRomsCancellation(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{Roms: platformRoms(1, 500, 1)})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := server.Client().WalkRoms(ctx, romm.GetRomsQuery{PlatformID: 1, Limit: 100}, func(romm.PaginatedRoms) error {
+		cancel()
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WalkRoms() error = %v, want context.Canceled", err)
+	}
+	if requests := countRequests(server, http.MethodGet, "/api/roms"); requests != 1 {
+		t.Errorf("made %d page requests after cancelling, want 1", requests)
+	}
+}
@@ -1,6 +1,7 @@
 package romm
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -34,15 +35,23 @@ func (p Platform) DisplayName() string {
 }
 
 func (c *Client) GetPlatforms() ([]Platform, error) {
+	return c.GetPlatformsContext(context.Background())
+}
+
+func (c *Client) GetPlatformsContext(ctx context.Context) ([]Platform, error) {
 	var platforms []Platform
-	err := c.doRequest("GET", endpointPlatforms, nil, nil, &platforms)
+	err := c.doRequest(ctx, "GET", endpointPlatforms, nil, nil, &platforms)
 	return platforms, err
 }
 
 func (c *Client) GetPlatform(id int) (Platform, error) {
+	return c.GetPlatformContext(context.Background(), id)
+}
+
+func (c *Client) GetPlatformContext(ctx context.Context, id int) (Platform, error) {
 	var platform Platform
 	path := fmt.Sprintf(endpointPlatformByID, id)
-	err := c.doRequest("GET", path, nil, nil, &platform)
+	err := c.doRequest(ctx, "GET", path, nil, nil, &platform)
 	return platform, err
 }
 
@@ -0,0 +1,62 @@
+package romm_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func countRequests(server *rommtest.Server, method, path string) int {
+	count := 0
+	for _, r := range server.Requests() {
+		if r.Method == method && r.Path == path {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{
+		Platforms: []romm.Platform{{ID: 1, Slug: "gba", FSSlug: "gba", Name: "Game Boy Advance"}},
+	}, rommtest.WithHook(rommtest.FailTimes(rommtest.Match(http.MethodGet, "/api/platforms"), http.StatusServiceUnavailable, 2)))
+	defer server.Close()
+
+	client := server.Client(romm.WithRetryPolicy(romm.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}))
+
+	platforms, err := client.GetPlatforms()
+	if err != nil {
+		t.Fatalf("GetPlatforms() error = %v", err)
+	}
+	if len(platforms) != 1 {
+		t.Errorf("GetPlatforms() returned %d platforms, want 1", len(platforms))
+	}
+	if requests := countRequests(server, http.MethodGet, "/api/platforms"); requests != 3 {
+		t.Errorf("made %d requests, want 3", requests)
+	}
+}
+
+func TestRetryDoesNotRepeatUploads(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{Roms: platformRoms(1, 1, 1)},
+		rommtest.WithHook(rommtest.FailTimes(rommtest.Match(http.MethodPost, "/api/saves"), http.StatusBadGateway, 1)))
+	defer server.Close()
+
+	client := server.Client(romm.WithRetryPolicy(romm.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+
+	_, err := client.UploadSaveReader(1, "game.srm", strings.NewReader("save"), "mgba")
+	if !errors.Is(err, romm.ErrServerError) {
+		t.Errorf("UploadSaveReader() error = %v, want ErrServerError", err)
+	}
+	if requests := countRequests(server, http.MethodPost, "/api/saves"); requests != 1 {
+		t.Errorf("made %d upload requests, want 1", requests)
+	}
+}
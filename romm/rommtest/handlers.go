@@ -0,0 +1,696 @@
+package rommtest
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"grout/romm"
+)
+
+func (s *Server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/heartbeat", s.handleHeartbeat)
+	mux.HandleFunc("POST /api/token", s.handleToken)
+
+	mux.HandleFunc("GET /api/users/me", s.scoped("me.read", s.handleCurrentUser))
+
+	mux.HandleFunc("GET /api/platforms", s.scoped("platforms.read", s.handlePlatforms))
+	mux.HandleFunc("GET /api/platforms/{id}", s.scoped("platforms.read", s.handlePlatform))
+
+	mux.HandleFunc("GET /api/roms", s.scoped("roms.read", s.handleRoms))
+	mux.HandleFunc("POST /api/roms", s.scoped("roms.write", s.handleRomUpload))
+	mux.HandleFunc("GET /api/roms/by-hash", s.scoped("roms.read", s.handleRomByHash))
+	mux.HandleFunc("GET /api/roms/download", s.scoped("roms.read", s.handleRomsDownload))
+	mux.HandleFunc("GET /api/roms/{id}", s.scoped("roms.read", s.handleRom))
+	mux.HandleFunc("GET /api/roms/{id}/content/{file}", s.scoped("roms.read", s.handleRomContent))
+
+	mux.HandleFunc("GET /api/collections", s.scoped("collections.read", s.handleCollections))
+	mux.HandleFunc("POST /api/collections", s.scoped("collections.write", s.handleCollectionCreate))
+	mux.HandleFunc("GET /api/collections/smart", s.scoped("collections.read", s.handleSmartCollections))
+	mux.HandleFunc("GET /api/collections/virtual", s.scoped("collections.read", s.handleVirtualCollections))
+	mux.HandleFunc("GET /api/collections/{id}", s.scoped("collections.read", s.handleCollection))
+	mux.HandleFunc("PUT /api/collections/{id}", s.scoped("collections.write", s.handleCollectionUpdate))
+	mux.HandleFunc("DELETE /api/collections/{id}", s.scoped("collections.write", s.handleCollectionDelete))
+
+	mux.HandleFunc("GET /api/firmware", s.scoped("firmware.read", s.handleFirmware))
+	mux.HandleFunc("GET /api/firmware/{id}/content/{file}", s.scoped("firmware.read", s.handleFirmwareContent))
+
+	mux.HandleFunc("GET /api/saves", s.scoped("assets.read", s.handleSaves(saveKindSave)))
+	mux.HandleFunc("POST /api/saves", s.scoped("assets.write", s.handleSaveUpload(saveKindSave)))
+	mux.HandleFunc("GET /api/saves/{id}/content/{file}", s.scoped("assets.read", s.handleSaveContent(saveKindSave)))
+
+	mux.HandleFunc("GET /api/states", s.scoped("assets.read", s.handleSaves(saveKindState)))
+	mux.HandleFunc("POST /api/states", s.scoped("assets.write", s.handleSaveUpload(saveKindState)))
+	mux.HandleFunc("GET /api/states/{id}/content/{file}", s.scoped("assets.read", s.handleSaveContent(saveKindState)))
+
+	return mux
+}
+
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"SYSTEM": map[string]any{"VERSION": s.fixture.Version},
+	})
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var scopes []string
+	switch r.PostForm.Get("grant_type") {
+	case "password":
+		if r.PostForm.Get("username") != s.fixture.Username || r.PostForm.Get("password") != s.fixture.Password {
+			writeError(w, http.StatusUnauthorized, "Invalid username or password")
+			return
+		}
+
+		// Like RomM, a login asking for more than the user's role allows is refused
+		allowed := roleScopes(s.fixture.Role)
+		scopes = strings.Fields(r.PostForm.Get("scope"))
+		if len(scopes) == 0 {
+			scopes = allowed
+		}
+		for _, scope := range scopes {
+			if !slices.Contains(allowed, scope) {
+				writeError(w, http.StatusForbidden, "Insufficient scope")
+				return
+			}
+		}
+	case "refresh_token":
+		refreshToken := r.PostForm.Get("refresh_token")
+		granted, ok := s.refreshTokens[refreshToken]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Invalid refresh token")
+			return
+		}
+		delete(s.refreshTokens, refreshToken)
+		scopes = granted
+	default:
+		writeError(w, http.StatusBadRequest, "Unsupported grant type")
+		return
+	}
+
+	s.tokenSerial++
+	accessToken := fmt.Sprintf("access-%d", s.tokenSerial)
+	refreshToken := fmt.Sprintf("refresh-%d", s.tokenSerial)
+	s.accessTokens[accessToken] = scopes
+	s.refreshTokens[refreshToken] = scopes
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "bearer",
+		"expires_in":    int((30 * time.Minute).Seconds()),
+	})
+}
+
+func (s *Server) handleCurrentUser(w http.ResponseWriter, r *http.Request) {
+	role := s.fixture.Role
+	if role == "" {
+		role = romm.RoleAdmin
+	}
+	writeJSON(w, http.StatusOK, romm.User{ID: 1, Username: s.fixture.Username, Role: role, Enabled: true})
+}
+
+func (s *Server) handlePlatforms(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, nonNil(s.fixture.Platforms))
+}
+
+func (s *Server) handlePlatform(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, found := s.findPlatform(id); found {
+		writeJSON(w, http.StatusOK, p)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Platform with ID %d not found", id)
+}
+
+func (s *Server) handleRoms(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	offset := queryInt(query.Get("offset"))
+	limit := queryInt(query.Get("limit"))
+	platformID := queryInt(query.Get("platform_id"))
+	collectionID := queryInt(query.Get("collection_id"))
+	smartCollectionID := queryInt(query.Get("smart_collection_id"))
+	virtualCollectionID := query.Get("virtual_collection_id")
+	search := strings.ToLower(query.Get("search"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var romIDs []int
+	filterByIDs := false
+	switch {
+	case collectionID != 0:
+		romIDs, filterByIDs = collectionRomIDs(s.fixture.Collections, collectionID), true
+	case smartCollectionID != 0:
+		romIDs, filterByIDs = collectionRomIDs(s.fixture.SmartCollections, smartCollectionID), true
+	case virtualCollectionID != "":
+		filterByIDs = true
+		for _, vc := range s.fixture.VirtualCollections {
+			if vc.ID == virtualCollectionID {
+				romIDs = vc.ROMIDs
+			}
+		}
+	}
+
+	matches := make([]romm.Rom, 0, len(s.fixture.Roms))
+	for _, rom := range s.fixture.Roms {
+		if platformID != 0 && rom.PlatformID != platformID {
+			continue
+		}
+		if filterByIDs && !slices.Contains(romIDs, rom.ID) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(rom.Name), search) {
+			continue
+		}
+		matches = append(matches, rom)
+	}
+
+	if limit <= 0 {
+		limit = len(matches)
+	}
+	if s.fixture.MaxPageSize > 0 && limit > s.fixture.MaxPageSize {
+		limit = s.fixture.MaxPageSize
+	}
+
+	page := []romm.Rom{}
+	if offset < len(matches) {
+		page = matches[offset:min(offset+limit, len(matches))]
+	}
+
+	writeJSON(w, http.StatusOK, romm.PaginatedRoms{
+		Items:  page,
+		Total:  len(matches),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func (s *Server) handleRom(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rom, found := s.findRom(id); found {
+		writeJSON(w, http.StatusOK, rom)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Rom with ID %d not found", id)
+}
+
+// handleRomUpload stores an uploaded ROM and lists it right away, as if RomM had
+// scanned the library straight after the upload.
+func (s *Server) handleRomUpload(w http.ResponseWriter, r *http.Request) {
+	platformID := queryInt(r.Header.Get("X-Upload-Platform"))
+	fileName := r.Header.Get("X-Upload-Filename")
+	if fileName == "" {
+		writeError(w, http.StatusBadRequest, "No filename provided")
+		return
+	}
+
+	file, _, err := r.FormFile(fileName)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Missing %s: %v", fileName, err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to read upload: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	platform, found := s.findPlatform(platformID)
+	if !found {
+		writeError(w, http.StatusNotFound, "Platform with ID %d not found", platformID)
+		return
+	}
+
+	for _, rom := range s.fixture.Roms {
+		if rom.PlatformID == platformID && rom.FsName == fileName {
+			writeError(w, http.StatusBadRequest, "File %s already exists", fileName)
+			return
+		}
+	}
+
+	ext := path.Ext(fileName)
+	s.nextID++
+	s.fixture.Roms = append(s.fixture.Roms, romm.Rom{
+		ID:             s.nextID,
+		PlatformID:     platform.ID,
+		PlatformSlug:   platform.Slug,
+		PlatformFSSlug: platform.FSSlug,
+		FsName:         fileName,
+		FsNameNoExt:    strings.TrimSuffix(fileName, ext),
+		FsExtension:    strings.TrimPrefix(ext, "."),
+		FsSizeBytes:    len(data),
+		Name:           strings.TrimSuffix(fileName, ext),
+	})
+	s.fixture.Content[ContentKey{RomID: s.nextID, FileName: fileName}] = data
+
+	writeJSON(w, http.StatusCreated, map[string]any{"uploaded_roms": []string{fileName}, "skipped_roms": []string{}})
+}
+
+func (s *Server) handleRomByHash(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	crc, md5, sha := query.Get("crc_hash"), query.Get("md5_hash"), query.Get("sha1_hash")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rom := range s.fixture.Roms {
+		if (crc != "" && strings.EqualFold(rom.CrcHash, crc)) ||
+			(md5 != "" && strings.EqualFold(rom.Md5Hash, md5)) ||
+			(sha != "" && strings.EqualFold(rom.Sha1Hash, sha)) {
+			writeJSON(w, http.StatusOK, rom)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Rom not found")
+}
+
+func (s *Server) handleRomsDownload(w http.ResponseWriter, r *http.Request) {
+	var ids []int
+	for _, raw := range strings.Split(r.URL.Query().Get("rom_ids"), ",") {
+		if id, err := strconv.Atoi(raw); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	s.mu.Lock()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for key, data := range s.fixture.Content {
+		if len(ids) > 0 && !slices.Contains(ids, key.RomID) {
+			continue
+		}
+		f, err := zw.Create(key.FileName)
+		if err == nil {
+			f.Write(data)
+		}
+	}
+	s.mu.Unlock()
+
+	if err := zw.Close(); err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to build archive: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Write(buf.Bytes())
+}
+
+// handleRomContent serves ROM bytes through http.ServeContent, which gives HEAD, Range and
+// If-Range handling that behaves like RomM's file responses.
+func (s *Server) handleRomContent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	fileName := r.PathValue("file")
+
+	s.mu.Lock()
+	data, found := s.fixture.Content[ContentKey{RomID: id, FileName: fileName}]
+	modTime := s.fixture.ModTime
+	s.mu.Unlock()
+
+	if !found {
+		writeError(w, http.StatusNotFound, "File %s not found for rom %d", fileName, id)
+		return
+	}
+
+	serveBytes(w, r, fileName, modTime, data)
+}
+
+func (s *Server) handleCollections(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, nonNil(s.fixture.Collections))
+}
+
+func (s *Server) handleSmartCollections(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, nonNil(s.fixture.SmartCollections))
+}
+
+func (s *Server) handleVirtualCollections(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("type") == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Field required: type")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, nonNil(s.fixture.VirtualCollections))
+}
+
+func (s *Server) handleCollection(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.fixture.Collections {
+		if c.ID == id {
+			writeJSON(w, http.StatusOK, c)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Collection with ID %d not found", id)
+}
+
+func (s *Server) handleCollectionCreate(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Collection name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	s.nextID++
+	collection := romm.Collection{
+		ID:          s.nextID,
+		Name:        name,
+		Description: r.FormValue("description"),
+		ROMIDs:      []int{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.fixture.Collections = append(s.fixture.Collections, collection)
+
+	writeJSON(w, http.StatusOK, collection)
+}
+
+// handleCollectionUpdate replaces the collection's fields and games, like RomM does with
+// the rom_ids form value.
+func (s *Server) handleCollectionUpdate(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var romIDs []int
+	if err := json.Unmarshal([]byte(r.FormValue("rom_ids")), &romIDs); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid rom_ids: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.fixture.Collections {
+		c := &s.fixture.Collections[i]
+		if c.ID != id {
+			continue
+		}
+		if name := r.FormValue("name"); name != "" {
+			c.Name = name
+		}
+		c.Description = r.FormValue("description")
+		c.ROMIDs = nonNil(romIDs)
+		c.ROMCount = len(romIDs)
+		c.UpdatedAt = time.Now().UTC()
+		writeJSON(w, http.StatusOK, c)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Collection with ID %d not found", id)
+}
+
+func (s *Server) handleCollectionDelete(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, c := range s.fixture.Collections {
+		if c.ID == id {
+			s.fixture.Collections = slices.Delete(s.fixture.Collections, i, i+1)
+			writeJSON(w, http.StatusOK, map[string]string{"msg": fmt.Sprintf("%s deleted successfully!", c.Name)})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Collection with ID %d not found", id)
+}
+
+func (s *Server) handleFirmware(w http.ResponseWriter, r *http.Request) {
+	platformID := queryInt(r.URL.Query().Get("platform_id"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	firmware := []romm.Firmware{}
+	for _, f := range s.fixture.Firmware {
+		if platformID == 0 || f.PlatformID == platformID {
+			firmware = append(firmware, f.Firmware)
+		}
+	}
+	writeJSON(w, http.StatusOK, firmware)
+}
+
+func (s *Server) handleFirmwareContent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	var file *FirmwareFile
+	for i := range s.fixture.Firmware {
+		if s.fixture.Firmware[i].Firmware.ID == id {
+			file = &s.fixture.Firmware[i]
+		}
+	}
+	modTime := s.fixture.ModTime
+	s.mu.Unlock()
+
+	if file == nil {
+		writeError(w, http.StatusNotFound, "Firmware with ID %d not found", id)
+		return
+	}
+
+	serveBytes(w, r, file.Firmware.FileName, modTime, file.Content)
+}
+
+// saveKind describes one of the two save stores. RomM serves saves and states
+// with the same schema, only the paths and the upload field differ.
+type saveKind struct {
+	Name      string
+	Path      string
+	FormField string
+}
+
+var (
+	saveKindSave  = saveKind{Name: "Save", Path: "saves", FormField: "saveFile"}
+	saveKindState = saveKind{Name: "State", Path: "states", FormField: "stateFile"}
+)
+
+// store must be called with s.mu held.
+func (s *Server) store(kind saveKind) *[]SaveFile {
+	if kind == saveKindState {
+		return &s.fixture.States
+	}
+	return &s.fixture.Saves
+}
+
+func (s *Server) handleSaves(kind saveKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		romID := queryInt(query.Get("rom_id"))
+		platformID := queryInt(query.Get("platform_id"))
+		emulator := query.Get("emulator")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		saves := []romm.Save{}
+		for _, save := range *s.store(kind) {
+			if romID != 0 && save.Save.RomID != romID {
+				continue
+			}
+			if platformID != 0 {
+				if rom, found := s.findRom(save.Save.RomID); !found || rom.PlatformID != platformID {
+					continue
+				}
+			}
+			if emulator != "" && save.Save.Emulator != emulator {
+				continue
+			}
+			saves = append(saves, save.Save)
+		}
+		writeJSON(w, http.StatusOK, saves)
+	}
+}
+
+func (s *Server) handleSaveUpload(kind saveKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		romID := queryInt(r.URL.Query().Get("rom_id"))
+		emulator := r.URL.Query().Get("emulator")
+
+		file, header, err := r.FormFile(kind.FormField)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Missing %s: %v", kind.FormField, err)
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Failed to read upload: %v", err)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, found := s.findRom(romID); !found {
+			writeError(w, http.StatusNotFound, "Rom with ID %d not found", romID)
+			return
+		}
+
+		now := time.Now().UTC()
+		s.nextID++
+		save := romm.Save{
+			ID:            s.nextID,
+			RomID:         romID,
+			FileName:      header.Filename,
+			FileExtension: strings.TrimPrefix(path.Ext(header.Filename), "."),
+			FileSizeBytes: len(data),
+			DownloadPath:  fmt.Sprintf("/api/%s/%d/content/%s", kind.Path, s.nextID, header.Filename),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			Emulator:      emulator,
+		}
+		store := s.store(kind)
+		*store = append(*store, SaveFile{Save: save, Content: data})
+
+		writeJSON(w, http.StatusOK, save)
+	}
+}
+
+func (s *Server) handleSaveContent(kind saveKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		var file *SaveFile
+		store := *s.store(kind)
+		for i := range store {
+			if store[i].Save.ID == id {
+				file = &store[i]
+			}
+		}
+		s.mu.Unlock()
+
+		if file == nil {
+			writeError(w, http.StatusNotFound, "%s with ID %d not found", kind.Name, id)
+			return
+		}
+
+		serveBytes(w, r, file.Save.FileName, file.Save.UpdatedAt, file.Content)
+	}
+}
+
+// findRom must be called with s.mu held.
+func (s *Server) findPlatform(id int) (romm.Platform, bool) {
+	for _, p := range s.fixture.Platforms {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return romm.Platform{}, false
+}
+
+func (s *Server) findRom(id int) (romm.Rom, bool) {
+	for _, rom := range s.fixture.Roms {
+		if rom.ID == id {
+			return rom, true
+		}
+	}
+	return romm.Rom{}, false
+}
+
+func serveBytes(w http.ResponseWriter, r *http.Request, name string, modTime time.Time, data []byte) {
+	sum := sha1.Sum(data)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	http.ServeContent(w, r, name, modTime, bytes.NewReader(data))
+}
+
+func collectionRomIDs(collections []romm.Collection, id int) []int {
+	for _, c := range collections {
+		if c.ID == id {
+			return c.ROMIDs
+		}
+	}
+	return nil
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid id %q", r.PathValue("id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func queryInt(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
+
+// nonNil keeps empty fixtures encoding as [] like RomM does, rather than null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
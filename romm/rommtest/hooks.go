@@ -0,0 +1,105 @@
+package rommtest
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Matcher selects the requests a hook applies to.
+type Matcher func(r *http.Request) bool
+
+// Match matches requests with the given method and a path starting with pathPrefix.
+// An empty method matches any method.
+func Match(method, pathPrefix string) Matcher {
+	return func(r *http.Request) bool {
+		return (method == "" || r.Method == method) && strings.HasPrefix(r.URL.Path, pathPrefix)
+	}
+}
+
+// Any matches every request.
+func Any() Matcher {
+	return func(*http.Request) bool { return true }
+}
+
+// Latency delays matching requests by d before they are handled.
+func Latency(match Matcher, d time.Duration) Hook {
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		if match(r) {
+			select {
+			case <-time.After(d):
+			case <-r.Context().Done():
+			}
+		}
+		return false
+	}
+}
+
+// FailTimes answers the first times matching requests with status and a RomM style error body.
+// A negative times fails every matching request.
+func FailTimes(match Matcher, status int, times int) Hook {
+	var count atomic.Int64
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		if !match(r) {
+			return false
+		}
+		if times >= 0 && count.Add(1) > int64(times) {
+			return false
+		}
+		writeError(w, status, "Injected failure")
+		return true
+	}
+}
+
+// DropConnection closes the connection without a response for the first times matching
+// requests, the way a flaky network does.
+func DropConnection(match Matcher, times int) Hook {
+	var count atomic.Int64
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		if !match(r) || count.Add(1) > int64(times) {
+			return false
+		}
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			return false
+		}
+		conn, _, err := hijacker.Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return true
+	}
+}
+
+// TruncateBody serves the first times matching requests normally but aborts the connection
+// after n bytes of body, simulating a download interrupted part way.
+func (s *Server) TruncateBody(match Matcher, n int64, times int) Hook {
+	var count atomic.Int64
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		if !match(r) || !s.authorized(r) || count.Add(1) > int64(times) {
+			return false
+		}
+		s.mux.ServeHTTP(&truncatingWriter{ResponseWriter: w, remaining: n}, r)
+		return true
+	}
+}
+
+type truncatingWriter struct {
+	http.ResponseWriter
+	remaining int64
+}
+
+func (t *truncatingWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) <= t.remaining {
+		t.remaining -= int64(len(p))
+		return t.ResponseWriter.Write(p)
+	}
+
+	t.ResponseWriter.Write(p[:t.remaining])
+	if flusher, ok := t.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	// net/http closes the connection without logging when a handler aborts this way
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,32 @@
+package rommtest
+
+import (
+	"slices"
+
+	"grout/romm"
+)
+
+// The scopes RomM allows each role. Every role has the scopes of the roles below it.
+var (
+	viewerScopes = []string{
+		"me.read", "me.write",
+		"roms.read", "roms.user.read", "roms.user.write",
+		"platforms.read",
+		"assets.read", "assets.write",
+		"firmware.read",
+		"collections.read", "collections.write",
+	}
+	editorScopes = slices.Concat(viewerScopes, []string{"roms.write", "platforms.write", "firmware.write"})
+	adminScopes  = slices.Concat(editorScopes, []string{"users.read", "users.write", "tasks.run"})
+)
+
+// roleScopes returns the scopes a user with role may be granted. Fixtures without a role are admins.
+func roleScopes(role string) []string {
+	switch role {
+	case romm.RoleViewer:
+		return viewerScopes
+	case romm.RoleEditor:
+		return editorScopes
+	}
+	return adminScopes
+}
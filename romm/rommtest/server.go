@@ -0,0 +1,272 @@
+// Package rommtest runs an in-process fake RomM server backed by an in-memory fixture,
+// so romm.Client and the code built on it can be tested without a real RomM instance.
+package rommtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"grout/romm"
+)
+
+// DefaultVersion is reported by the heartbeat when the fixture doesn't set one.
+const DefaultVersion = "4.5.0"
+
+// ContentKey addresses a ROM file served from /api/roms/{id}/content/{file}.
+type ContentKey struct {
+	RomID    int
+	FileName string
+}
+
+// FirmwareFile is a firmware entry together with the bytes served for it.
+type FirmwareFile struct {
+	PlatformID int
+	Firmware   romm.Firmware
+	Content    []byte
+}
+
+// SaveFile is a save or state entry together with the bytes served for it.
+type SaveFile struct {
+	Save    romm.Save
+	Content []byte
+}
+
+// Fixture is the data the fake server starts with. The server takes ownership of it.
+type Fixture struct {
+	Version string
+
+	// Username and Password enable authentication. Without them every request is accepted.
+	Username string
+	Password string
+	// Role limits the scopes the user can get tokens for, like RomM's roles. Empty is admin.
+	Role string
+
+	Platforms          []romm.Platform
+	Roms               []romm.Rom
+	Collections        []romm.Collection
+	SmartCollections   []romm.Collection
+	VirtualCollections []romm.VirtualCollection
+	Firmware           []FirmwareFile
+	Saves              []SaveFile
+	States             []SaveFile
+	Content            map[ContentKey][]byte
+
+	// MaxPageSize caps the limit of ROM listings, zero means no cap
+	MaxPageSize int
+	// ModTime is reported as Last-Modified for content downloads
+	ModTime time.Time
+}
+
+// Hook runs before a request is routed. It returns true when it has written the response itself.
+type Hook func(w http.ResponseWriter, r *http.Request) bool
+
+// Request is a request the server received, recorded for assertions.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+}
+
+type Server struct {
+	*httptest.Server
+
+	mux      *http.ServeMux
+	mu       sync.Mutex
+	fixture  Fixture
+	hooks    []Hook
+	requests []Request
+	nextID   int
+
+	// Issued tokens and the scopes they were granted
+	accessTokens  map[string][]string
+	refreshTokens map[string][]string
+	tokenSerial   int
+}
+
+type Option func(*Server)
+
+// WithHook registers hooks that run before every request, in order.
+func WithHook(hooks ...Hook) Option {
+	return func(s *Server) {
+		s.hooks = append(s.hooks, hooks...)
+	}
+}
+
+// NewServer starts a fake RomM server serving fixture. Call Close when done.
+func NewServer(fixture Fixture, opts ...Option) *Server {
+	if fixture.Version == "" {
+		fixture.Version = DefaultVersion
+	}
+	if fixture.Content == nil {
+		fixture.Content = make(map[ContentKey][]byte)
+	}
+	if fixture.ModTime.IsZero() {
+		fixture.ModTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	s := &Server{
+		fixture:       fixture,
+		nextID:        1000,
+		accessTokens:  make(map[string][]string),
+		refreshTokens: make(map[string][]string),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux = s.routes()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Host returns a romm.Host pointing at the server with the fixture's credentials.
+func (s *Server) Host() romm.Host {
+	return romm.Host{
+		RootURI:       s.URL,
+		Username:      s.fixture.Username,
+		Password:      s.fixture.Password,
+		ServerVersion: s.fixture.Version,
+	}
+}
+
+// Client returns a romm.Client for the server. Retries are disabled unless opts enable them,
+// which keeps injected failures visible to the test.
+func (s *Server) Client(opts ...romm.ClientOption) *romm.Client {
+	opts = append([]romm.ClientOption{
+		romm.WithBasicAuth(s.fixture.Username, s.fixture.Password),
+		romm.WithRetryPolicy(romm.NoRetry),
+	}, opts...)
+	return romm.NewClient(s.URL, opts...)
+}
+
+// AddHook registers a hook on a running server.
+func (s *Server) AddHook(hook Hook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// Requests returns every request received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Saves returns the saves currently stored, including uploads.
+func (s *Server) Saves() []SaveFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SaveFile(nil), s.fixture.Saves...)
+}
+
+// States returns the states currently stored, including uploads.
+func (s *Server) States() []SaveFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SaveFile(nil), s.fixture.States...)
+}
+
+// SetContent replaces the bytes served for a ROM file, as if it changed on the server.
+func (s *Server) SetContent(romID int, fileName string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixture.Content[ContentKey{RomID: romID, FileName: fileName}] = data
+}
+
+// ExpireTokens invalidates every issued access token, forcing clients to refresh.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessTokens = make(map[string][]string)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+	})
+	hooks := append([]Hook(nil), s.hooks...)
+	s.mu.Unlock()
+
+	for _, hook := range hooks {
+		if hook(w, r) {
+			return
+		}
+	}
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "Invalid or expired credentials")
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.fixture.Username == "" || r.URL.Path == "/api/heartbeat" || r.URL.Path == "/api/token" {
+		return true
+	}
+
+	if username, password, ok := r.BasicAuth(); ok {
+		return username == s.fixture.Username && password == s.fixture.Password
+	}
+
+	accessToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok = s.accessTokens[accessToken]
+	return ok
+}
+
+// scoped answers 403 to requests that lack scope, like RomM's protected routes. Basic auth
+// has every scope of the user's role.
+func (s *Server) scoped(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.hasScope(r, scope) {
+			writeError(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (s *Server) hasScope(r *http.Request, scope string) bool {
+	if s.fixture.Username == "" {
+		return true
+	}
+	if _, _, ok := r.BasicAuth(); ok {
+		return slices.Contains(roleScopes(s.fixture.Role), scope)
+	}
+
+	accessToken, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Contains(s.accessTokens[accessToken], scope)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers the way RomM does, with the message under "detail".
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, map[string]string{"detail": fmt.Sprintf(format, args...)})
+}
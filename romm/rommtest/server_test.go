@@ -0,0 +1,123 @@
+package rommtest
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"grout/romm"
+)
+
+func TestAuthenticationRequired(t *testing.T) {
+	server := NewServer(Fixture{Username: "player", Password: "hunter2"})
+	defer server.Close()
+
+	if _, err := romm.NewClient(server.URL).GetPlatforms(); !errors.Is(err, romm.ErrUnauthorized) {
+		t.Errorf("unauthenticated GetPlatforms() error = %v, want ErrUnauthorized", err)
+	}
+	if _, err := server.Client().GetPlatforms(); err != nil {
+		t.Errorf("GetPlatforms() with fixture credentials error = %v", err)
+	}
+	if err := server.Client().ValidateConnection(); err != nil {
+		t.Errorf("heartbeat should not need credentials, got %v", err)
+	}
+}
+
+func TestScopesEnforced(t *testing.T) {
+	server := NewServer(Fixture{Username: "player", Password: "hunter2", Role: romm.RoleViewer})
+	defer server.Close()
+
+	// A login asking for more than the role allows is refused
+	form := url.Values{"grant_type": {"password"}, "username": {"player"}, "password": {"hunter2"}, "scope": {"roms.read roms.write"}}
+	resp, err := http.PostForm(server.URL+"/api/token", form)
+	if err != nil {
+		t.Fatalf("POST /api/token error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("login beyond the viewer role status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+
+	// A token only reaches the endpoints its scopes cover
+	token, err := romm.NewClient(server.URL).Login("player", "hunter2")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	client := romm.NewClient(server.URL, romm.WithToken(token), romm.WithRetryPolicy(romm.NoRetry))
+	if _, err := client.GetPlatforms(); err != nil {
+		t.Errorf("GetPlatforms() with a viewer token error = %v", err)
+	}
+	if err := client.UploadRomReader(1, "game.zip", strings.NewReader("rom")); !errors.Is(err, romm.ErrForbidden) {
+		t.Errorf("UploadRomReader() with a viewer token error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestLatencyHook(t *testing.T) {
+	const delay = 50 * time.Millisecond
+	server := NewServer(Fixture{}, WithHook(Latency(Match(http.MethodGet, "/api/collections"), delay)))
+	defer server.Close()
+
+	start := time.Now()
+	if _, err := server.Client().GetCollections(); err != nil {
+		t.Fatalf("GetCollections() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("GetCollections() took %v, want at least %v", elapsed, delay)
+	}
+
+	// Requests the matcher doesn't select are not delayed
+	start = time.Now()
+	server.Client().GetPlatforms()
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("GetPlatforms() took %v, latency hook should not apply", elapsed)
+	}
+}
+
+func TestLatencyHookTimesOutClient(t *testing.T) {
+	server := NewServer(Fixture{}, WithHook(Latency(Any(), time.Second)))
+	defer server.Close()
+
+	_, err := server.Client(romm.WithTimeout(20 * time.Millisecond)).GetPlatforms()
+	if !errors.Is(romm.ClassifyError(err), romm.ErrTimeout) {
+		t.Errorf("GetPlatforms() error = %v, want a timeout", err)
+	}
+}
+
+func TestDropConnectionHook(t *testing.T) {
+	server := NewServer(Fixture{}, WithHook(DropConnection(Match("", "/api/firmware"), 1)))
+	defer server.Close()
+
+	client := server.Client()
+	if _, err := client.GetFirmware(1); err == nil {
+		t.Fatal("GetFirmware() succeeded on a dropped connection")
+	}
+	if _, err := client.GetFirmware(1); err != nil {
+		t.Errorf("GetFirmware() after the hook was exhausted error = %v", err)
+	}
+}
+
+func TestVirtualCollectionsRequireType(t *testing.T) {
+	server := NewServer(Fixture{
+		VirtualCollections: []romm.VirtualCollection{{ID: "genre-rpg", Name: "RPG", ROMIDs: []int{1}}},
+		Roms:               []romm.Rom{{ID: 1, Name: "Quest"}, {ID: 2, Name: "Racer"}},
+	})
+	defer server.Close()
+
+	client := server.Client()
+
+	collections, err := client.GetVirtualCollections()
+	if err != nil || len(collections) != 1 {
+		t.Fatalf("GetVirtualCollections() = %v, %v", collections, err)
+	}
+
+	page, err := client.GetRoms(romm.GetRomsQuery{VirtualCollectionID: "genre-rpg"})
+	if err != nil {
+		t.Fatalf("GetRoms() error = %v", err)
+	}
+	if page.Total != 1 || page.Items[0].ID != 1 {
+		t.Errorf("GetRoms(virtual collection) = %+v, want only rom 1", page.Items)
+	}
+}
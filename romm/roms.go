@@ -1,11 +1,20 @@
 package romm
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
 	"grout/internal/fileutil"
+	"io"
+	"net/http"
 	"net/url"
+	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sonh/qs"
@@ -15,6 +24,13 @@ type PlatformDirResolver interface {
 	GetPlatformRomDirectory(Platform) string
 }
 
+type ManualDirResolver interface {
+	GetManualDirectory(Platform) string
+}
+
+// resourcesAssetPrefix is where RomM serves files from its resources folder.
+const resourcesAssetPrefix = "/assets/romm/resources/"
+
 type PaginatedRoms struct {
 	Items  []Rom `json:"items"`
 	Total  int   `json:"total"`
@@ -39,6 +55,9 @@ type Rom struct {
 	Name                string `json:"name,omitempty"`
 	DisplayName         string
 	Slug                string       `json:"slug,omitempty"`
+	IgdbID              int          `json:"igdb_id,omitempty"`
+	MobyID              int          `json:"moby_id,omitempty"`
+	SsID                int          `json:"ss_id,omitempty"`
 	Summary             string       `json:"summary,omitempty"`
 	AlternativeNames    []string     `json:"alternative_names,omitempty"`
 	Metadatum           RomMetadata  `json:"metadatum,omitempty"`
@@ -69,7 +88,20 @@ type Rom struct {
 	CreatedAt           time.Time    `json:"created_at,omitempty"`
 	UpdatedAt           time.Time    `json:"updated_at,omitempty"`
 	MissingFromFs       bool         `json:"missing_from_fs,omitempty"`
-	Siblings            []any        `json:"siblings,omitempty"`
+	Siblings            []SiblingRom `json:"siblings,omitempty"`
+
+	// ParsedTags is the parsed tags of the file name, filled in when the game is cached
+	ParsedTags *RomTags `json:"parsed_tags,omitempty"`
+}
+
+// SiblingRom is another dump of the same game on the same platform, usually a different
+// region or revision.
+type SiblingRom struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name,omitempty"`
+	FsNameNoTags   string `json:"fs_name_no_tags,omitempty"`
+	FsNameNoExt    string `json:"fs_name_no_ext,omitempty"`
+	SortComparator string `json:"sort_comparator,omitempty"`
 }
 
 type Screenshot struct {
@@ -144,24 +176,44 @@ func (q DownloadRomsQuery) Valid() bool {
 }
 
 func (c *Client) GetRoms(query GetRomsQuery) (PaginatedRoms, error) {
+	return c.GetRomsContext(context.Background(), query)
+}
+
+func (c *Client) GetRomsContext(ctx context.Context, query GetRomsQuery) (PaginatedRoms, error) {
 	var result PaginatedRoms
-	err := c.doRequest("GET", endpointRoms, query, nil, &result)
+	err := c.doRequest(ctx, "GET", endpointRoms, query, nil, &result)
 	return result, err
 }
+
 func (c *Client) GetRomByHash(query GetRomByHashQuery) (Rom, error) {
+	return c.GetRomByHashContext(context.Background(), query)
+}
+
+func (c *Client) GetRomByHashContext(ctx context.Context, query GetRomByHashQuery) (Rom, error) {
 	var rom Rom
-	err := c.doRequest("GET", endpointRomsByHash, query, nil, &rom)
+	err := c.doRequest(ctx, "GET", endpointRomsByHash, query, nil, &rom)
 	return rom, err
 }
+
 func (c *Client) GetRom(id int) (Rom, error) {
+	return c.GetRomContext(context.Background(), id)
+}
+
+func (c *Client) GetRomContext(ctx context.Context, id int) (Rom, error) {
 	var rom Rom
 	path := fmt.Sprintf(endpointRomByID, id)
-	err := c.doRequest("GET", path, nil, nil, &rom)
+	err := c.doRequest(ctx, "GET", path, nil, nil, &rom)
 	return rom, err
 }
-func (c *Client) DownloadRoms(romIDs []int) ([]byte, error) {
+
+// DownloadRoms streams a zip of the given ROMs into w and returns the number of bytes written.
+func (c *Client) DownloadRoms(romIDs []int, w io.Writer) (int64, error) {
+	return c.DownloadRomsContext(context.Background(), romIDs, w)
+}
+
+func (c *Client) DownloadRomsContext(ctx context.Context, romIDs []int, w io.Writer) (int64, error) {
 	if len(romIDs) == 0 {
-		return c.doRequestRaw("GET", endpointRomsDownload, nil)
+		return c.doRequestStream(ctx, "GET", endpointRomsDownload, w)
 	}
 
 	ids := ""
@@ -175,11 +227,29 @@ func (c *Client) DownloadRoms(romIDs []int) ([]byte, error) {
 	query := DownloadRomsQuery{RomIDs: ids}
 	values, err := qs.NewEncoder().Values(query)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	path := endpointRomsDownload + "?" + values.Encode()
-	return c.doRequestRaw("GET", path, nil)
+	return c.doRequestStream(ctx, "GET", path, w)
+}
+
+// UploadRomReader uploads the contents of r as filename into the platform's library folder.
+// The body is streamed like save uploads. RomM only lists the file after its next library scan.
+func (c *Client) UploadRomReader(platformID int, filename string, r io.Reader) error {
+	return c.UploadRomReaderContext(context.Background(), platformID, filename, r)
+}
+
+func (c *Client) UploadRomReaderContext(ctx context.Context, platformID int, filename string, r io.Reader) error {
+	// RomM reads the file from the form field named after the file itself
+	body, contentType := streamMultipartFile(filename, filename, r)
+	defer body.Close()
+
+	header := http.Header{}
+	header.Set("X-Upload-Platform", strconv.Itoa(platformID))
+	header.Set("X-Upload-Filename", filename)
+
+	return c.doMultipartRequest(ctx, "POST", endpointRoms, nil, body, contentType, header, nil)
 }
 
 func (r Rom) GetGamePage(host Host) string {
@@ -187,18 +257,20 @@ func (r Rom) GetGamePage(host Host) string {
 	return u
 }
 
-func (r Rom) GetLocalPath(resolver PlatformDirResolver) string {
-	if r.PlatformFSSlug == "" {
-		return ""
-	}
-
-	platform := Platform{
+func (r Rom) platform() Platform {
+	return Platform{
 		ID:     r.PlatformID,
 		FSSlug: r.PlatformFSSlug,
 		Name:   r.PlatformDisplayName,
 	}
+}
+
+func (r Rom) GetLocalPath(resolver PlatformDirResolver) string {
+	if r.PlatformFSSlug == "" {
+		return ""
+	}
 
-	romDirectory := resolver.GetPlatformRomDirectory(platform)
+	romDirectory := resolver.GetPlatformRomDirectory(r.platform())
 
 	if r.HasMultipleFiles {
 		return filepath.Join(romDirectory, r.FsNameNoExt+".m3u")
@@ -216,3 +288,227 @@ func (r Rom) IsDownloaded(resolver PlatformDirResolver) bool {
 	}
 	return fileutil.FileExists(path)
 }
+
+// ManualPath returns the server path of the manual RomM stored for the rom, or "" when it has none.
+func (r Rom) ManualPath() string {
+	if !r.HasManual || r.PathManual == "" {
+		return ""
+	}
+	if strings.HasPrefix(r.PathManual, "/") {
+		return r.PathManual
+	}
+	return resourcesAssetPrefix + r.PathManual
+}
+
+// ManualFileName returns the local file name of the rom's manual, named after the rom like artwork is.
+func (r Rom) ManualFileName() string {
+	ext := path.Ext(r.PathManual)
+	if ext == "" {
+		ext = ".pdf"
+	}
+	return r.FsNameNoExt + ext
+}
+
+// GetLocalManualPath returns where the rom's manual is stored on the device, or "" when it has none.
+func (r Rom) GetLocalManualPath(resolver ManualDirResolver) string {
+	if r.PlatformFSSlug == "" || r.ManualPath() == "" {
+		return ""
+	}
+	return filepath.Join(resolver.GetManualDirectory(r.platform()), r.ManualFileName())
+}
+
+// RemoveLocalManual deletes the rom's manual from the device, for when the rom itself is removed.
+// A manual that was never downloaded is not an error.
+func (r Rom) RemoveLocalManual(resolver ManualDirResolver) error {
+	location := r.GetLocalManualPath(resolver)
+	if location == "" {
+		return nil
+	}
+	if err := fileutil.DeleteFile(location); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove manual: %w", err)
+	}
+	return nil
+}
+
+// DownloadManual streams the rom's manual into w and returns the number of bytes written.
+func (c *Client) DownloadManual(rom Rom, w io.Writer) (int64, error) {
+	return c.DownloadManualContext(context.Background(), rom, w)
+}
+
+func (c *Client) DownloadManualContext(ctx context.Context, rom Rom, w io.Writer) (int64, error) {
+	manualPath := rom.ManualPath()
+	if manualPath == "" {
+		return 0, fmt.Errorf("rom %d has no manual", rom.ID)
+	}
+	return c.doRequestStream(ctx, "GET", manualPath, w)
+}
+
+// NameTags returns the parsed tags of the ROM's file name, from the cache when it has them.
+func (r Rom) NameTags() RomTags {
+	if r.ParsedTags != nil {
+		return *r.ParsedTags
+	}
+
+	name := r.FsName
+	if name == "" {
+		name = r.Name
+	}
+	return ParseTags(name)
+}
+
+// SiblingIDs returns the IDs of the game's other variants.
+func (r Rom) SiblingIDs() []int {
+	ids := make([]int, len(r.Siblings))
+	for i, sibling := range r.Siblings {
+		ids[i] = sibling.ID
+	}
+	return ids
+}
+
+// GroupVariants groups the games that are versions of the same title: RomM siblings, ROMs
+// matched to the same metadata entry, and ROMs sharing a name once tags are removed. Groups
+// keep the order in which they first appear in games.
+func GroupVariants(games []Rom) [][]Rom {
+	parent := make([]int, len(games))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	owner := make(map[string]int)
+	for i, game := range games {
+		for _, key := range game.variantKeys() {
+			j, ok := owner[key]
+			if !ok {
+				owner[key] = i
+				continue
+			}
+			if a, b := find(i), find(j); a != b {
+				parent[max(a, b)] = min(a, b)
+			}
+		}
+	}
+
+	groupOf := make(map[int]int)
+	var groups [][]Rom
+	for i, game := range games {
+		root := find(i)
+		group, ok := groupOf[root]
+		if !ok {
+			group = len(groups)
+			groupOf[root] = group
+			groups = append(groups, nil)
+		}
+		groups[group] = append(groups[group], game)
+	}
+
+	return groups
+}
+
+// variantKeys lists what the ROM has in common with its other versions, two ROMs sharing
+// any key are variants of each other.
+func (r Rom) variantKeys() []string {
+	keys := []string{fmt.Sprintf("rom:%d", r.ID)}
+	for _, id := range r.SiblingIDs() {
+		keys = append(keys, fmt.Sprintf("rom:%d", id))
+	}
+
+	if r.IgdbID != 0 {
+		keys = append(keys, fmt.Sprintf("igdb:%d:%d", r.PlatformID, r.IgdbID))
+	}
+	if r.MobyID != 0 {
+		keys = append(keys, fmt.Sprintf("moby:%d:%d", r.PlatformID, r.MobyID))
+	}
+	if r.SsID != 0 {
+		keys = append(keys, fmt.Sprintf("ss:%d:%d", r.PlatformID, r.SsID))
+	}
+	if name := strings.ToLower(strings.TrimSpace(r.FsNameNoTags)); name != "" {
+		keys = append(keys, fmt.Sprintf("name:%d:%s", r.PlatformID, name))
+	}
+
+	return keys
+}
+
+// VariantRank scores the ROM against a priority list of regions and languages, lower is a
+// better match. ROMs matching nothing in the list rank last. When RomM knows neither, the
+// file name tags are used.
+func (r Rom) VariantRank(priority []string) int {
+	best := len(priority)
+	values := slices.Concat(r.Regions, r.Languages)
+	if len(values) == 0 {
+		tags := r.NameTags()
+		values = slices.Concat(tags.Regions, tags.Languages)
+	}
+
+	for _, value := range values {
+		for i, preferred := range priority[:best] {
+			if strings.EqualFold(value, preferred) {
+				best = i
+				break
+			}
+		}
+	}
+	return best
+}
+
+// SortVariants orders variants best match first by VariantRank, then latest revision.
+func SortVariants(variants []Rom, priority []string) {
+	slices.SortStableFunc(variants, func(a, b Rom) int {
+		if c := cmp.Compare(a.VariantRank(priority), b.VariantRank(priority)); c != 0 {
+			return c
+		}
+		return compareRevisions(b.Revision, a.Revision)
+	})
+}
+
+// compareRevisions orders revisions like "Rev 2" before "Rev 10" by comparing runs of digits
+// as numbers and everything else case-insensitively.
+func compareRevisions(a, b string) int {
+	for a != "" && b != "" {
+		aPart, aRest := revisionPart(a)
+		bPart, bRest := revisionPart(b)
+
+		aNum, aErr := strconv.Atoi(aPart)
+		bNum, bErr := strconv.Atoi(bPart)
+		var c int
+		if aErr == nil && bErr == nil {
+			c = cmp.Compare(aNum, bNum)
+		} else {
+			c = strings.Compare(strings.ToLower(aPart), strings.ToLower(bPart))
+		}
+		if c != 0 {
+			return c
+		}
+
+		a, b = aRest, bRest
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// revisionPart splits off the leading run of digits or non-digits of s.
+func revisionPart(s string) (string, string) {
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// Checksums returns the hashes RomM computed for this file.
+func (f RomFile) Checksums() fileutil.Checksums {
+	return fileutil.Checksums{
+		CRC:  f.CrcHash,
+		MD5:  f.Md5Hash,
+		SHA1: f.Sha1Hash,
+	}
+}
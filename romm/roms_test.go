@@ -0,0 +1,118 @@
+package romm_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+type manualDir string
+
+func (d manualDir) GetManualDirectory(romm.Platform) string {
+	return string(d)
+}
+
+func TestRemoveLocalManual(t *testing.T) {
+	dir := manualDir(t.TempDir())
+	rom := romm.Rom{
+		ID:             1,
+		PlatformFSSlug: "gb",
+		FsNameNoExt:    "Tetris (World)",
+		HasManual:      true,
+		PathManual:     "roms/1/manual.pdf",
+	}
+
+	location := rom.GetLocalManualPath(dir)
+	if err := os.WriteFile(location, []byte("%PDF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rom.RemoveLocalManual(dir); err != nil {
+		t.Fatalf("RemoveLocalManual() error = %v", err)
+	}
+	if _, err := os.Stat(location); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("manual still exists after RemoveLocalManual(), stat error = %v", err)
+	}
+
+	// Nothing left to remove is fine
+	if err := rom.RemoveLocalManual(dir); err != nil {
+		t.Errorf("RemoveLocalManual() without a manual error = %v", err)
+	}
+}
+
+func TestUploadRom(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{
+		Platforms: []romm.Platform{{ID: 1, Name: "Game Boy", Slug: "gb", FSSlug: "gb"}},
+		Roms:      platformRoms(1, 1, 1),
+	})
+	defer server.Close()
+
+	client := server.Client()
+	content := bytes.Repeat([]byte("rom"), 64*1024)
+
+	if err := client.UploadRomReader(1, "Homebrew Game (World).gb", bytes.NewReader(content)); err != nil {
+		t.Fatalf("UploadRomReader() error = %v", err)
+	}
+
+	roms, err := client.GetRoms(romm.GetRomsQuery{PlatformID: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetRoms() error = %v", err)
+	}
+	if len(roms.Items) != 2 || roms.Items[1].FsName != "Homebrew Game (World).gb" {
+		t.Fatalf("GetRoms(platform 1) = %+v, want the uploaded ROM listed", roms.Items)
+	}
+
+	rc, err := client.OpenRomContent(roms.Items[1].ID, "Homebrew Game (World).gb", 0, "")
+	if err != nil {
+		t.Fatalf("OpenRomContent() error = %v", err)
+	}
+	defer rc.Body.Close()
+	if got, _ := io.ReadAll(rc.Body); !bytes.Equal(got, content) {
+		t.Errorf("OpenRomContent() returned %d bytes that don't match the upload", len(got))
+	}
+
+	if err := client.UploadRomReader(1, "Homebrew Game (World).gb", bytes.NewReader(content)); err == nil {
+		t.Error("UploadRomReader() of an existing file succeeded, want an error")
+	}
+	if err := client.UploadRomReader(9, "other.gb", strings.NewReader("rom")); !errors.Is(err, romm.ErrNotFound) {
+		t.Errorf("UploadRomReader() to a missing platform error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUploadRomScopeFollowsRole(t *testing.T) {
+	tests := []struct {
+		role string
+		want error
+	}{
+		{romm.RoleViewer, romm.ErrForbidden},
+		{romm.RoleEditor, nil},
+		{romm.RoleAdmin, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			server := rommtest.NewServer(rommtest.Fixture{
+				Username:  "player",
+				Password:  "hunter2",
+				Role:      tt.role,
+				Platforms: []romm.Platform{{ID: 1, Name: "Game Boy", Slug: "gb", FSSlug: "gb"}},
+			})
+			defer server.Close()
+
+			token, err := romm.NewClient(server.URL).Login("player", "hunter2")
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			client := romm.NewClient(server.URL, romm.WithToken(token), romm.WithRetryPolicy(romm.NoRetry))
+			if err := client.UploadRomReader(1, "game.gb", strings.NewReader("rom")); !errors.Is(err, tt.want) {
+				t.Errorf("UploadRomReader() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
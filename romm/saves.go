@@ -1,7 +1,7 @@
 package romm
 
 import (
-	"bytes"
+	"context"
 	"io"
 	"mime/multipart"
 	"os"
@@ -54,43 +54,80 @@ func (sq SaveQuery) Valid() bool {
 }
 
 func (c *Client) GetSaves(query SaveQuery) ([]Save, error) {
+	return c.GetSavesContext(context.Background(), query)
+}
+
+func (c *Client) GetSavesContext(ctx context.Context, query SaveQuery) ([]Save, error) {
 	var saves []Save
-	err := c.doRequest("GET", endpointSaves, query, nil, &saves)
+	err := c.doRequest(ctx, "GET", endpointSaves, query, nil, &saves)
 	return saves, err
 }
 
-func (c *Client) DownloadSave(downloadPath string) ([]byte, error) {
-	return c.doRequestRaw("GET", downloadPath, nil)
+// DownloadSave streams the save at downloadPath into w and returns the number of bytes written.
+func (c *Client) DownloadSave(downloadPath string, w io.Writer) (int64, error) {
+	return c.DownloadSaveContext(context.Background(), downloadPath, w)
+}
+
+func (c *Client) DownloadSaveContext(ctx context.Context, downloadPath string, w io.Writer) (int64, error) {
+	return c.doRequestStream(ctx, "GET", downloadPath, w)
 }
 
 func (c *Client) UploadSave(romID int, savePath string, emulator string) (Save, error) {
+	return c.UploadSaveContext(context.Background(), romID, savePath, emulator)
+}
+
+func (c *Client) UploadSaveContext(ctx context.Context, romID int, savePath string, emulator string) (Save, error) {
 	file, err := os.Open(savePath)
 	if err != nil {
 		return Save{}, err
 	}
 	defer file.Close()
 
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+	return c.UploadSaveReaderContext(ctx, romID, filepath.Base(savePath), file, emulator)
+}
 
-	part, err := writer.CreateFormFile("saveFile", filepath.Base(savePath))
-	if err != nil {
-		return Save{}, err
-	}
+// UploadSaveReader uploads the contents of r as a save named filename. The multipart
+// body is produced through a pipe, so the save is never held in memory as a whole.
+func (c *Client) UploadSaveReader(romID int, filename string, r io.Reader, emulator string) (Save, error) {
+	return c.UploadSaveReaderContext(context.Background(), romID, filename, r, emulator)
+}
 
-	if _, err := io.Copy(part, file); err != nil {
-		return Save{}, err
-	}
+func (c *Client) UploadSaveReaderContext(ctx context.Context, romID int, filename string, r io.Reader, emulator string) (Save, error) {
+	return c.uploadSaveFile(ctx, endpointSaves, "saveFile", romID, filename, r, emulator)
+}
 
-	if err := writer.Close(); err != nil {
-		return Save{}, err
-	}
+// uploadSaveFile posts r as a multipart file to endpoint. Saves and states share the
+// upload contract and only differ in the endpoint and the name of the form field.
+func (c *Client) uploadSaveFile(ctx context.Context, endpoint, fieldName string, romID int, filename string, r io.Reader, emulator string) (Save, error) {
+	body, contentType := streamMultipartFile(fieldName, filename, r)
+	defer body.Close()
 
 	var res Save
-	err = c.doMultipartRequest("POST", endpointSaves, SaveQuery{RomID: romID, Emulator: emulator}, &buf, writer.FormDataContentType(), &res)
+	err := c.doMultipartRequest(ctx, "POST", endpoint, SaveQuery{RomID: romID, Emulator: emulator}, body, contentType, nil, &res)
 	if err != nil {
 		return Save{}, err
 	}
 
 	return res, nil
 }
+
+// streamMultipartFile returns a reader producing a multipart body with a single file part.
+// The body is written by a goroutine as the HTTP client consumes it; closing the returned
+// reader unblocks that goroutine if the request is abandoned early.
+func streamMultipartFile(fieldName, filename string, r io.Reader) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		part, err := writer.CreateFormFile(fieldName, filename)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr, writer.FormDataContentType()
+}
@@ -0,0 +1,45 @@
+package romm_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func TestSaveRoundTrip(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{
+		Roms: append(platformRoms(1, 2, 1), platformRoms(2, 1, 50)...),
+	})
+	defer server.Close()
+
+	client := server.Client()
+	content := bytes.Repeat([]byte{0xAB, 0xCD}, 32*1024)
+
+	uploaded, err := client.UploadSaveReader(2, "Game 0001 [2025-01-01 10-00-00].srm", bytes.NewReader(content), "mgba")
+	if err != nil {
+		t.Fatalf("UploadSaveReader() error = %v", err)
+	}
+	if _, err := client.UploadSaveReader(50, "other.srm", strings.NewReader("other"), "mgba"); err != nil {
+		t.Fatalf("UploadSaveReader() error = %v", err)
+	}
+
+	saves, err := client.GetSaves(romm.SaveQuery{PlatformID: 1})
+	if err != nil {
+		t.Fatalf("GetSaves() error = %v", err)
+	}
+	if len(saves) != 1 || saves[0].ID != uploaded.ID {
+		t.Fatalf("GetSaves(platform 1) = %+v, want only the uploaded save", saves)
+	}
+
+	var buf bytes.Buffer
+	n, err := client.DownloadSave(saves[0].DownloadPath, &buf)
+	if err != nil {
+		t.Fatalf("DownloadSave() error = %v", err)
+	}
+	if n != int64(len(content)) || !bytes.Equal(buf.Bytes(), content) {
+		t.Errorf("DownloadSave() returned %d bytes that don't match the upload", n)
+	}
+}
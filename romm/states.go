@@ -0,0 +1,54 @@
+package romm
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// State is a save state. RomM describes states with the same schema as saves,
+// so they share a type and the sync code can treat both alike.
+type State = Save
+
+func (c *Client) GetStates(query SaveQuery) ([]State, error) {
+	return c.GetStatesContext(context.Background(), query)
+}
+
+func (c *Client) GetStatesContext(ctx context.Context, query SaveQuery) ([]State, error) {
+	var states []State
+	err := c.doRequest(ctx, "GET", endpointStates, query, nil, &states)
+	return states, err
+}
+
+// DownloadState streams the state at downloadPath into w and returns the number of bytes written.
+func (c *Client) DownloadState(downloadPath string, w io.Writer) (int64, error) {
+	return c.DownloadStateContext(context.Background(), downloadPath, w)
+}
+
+func (c *Client) DownloadStateContext(ctx context.Context, downloadPath string, w io.Writer) (int64, error) {
+	return c.doRequestStream(ctx, "GET", downloadPath, w)
+}
+
+func (c *Client) UploadState(romID int, statePath string, emulator string) (State, error) {
+	return c.UploadStateContext(context.Background(), romID, statePath, emulator)
+}
+
+func (c *Client) UploadStateContext(ctx context.Context, romID int, statePath string, emulator string) (State, error) {
+	file, err := os.Open(statePath)
+	if err != nil {
+		return State{}, err
+	}
+	defer file.Close()
+
+	return c.UploadStateReaderContext(ctx, romID, filepath.Base(statePath), file, emulator)
+}
+
+// UploadStateReader uploads the contents of r as a state named filename, streaming it like UploadSaveReader.
+func (c *Client) UploadStateReader(romID int, filename string, r io.Reader, emulator string) (State, error) {
+	return c.UploadStateReaderContext(context.Background(), romID, filename, r, emulator)
+}
+
+func (c *Client) UploadStateReaderContext(ctx context.Context, romID int, filename string, r io.Reader, emulator string) (State, error) {
+	return c.uploadSaveFile(ctx, endpointStates, "stateFile", romID, filename, r, emulator)
+}
@@ -0,0 +1,43 @@
+package romm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{Roms: platformRoms(1, 1, 1)})
+	defer server.Close()
+
+	client := server.Client()
+	content := []byte("state slot 1")
+
+	uploaded, err := client.UploadStateReader(1, "Game 0001 [2025-01-01 10-00-00].state1", bytes.NewReader(content), "mGBA")
+	if err != nil {
+		t.Fatalf("UploadStateReader() error = %v", err)
+	}
+
+	// States are kept apart from saves
+	if saves, err := client.GetSaves(romm.SaveQuery{RomID: 1}); err != nil || len(saves) != 0 {
+		t.Errorf("GetSaves() = %v, %v, want no saves", saves, err)
+	}
+
+	states, err := client.GetStates(romm.SaveQuery{PlatformID: 1})
+	if err != nil {
+		t.Fatalf("GetStates() error = %v", err)
+	}
+	if len(states) != 1 || states[0].ID != uploaded.ID || states[0].FileExtension != "state1" {
+		t.Fatalf("GetStates(platform 1) = %+v, want the uploaded state", states)
+	}
+
+	var buf bytes.Buffer
+	if _, err := client.DownloadState(states[0].DownloadPath, &buf); err != nil {
+		t.Fatalf("DownloadState() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), content) {
+		t.Errorf("DownloadState() = %q, want %q", buf.Bytes(), content)
+	}
+}
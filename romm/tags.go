@@ -0,0 +1,248 @@
+package romm
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RomTags holds what the No-Intro, Redump and GoodTools tags of a ROM's file name say about
+// the dump, for example "Game (USA, Europe) (En,Fr) (Rev 1) (Disc 2) [!]".
+type RomTags struct {
+	Regions   []string `json:"regions,omitempty"`
+	Languages []string `json:"languages,omitempty"`
+	Revision  string   `json:"revision,omitempty"`
+	Version   string   `json:"version,omitempty"`
+	Disc      string   `json:"disc,omitempty"`
+
+	Beta           bool `json:"beta,omitempty"`
+	Proto          bool `json:"proto,omitempty"`
+	Demo           bool `json:"demo,omitempty"`
+	Hack           bool `json:"hack,omitempty"`
+	Unlicensed     bool `json:"unlicensed,omitempty"`
+	Pirate         bool `json:"pirate,omitempty"`
+	Translation    bool `json:"translation,omitempty"`
+	Alternate      bool `json:"alternate,omitempty"`
+	BadDump        bool `json:"bad_dump,omitempty"`
+	Verified       bool `json:"verified,omitempty"`
+	VirtualConsole bool `json:"virtual_console,omitempty"`
+
+	// Other keeps the tags the parser doesn't know, as they were written
+	Other []string `json:"other,omitempty"`
+}
+
+var (
+	romTagRegex      = regexp.MustCompile(`\(([^()]*)\)|\[([^\[\]]*)\]`)
+	revisionTagRegex = regexp.MustCompile(`(?i)^rev(?:ision)?\s*([0-9a-z.]+)$`)
+	versionTagRegex  = regexp.MustCompile(`(?i)^v(?:ersion\s*)?(\d[0-9a-z.]*)$`)
+	discTagRegex     = regexp.MustCompile(`(?i)^(?:disc|disk|cd)\s*([0-9a-z]+)(?:\s+of\s+\d+)?$`)
+	numberedTagRegex = regexp.MustCompile(`^([a-zA-Z]+)\s*\d*$`)
+	dumpTagRegex     = regexp.MustCompile(`^([abfhopT])\d*[a-zA-Z]?(?:[+-].*)?$`)
+)
+
+// tagRegions maps the region names No-Intro and Redump use, in lower case, to how RomM names them.
+var tagRegions = map[string]string{
+	"world": "World", "usa": "USA", "europe": "Europe", "japan": "Japan", "asia": "Asia",
+	"australia": "Australia", "brazil": "Brazil", "canada": "Canada", "china": "China",
+	"france": "France", "germany": "Germany", "hong kong": "Hong Kong", "italy": "Italy",
+	"korea": "Korea", "netherlands": "Netherlands", "spain": "Spain", "sweden": "Sweden",
+	"taiwan": "Taiwan", "uk": "UK", "russia": "Russia", "scandinavia": "Scandinavia",
+	"greece": "Greece", "finland": "Finland", "norway": "Norway", "denmark": "Denmark",
+	"portugal": "Portugal", "poland": "Poland", "mexico": "Mexico", "argentina": "Argentina",
+	"india": "India", "latin america": "Latin America", "unknown": "Unknown",
+}
+
+// goodToolsRegions maps GoodTools single letter country codes, as in "(U)" or "(JUE)". Only
+// Japan, USA and Europe are combined, other letters stand alone.
+var goodToolsRegions = map[rune]string{
+	'W': "World", 'U': "USA", 'E': "Europe", 'J': "Japan", 'A': "Australia", 'B': "Brazil",
+	'C': "China", 'F': "France", 'G': "Germany", 'I': "Italy", 'K': "Korea", 'S': "Spain",
+}
+
+// tagLanguages maps the ISO 639-1 codes used in language tags to how RomM names languages.
+var tagLanguages = map[string]string{
+	"en": "English", "fr": "French", "de": "German", "es": "Spanish", "it": "Italian",
+	"pt": "Portuguese", "nl": "Dutch", "sv": "Swedish", "no": "Norwegian", "da": "Danish",
+	"fi": "Finnish", "ja": "Japanese", "ko": "Korean", "zh": "Chinese", "ru": "Russian",
+	"pl": "Polish", "el": "Greek", "ca": "Catalan", "cs": "Czech", "hu": "Hungarian",
+	"tr": "Turkish", "ar": "Arabic", "he": "Hebrew", "hr": "Croatian",
+}
+
+// TagParserVersion changes whenever ParseTags reads file names differently, so tags cached by
+// an older parser are parsed again.
+const TagParserVersion = 1
+
+// ParseTags reads the tags of a ROM file name. Tags it can't place end up in Other.
+func ParseTags(name string) RomTags {
+	var tags RomTags
+
+	for _, match := range romTagRegex.FindAllStringSubmatch(name, -1) {
+		if match[0][0] == '[' {
+			tags.parseDumpTag(strings.TrimSpace(match[2]))
+			continue
+		}
+		tags.parseTag(strings.TrimSpace(match[1]))
+	}
+
+	return tags
+}
+
+// ReleaseTags returns the tags RomM reports for the ROM, falling back to the tags of its file
+// name when RomM has none.
+func (r Rom) ReleaseTags() RomTags {
+	var tags RomTags
+	parsed := false
+
+	for _, tag := range r.Tags {
+		if value, ok := tag.(string); ok && strings.TrimSpace(value) != "" {
+			tags.parseTag(strings.TrimSpace(value))
+			parsed = true
+		}
+	}
+
+	if !parsed {
+		return r.NameTags()
+	}
+	return tags
+}
+
+// IsPrerelease reports whether the dump is a beta, prototype or demo rather than a released game.
+func (t RomTags) IsPrerelease() bool {
+	return t.Beta || t.Proto || t.Demo
+}
+
+func (t *RomTags) parseTag(tag string) {
+	if tag == "" {
+		return
+	}
+
+	if regions, ok := parseRegionTag(tag); ok {
+		t.Regions = appendUnique(t.Regions, regions...)
+		return
+	}
+	if languages, ok := parseLanguageTag(tag); ok {
+		t.Languages = appendUnique(t.Languages, languages...)
+		return
+	}
+	if m := revisionTagRegex.FindStringSubmatch(tag); m != nil {
+		t.Revision = m[1]
+		return
+	}
+	if m := versionTagRegex.FindStringSubmatch(tag); m != nil {
+		t.Version = m[1]
+		return
+	}
+	if m := discTagRegex.FindStringSubmatch(tag); m != nil {
+		t.Disc = strings.ToUpper(m[1])
+		return
+	}
+
+	if strings.EqualFold(tag, "Virtual Console") {
+		t.VirtualConsole = true
+		return
+	}
+
+	word := tag
+	if m := numberedTagRegex.FindStringSubmatch(tag); m != nil {
+		word = m[1]
+	}
+
+	switch strings.ToLower(word) {
+	case "beta":
+		t.Beta = true
+	case "proto", "prototype":
+		t.Proto = true
+	case "demo", "sample", "kiosk":
+		t.Demo = true
+	case "hack":
+		t.Hack = true
+	case "unl", "unlicensed":
+		t.Unlicensed = true
+	case "pirate":
+		t.Pirate = true
+	case "alt":
+		t.Alternate = true
+	default:
+		t.Other = append(t.Other, tag)
+	}
+}
+
+// parseDumpTag reads the square bracket flags of GoodTools and Redump, like [!], [b1] or [T+Eng].
+func (t *RomTags) parseDumpTag(tag string) {
+	if tag == "!" {
+		t.Verified = true
+		return
+	}
+
+	m := dumpTagRegex.FindStringSubmatch(tag)
+	if m == nil {
+		if tag != "" {
+			t.Other = append(t.Other, tag)
+		}
+		return
+	}
+
+	switch m[1] {
+	case "a":
+		t.Alternate = true
+	case "b":
+		t.BadDump = true
+	case "h":
+		t.Hack = true
+	case "p":
+		t.Pirate = true
+	case "T":
+		t.Translation = true
+	default:
+		t.Other = append(t.Other, tag)
+	}
+}
+
+func parseRegionTag(tag string) ([]string, bool) {
+	var regions []string
+	for _, part := range strings.Split(tag, ",") {
+		region, ok := tagRegions[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return parseGoodToolsRegionTag(tag)
+		}
+		regions = append(regions, region)
+	}
+	return regions, true
+}
+
+func parseGoodToolsRegionTag(tag string) ([]string, bool) {
+	if tag == "" || len(tag) > 3 {
+		return nil, false
+	}
+
+	var regions []string
+	for _, code := range tag {
+		region, ok := goodToolsRegions[code]
+		if !ok || len(tag) > 1 && !strings.ContainsRune("JUE", code) {
+			return nil, false
+		}
+		regions = append(regions, region)
+	}
+	return regions, true
+}
+
+func parseLanguageTag(tag string) ([]string, bool) {
+	var languages []string
+	for _, part := range strings.FieldsFunc(tag, func(r rune) bool { return r == ',' || r == '+' }) {
+		language, ok := tagLanguages[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return nil, false
+		}
+		languages = append(languages, language)
+	}
+	return languages, len(languages) > 0
+}
+
+func appendUnique(values []string, add ...string) []string {
+	for _, value := range add {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
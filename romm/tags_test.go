@@ -0,0 +1,54 @@
+package romm_test
+
+import (
+	"reflect"
+	"testing"
+
+	"grout/romm"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name string
+		want romm.RomTags
+	}{
+		{
+			name: "Final Fantasy VII (USA) (Disc 2) (Rev 1).chd",
+			want: romm.RomTags{Regions: []string{"USA"}, Revision: "1", Disc: "2"},
+		},
+		{
+			name: "Legend of Zelda, The (USA, Europe) (En,Fr,De) (v1.1) [!].zip",
+			want: romm.RomTags{Regions: []string{"USA", "Europe"}, Languages: []string{"English", "French", "German"}, Version: "1.1", Verified: true},
+		},
+		{
+			name: "Sonic (JUE) (Beta 2) (Proto) (Demo) (Hack) (Unl) (Virtual Console)",
+			want: romm.RomTags{Regions: []string{"Japan", "USA", "Europe"}, Beta: true, Proto: true, Demo: true, Hack: true, Unlicensed: true, VirtualConsole: true},
+		},
+		{
+			name: "Mario (U) [b1] [h2C] [T+Eng1.0] [a1] [p1] (SEGA) [t1]",
+			want: romm.RomTags{Regions: []string{"USA"}, BadDump: true, Hack: true, Translation: true, Alternate: true, Pirate: true, Other: []string{"SEGA", "t1"}},
+		},
+		{
+			name: "Tetris",
+			want: romm.RomTags{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := romm.ParseTags(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseTags(t *testing.T) {
+	fromRomM := romm.Rom{FsName: "Game (USA) (Beta).zip", Tags: []any{"Proto", "Unl"}}
+	if tags := fromRomM.ReleaseTags(); tags.Beta || !tags.Proto || !tags.Unlicensed {
+		t.Errorf("ReleaseTags() = %+v, want RomM tags", tags)
+	}
+
+	fromName := romm.Rom{FsName: "Game (USA) (Beta) [h1].zip"}
+	if tags := fromName.ReleaseTags(); !tags.Beta || !tags.Hack {
+		t.Errorf("ReleaseTags() = %+v, want file name tags", tags)
+	}
+}
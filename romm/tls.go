@@ -0,0 +1,197 @@
+package romm
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// baseTransport is http.DefaultTransport as set up at startup, with the bundled root
+// certificates, before InstallDefaultTransport replaces it.
+var baseTransport = sync.OnceValue(func() *http.Transport {
+	return http.DefaultTransport.(*http.Transport)
+})
+
+// hostTransports holds one transport per host TLS configuration so connections are reused.
+var hostTransports sync.Map
+
+// HasTLSSettings reports whether the host trusts an extra CA bundle or pins its certificate.
+func (h Host) HasTLSSettings() bool {
+	return h.CABundle != "" || h.PinnedCertSHA256 != ""
+}
+
+// Transport returns the HTTP transport for this host. Connections to the host trust its CA
+// bundle and check its pinned certificate, connections anywhere else are verified as usual,
+// so the transport can follow redirects and fetch from other servers too.
+func (h Host) Transport() *http.Transport {
+	base := baseTransport()
+	address := h.tlsAddress()
+	if !h.HasTLSSettings() || address == "" {
+		return base
+	}
+
+	key := strings.Join([]string{address, h.CABundle, NormalizeFingerprint(h.PinnedCertSHA256)}, "|")
+	if t, ok := hostTransports.Load(key); ok {
+		return t.(*http.Transport)
+	}
+
+	t, _ := hostTransports.LoadOrStore(key, newHostTransport(base, address, h.tlsConfig(base.TLSClientConfig)))
+	return t.(*http.Transport)
+}
+
+// HTTPClient returns a client for requests to this host outside of the RomM API, such as artwork.
+// Requests to the host carry its custom headers.
+func (h Host) HTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: newHeaderTransport(h.URL(), h.Headers, h.Transport())}
+}
+
+// InstallDefaultTransport makes http.DefaultTransport trust the host the way its Transport does.
+// Code that doesn't take a client, like gabagool's download manager and the updater, goes
+// through the default transport.
+func InstallDefaultTransport(h Host) {
+	http.DefaultTransport = h.Transport()
+}
+
+// tlsAddress is the host:port the transport dials for this host, empty for plain HTTP hosts.
+func (h Host) tlsAddress() string {
+	u, err := url.Parse(h.URL())
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ""
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(strings.ToLower(u.Hostname()), port)
+}
+
+func (h Host) tlsConfig(base *tls.Config) *tls.Config {
+	config := cloneTLSConfig(base)
+
+	if h.CABundle != "" {
+		pool, err := loadCABundle(h.CABundle, config.RootCAs)
+		if err != nil {
+			gabagool.GetLogger().Warn("Unable to load CA bundle", "path", h.CABundle, "error", err)
+		} else {
+			config.RootCAs = pool
+		}
+	}
+
+	if pin := NormalizeFingerprint(h.PinnedCertSHA256); pin != "" {
+		// A pinned certificate is trusted on its own, that is what lets self-signed certificates work
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return &CertificateError{Err: ErrCertificateMismatch}
+			}
+			if fingerprint := CertificateFingerprint(state.PeerCertificates[0]); fingerprint != pin {
+				return &CertificateError{Fingerprint: fingerprint, Err: ErrCertificateMismatch}
+			}
+			return nil
+		}
+	}
+
+	return config
+}
+
+// newHostTransport clones base with a TLS dialer that uses hostConfig for address and the
+// base configuration for every other address.
+func newHostTransport(base *http.Transport, address string, hostConfig *tls.Config) *http.Transport {
+	t := base.Clone()
+
+	dial := t.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+
+	t.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		config := base.TLSClientConfig
+		if strings.EqualFold(addr, address) {
+			config = hostConfig
+		}
+
+		config = cloneTLSConfig(config)
+		if config.ServerName == "" {
+			config.ServerName, _, _ = net.SplitHostPort(addr)
+		}
+
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+
+	return t
+}
+
+func loadCABundle(path string, roots *x509.CertPool) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pool *x509.CertPool
+	switch {
+	case roots != nil:
+		pool = roots.Clone()
+	default:
+		if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+func cloneTLSConfig(config *tls.Config) *tls.Config {
+	if config == nil {
+		return &tls.Config{}
+	}
+	return config.Clone()
+}
+
+// CertificateFingerprint returns the SHA-256 of the certificate as lowercase hex.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeFingerprint accepts a hex fingerprint with or without colons and spaces, in either case.
+func NormalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fingerprint))
+}
+
+// FormatFingerprint writes the fingerprint as colon separated uppercase byte pairs, the way
+// browsers show it.
+func FormatFingerprint(fingerprint string) string {
+	fingerprint = strings.ToUpper(NormalizeFingerprint(fingerprint))
+
+	pairs := make([]string, 0, len(fingerprint)/2)
+	for i := 0; i+1 < len(fingerprint); i += 2 {
+		pairs = append(pairs, fingerprint[i:i+2])
+	}
+	return strings.Join(pairs, ":")
+}
@@ -0,0 +1,62 @@
+package romm_test
+
+import (
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"grout/romm"
+)
+
+func TestHostCertificateTrust(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	// Rejected handshakes are the point of the test, keep them out of the output
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	fingerprint := romm.CertificateFingerprint(server.Certificate())
+	host := romm.Host{RootURI: server.URL}
+
+	err := romm.NewClientFromHost(host).ValidateConnection()
+	var certErr *romm.CertificateError
+	if !errors.As(err, &certErr) || !errors.Is(err, romm.ErrUntrustedCertificate) {
+		t.Fatalf("ValidateConnection() without trust = %v, want ErrUntrustedCertificate", err)
+	}
+	if certErr.Fingerprint != fingerprint {
+		t.Errorf("Fingerprint = %q, want %q", certErr.Fingerprint, fingerprint)
+	}
+
+	pinned := host
+	pinned.PinnedCertSHA256 = romm.FormatFingerprint(fingerprint)
+	if err := romm.NewClientFromHost(pinned).ValidateConnection(); err != nil {
+		t.Errorf("ValidateConnection() with matching pin = %v", err)
+	}
+
+	wrongPin := host
+	wrongPin.PinnedCertSHA256 = strings.Repeat("ab", 32)
+	if err := romm.NewClientFromHost(wrongPin).ValidateConnection(); !errors.Is(err, romm.ErrCertificateMismatch) {
+		t.Errorf("ValidateConnection() with wrong pin = %v, want ErrCertificateMismatch", err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	trusted := host
+	trusted.CABundle = bundle
+	if err := romm.NewClientFromHost(trusted).ValidateConnection(); err != nil {
+		t.Errorf("ValidateConnection() with CA bundle = %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package romm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTokenScopes are the OAuth2 scopes Grout requests when logging in.
+// They match what a RomM "viewer" role is allowed to hold, so login works for every user.
+var DefaultTokenScopes = []string{
+	"me.read",
+	"me.write",
+	"roms.read",
+	"platforms.read",
+	"assets.read",
+	"assets.write",
+	"firmware.read",
+	"collections.read",
+	"collections.write",
+}
+
+// UploadTokenScopes are asked for on top of DefaultTokenScopes when the user's role allows them,
+// see User.CanUpload. RomM refuses a login asking for a scope the user's role doesn't have.
+var UploadTokenScopes = []string{
+	"roms.write",
+}
+
+// tokenExpiryLeeway refreshes tokens slightly before they expire so a request
+// started just before expiry doesn't race the server clock.
+const tokenExpiryLeeway = 30 * time.Second
+
+// Token is an OAuth2 access/refresh token pair issued by RomM.
+// Hosts hold a pointer to their Token so that a refresh performed by any
+// client is visible to every copy of the Host, including the one in the config.
+type Token struct {
+	mu           sync.Mutex
+	refreshMu    sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+}
+
+type tokenJSON struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	Expires      int    `json:"expires"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+var (
+	tokenRefreshHandlerMu sync.RWMutex
+	tokenRefreshHandler   func(hostKey string, token *Token)
+)
+
+// OnTokenRefresh registers a callback invoked after a token has been refreshed,
+// so the caller can persist the new pair. hostKey is the Key of the Host the client
+// was made from, empty for clients made without one. The callback runs on the
+// goroutine of the request that needed the refresh.
+func OnTokenRefresh(fn func(hostKey string, token *Token)) {
+	tokenRefreshHandlerMu.Lock()
+	defer tokenRefreshHandlerMu.Unlock()
+	tokenRefreshHandler = fn
+}
+
+func notifyTokenRefresh(hostKey string, t *Token) {
+	tokenRefreshHandlerMu.RLock()
+	fn := tokenRefreshHandler
+	tokenRefreshHandlerMu.RUnlock()
+
+	if fn != nil {
+		fn(hostKey, t)
+	}
+}
+
+func (t *Token) MarshalJSON() ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return json.Marshal(tokenJSON{
+		AccessToken:  t.accessToken,
+		RefreshToken: t.refreshToken,
+		ExpiresAt:    t.expiresAt,
+	})
+}
+
+func (t *Token) UnmarshalJSON(data []byte) error {
+	var tj tokenJSON
+	if err := json.Unmarshal(data, &tj); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.accessToken = tj.AccessToken
+	t.refreshToken = tj.RefreshToken
+	t.expiresAt = tj.ExpiresAt
+	return nil
+}
+
+// HasRefreshToken reports whether the token can be refreshed without the user's password.
+func (t *Token) HasRefreshToken() bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.refreshToken != ""
+}
+
+func (t *Token) update(res tokenResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.accessToken = res.AccessToken
+	if res.RefreshToken != "" {
+		t.refreshToken = res.RefreshToken
+	}
+
+	seconds := res.ExpiresIn
+	if seconds == 0 {
+		seconds = res.Expires
+	}
+	t.expiresAt = time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// accessTokenFor returns a usable access token, refreshing it first if it has expired.
+// When force is set the token is refreshed even if it still looks valid, which is
+// what we want after the server rejected it with a 401.
+func (c *Client) accessTokenFor(ctx context.Context, force bool) (string, error) {
+	t := c.token
+	t.mu.Lock()
+
+	stale := t.accessToken
+	if !force && t.accessToken != "" && time.Now().Add(tokenExpiryLeeway).Before(t.expiresAt) {
+		t.mu.Unlock()
+		return stale, nil
+	}
+	refreshToken := t.refreshToken
+	t.mu.Unlock()
+
+	if refreshToken == "" {
+		return "", &AuthError{StatusCode: http.StatusUnauthorized, Message: "Session expired", Err: ErrUnauthorized}
+	}
+
+	// Serialize refreshes so concurrent requests don't each burn a refresh token
+	t.refreshMu.Lock()
+	defer t.refreshMu.Unlock()
+
+	t.mu.Lock()
+	if t.accessToken != stale {
+		current := t.accessToken
+		t.mu.Unlock()
+		return current, nil
+	}
+	t.mu.Unlock()
+
+	res, _, err := c.requestToken(ctx, c.baseURL, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	t.update(res)
+	notifyTokenRefresh(c.hostKey, t)
+
+	return res.AccessToken, nil
+}
+
+// requestToken posts an OAuth2 grant to baseURL and returns the decoded token along
+// with the HTTP status, which Login needs to detect protocol mismatches.
+func (c *Client) requestToken(ctx context.Context, baseURL string, form url.Values) (tokenResponse, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+endpointToken, strings.NewReader(form.Encode()))
+	if err != nil {
+		return tokenResponse{}, 0, ClassifyError(fmt.Errorf("failed to create token request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return tokenResponse{}, 0, ClassifyError(fmt.Errorf("failed to request token: %w", err))
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+	case resp.StatusCode == 400 || resp.StatusCode == 401:
+		// A rejected refresh token means the session is over, not that the password was wrong
+		message := "Invalid username or password"
+		if form.Get("grant_type") == "refresh_token" {
+			message = "Session expired"
+		}
+		return tokenResponse{}, resp.StatusCode, &AuthError{
+			StatusCode: resp.StatusCode,
+			Message:    message,
+			Err:        ErrUnauthorized,
+		}
+	case resp.StatusCode == 403:
+		return tokenResponse{}, resp.StatusCode, &AuthError{
+			StatusCode: 403,
+			Message:    "Access forbidden",
+			Err:        ErrForbidden,
+		}
+	case resp.StatusCode >= 500:
+		return tokenResponse{}, resp.StatusCode, &AuthError{
+			StatusCode: resp.StatusCode,
+			Message:    "Server error",
+			Err:        ErrServerError,
+		}
+	default:
+		return tokenResponse{}, resp.StatusCode, fmt.Errorf("login failed with status: %d", resp.StatusCode)
+	}
+
+	var res tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return tokenResponse{}, resp.StatusCode, fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if res.AccessToken == "" {
+		return tokenResponse{}, resp.StatusCode, fmt.Errorf("token response did not include an access token")
+	}
+
+	return res, resp.StatusCode, nil
+}
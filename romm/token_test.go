@@ -0,0 +1,126 @@
+package romm_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"grout/romm"
+	"grout/romm/rommtest"
+)
+
+func TestTokenRefreshAfterExpiry(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{
+		Username:  "player",
+		Password:  "hunter2",
+		Role:      romm.RoleViewer,
+		Platforms: []romm.Platform{{ID: 1, Name: "Game Boy"}},
+	})
+	defer server.Close()
+
+	token, err := romm.NewClient(server.URL).Login("player", "hunter2")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	// Without a password the client can only succeed by refreshing the token
+	client := romm.NewClient(server.URL, romm.WithToken(token), romm.WithRetryPolicy(romm.NoRetry))
+	if _, err := client.GetPlatforms(); err != nil {
+		t.Fatalf("GetPlatforms() error = %v", err)
+	}
+
+	server.ExpireTokens()
+
+	if _, err := client.GetPlatforms(); err != nil {
+		t.Fatalf("GetPlatforms() after expiry error = %v", err)
+	}
+	if requests := countRequests(server, http.MethodPost, "/api/token"); requests != 2 {
+		t.Errorf("made %d token requests, want login plus one refresh", requests)
+	}
+}
+
+func TestLoginRejectsBadCredentials(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{Username: "player", Password: "hunter2"})
+	defer server.Close()
+
+	_, err := romm.NewClient(server.URL).Login("player", "wrong")
+	if !errors.Is(err, romm.ErrUnauthorized) {
+		t.Errorf("Login() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRejectedRefreshIsSessionExpired(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{
+		Username:  "player",
+		Password:  "hunter2",
+		Platforms: []romm.Platform{{ID: 1, Name: "Game Boy"}},
+	})
+	defer server.Close()
+
+	token, err := romm.NewClient(server.URL).Login("player", "hunter2")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	// A second copy of the token keeps the refresh token the first copy is about to burn
+	data, err := token.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	stale := &romm.Token{}
+	if err := stale.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+
+	server.ExpireTokens()
+
+	if _, err := romm.NewClient(server.URL, romm.WithToken(token), romm.WithRetryPolicy(romm.NoRetry)).GetPlatforms(); err != nil {
+		t.Fatalf("GetPlatforms() error = %v", err)
+	}
+
+	_, err = romm.NewClient(server.URL, romm.WithToken(stale), romm.WithRetryPolicy(romm.NoRetry)).GetPlatforms()
+	var authErr *romm.AuthError
+	if !errors.As(err, &authErr) || !errors.Is(err, romm.ErrUnauthorized) {
+		t.Fatalf("GetPlatforms() with a used refresh token error = %v, want ErrUnauthorized", err)
+	}
+	if authErr.Message != "Session expired" {
+		t.Errorf("AuthError.Message = %q, want %q", authErr.Message, "Session expired")
+	}
+}
+
+func TestTokenRefreshNamesHost(t *testing.T) {
+	server := rommtest.NewServer(rommtest.Fixture{
+		Username:  "player",
+		Password:  "hunter2",
+		Platforms: []romm.Platform{{ID: 1, Name: "Game Boy"}},
+	})
+	defer server.Close()
+
+	token, err := romm.NewClient(server.URL).Login("player", "hunter2")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	var refreshed []string
+	romm.OnTokenRefresh(func(hostKey string, got *romm.Token) {
+		if got != token {
+			t.Error("refresh callback got another token than the host's")
+		}
+		refreshed = append(refreshed, hostKey)
+	})
+	t.Cleanup(func() { romm.OnTokenRefresh(nil) })
+
+	host := server.Host()
+	host.Password = ""
+	host.Token = token
+	client := romm.NewClientFromHost(host)
+
+	server.ExpireTokens()
+	if _, err := client.GetPlatforms(); err != nil {
+		t.Fatalf("GetPlatforms() after expiry error = %v", err)
+	}
+
+	if len(refreshed) != 1 || refreshed[0] != host.Key() {
+		t.Errorf("refresh callback got hosts %v, want [%s]", refreshed, host.Key())
+	}
+}
@@ -0,0 +1,33 @@
+package romm
+
+import "context"
+
+// Roles RomM gives its users. Each role is allowed the scopes of the ones before it and more.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// CanUpload reports whether the user's role is allowed UploadTokenScopes.
+func (u User) CanUpload() bool {
+	return u.Role == RoleEditor || u.Role == RoleAdmin
+}
+
+// GetCurrentUser returns the user the client is logged in as.
+func (c *Client) GetCurrentUser() (User, error) {
+	return c.GetCurrentUserContext(context.Background())
+}
+
+func (c *Client) GetCurrentUserContext(ctx context.Context) (User, error) {
+	var user User
+	err := c.doRequest(ctx, "GET", endpointCurrentUser, nil, nil, &user)
+	return user, err
+}
@@ -0,0 +1,100 @@
+package romm_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"grout/romm"
+)
+
+func TestSiblingIDs(t *testing.T) {
+	var rom romm.Rom
+	data := `{"id": 1, "siblings": [{"id": 2, "name": "Alpha (Europe)"}, {"id": 9, "fs_name_no_tags": "Alpha"}]}`
+	if err := json.Unmarshal([]byte(data), &rom); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got, want := rom.SiblingIDs(), []int{2, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SiblingIDs() = %v, want %v", got, want)
+	}
+
+	if got := (romm.Rom{ID: 3}).SiblingIDs(); len(got) != 0 {
+		t.Errorf("SiblingIDs() without siblings = %v, want none", got)
+	}
+}
+
+func TestGroupVariants(t *testing.T) {
+	siblings := func(ids ...int) []romm.SiblingRom {
+		s := make([]romm.SiblingRom, len(ids))
+		for i, id := range ids {
+			s[i] = romm.SiblingRom{ID: id}
+		}
+		return s
+	}
+
+	games := []romm.Rom{
+		{ID: 1, PlatformID: 1, FsNameNoTags: "Alpha", Siblings: siblings(2, 9)},
+		{ID: 3, PlatformID: 1, FsNameNoTags: "Beta"},
+		{ID: 2, PlatformID: 1, FsNameNoTags: "Alpha", Siblings: siblings(1, 9)},
+		{ID: 4, PlatformID: 1, FsNameNoTags: "Gamma", IgdbID: 77},
+		{ID: 5, PlatformID: 1, FsNameNoTags: "Gamma Returns", IgdbID: 77},
+		{ID: 6, PlatformID: 1, FsNameNoTags: "beta"},
+		{ID: 7, PlatformID: 2, FsNameNoTags: "Beta"},
+	}
+
+	var got [][]int
+	for _, group := range romm.GroupVariants(games) {
+		var ids []int
+		for _, game := range group {
+			ids = append(ids, game.ID)
+		}
+		got = append(got, ids)
+	}
+
+	want := [][]int{{1, 2}, {3, 6}, {4, 5}, {7}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("GroupVariants() = %v, want %v", got, want)
+	}
+}
+
+func TestSortVariants(t *testing.T) {
+	variants := []romm.Rom{
+		{ID: 1, Regions: []string{"Japan"}},
+		{ID: 2, Regions: []string{"USA"}},
+		{ID: 3, Regions: []string{"Europe"}, Languages: []string{"English", "French"}},
+		{ID: 4, Regions: []string{"USA"}, Revision: "1"},
+		{ID: 5, Regions: []string{"Brazil"}},
+	}
+
+	romm.SortVariants(variants, []string{"europe", "USA", "Japan"})
+
+	var got []int
+	for _, v := range variants {
+		got = append(got, v.ID)
+	}
+	if want := []int{3, 4, 2, 1, 5}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("SortVariants() = %v, want %v", got, want)
+	}
+}
+
+func TestSortVariantsRevisions(t *testing.T) {
+	variants := []romm.Rom{
+		{ID: 1, Revision: "Rev 2"},
+		{ID: 2, Revision: "Rev 10"},
+		{ID: 3},
+		{ID: 4, Revision: "Rev 1"},
+		{ID: 5, Revision: "Rev 1.1"},
+	}
+
+	romm.SortVariants(variants, nil)
+
+	var got []int
+	for _, v := range variants {
+		got = append(got, v.ID)
+	}
+	if want := []int{2, 1, 5, 4, 3}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("SortVariants() = %v, want %v", got, want)
+	}
+}
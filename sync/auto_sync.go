@@ -1,8 +1,10 @@
 package sync
 
 import (
+	"context"
 	"grout/internal"
 	"grout/romm"
+	"sync"
 	"sync/atomic"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
@@ -10,11 +12,13 @@ import (
 )
 
 type AutoSync struct {
+	mu         sync.Mutex // guards host, done and cancel
 	host       romm.Host
 	config     *internal.Config
 	icon       *gaba.DynamicStatusBarIcon
 	running    atomic.Bool
 	done       chan struct{}
+	cancel     context.CancelFunc
 	showButton atomic.Bool
 }
 
@@ -34,9 +38,29 @@ func (a *AutoSync) Icon() gaba.StatusBarIcon {
 }
 
 func (a *AutoSync) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.start()
+}
+
+// start launches a sync, a.mu must be held.
+func (a *AutoSync) start() {
+	ctx, cancel := context.WithCancel(context.Background())
 	a.running.Store(true)
 	a.done = make(chan struct{}) // Reinitialize channel for reuse
-	go a.run()
+	a.cancel = cancel
+	go a.run(ctx, a.host, a.done)
+}
+
+// Stop cancels an in-progress sync. Call Wait afterwards to block until it has unwound.
+func (a *AutoSync) Stop() {
+	a.mu.Lock()
+	cancel := a.cancel
+	a.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
 }
 
 func (a *AutoSync) IsRunning() bool {
@@ -44,7 +68,11 @@ func (a *AutoSync) IsRunning() bool {
 }
 
 func (a *AutoSync) Wait() {
-	<-a.done
+	a.mu.Lock()
+	done := a.done
+	a.mu.Unlock()
+
+	<-done
 }
 
 func (a *AutoSync) ShowButton() *atomic.Bool {
@@ -54,18 +82,36 @@ func (a *AutoSync) ShowButton() *atomic.Bool {
 // Trigger starts a new sync if one isn't already running.
 // Returns true if a new sync was started, false if one is already in progress.
 func (a *AutoSync) Trigger() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	if a.running.Load() {
 		return false
 	}
-	a.Start()
+	a.start()
 	return true
 }
 
 func (a *AutoSync) Host() romm.Host {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	return a.host
 }
 
-func (a *AutoSync) run() {
+// SetHost points later syncs at another server. A sync still running for the previous server
+// is stopped and waited out first, so it never mixes the two.
+func (a *AutoSync) SetHost(host romm.Host) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.running.Load() {
+		a.cancel()
+		<-a.done
+	}
+	a.host = host
+}
+
+func (a *AutoSync) run(ctx context.Context, host romm.Host, done chan struct{}) {
 	logger := gaba.GetLogger()
 	defer func() {
 		if r := recover(); r != nil {
@@ -73,13 +119,17 @@ func (a *AutoSync) run() {
 			a.icon.SetText(icons.CloudAlert)
 		}
 		a.running.Store(false)
-		close(a.done)
+		close(done)
 	}()
 
 	a.icon.SetText(icons.CloudRefresh)
 	logger.Debug("AutoSync: Starting save sync scan")
 
-	syncs, _, err := FindSaveSyncs(a.host, a.config)
+	syncs, _, err := FindSaveSyncs(ctx, host, a.config)
+	if ctx.Err() != nil {
+		logger.Debug("AutoSync: Cancelled during scan")
+		return
+	}
 	if err != nil {
 		logger.Error("AutoSync: Failed to find save syncs", "error", err)
 		a.icon.SetText(icons.CloudAlert)
@@ -97,6 +147,11 @@ func (a *AutoSync) run() {
 	hadError := false
 
 	for i := range syncs {
+		if ctx.Err() != nil {
+			logger.Debug("AutoSync: Cancelled", "remaining", len(syncs)-i)
+			return
+		}
+
 		s := &syncs[i]
 
 		switch s.Action {
@@ -110,7 +165,7 @@ func (a *AutoSync) run() {
 			continue
 		}
 
-		result := s.Execute(a.host, a.config)
+		result := s.Execute(ctx, host, a.config)
 		if !result.Success {
 			logger.Error("AutoSync: Sync failed", "game", s.GameBase, "error", result.Error)
 			hadError = true
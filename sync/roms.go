@@ -21,13 +21,22 @@ type LocalRomFile struct {
 	RomName     string
 	FSSlug      string
 	FileName    string
+	Path        string
 	RemoteSaves []romm.Save
 	SaveFile    *LocalSave
+
+	RemoteStates []romm.State
+	StateFiles   []LocalSave
 }
 
 func (lrf LocalRomFile) syncAction() SyncAction {
-	hasLocal := lrf.SaveFile != nil
-	hasRemote := len(lrf.RemoteSaves) > 0
+	return syncActionFor(lrf.SaveFile, lrf.RemoteSaves)
+}
+
+// syncActionFor decides which side of a save or state slot is newer.
+func syncActionFor(local *LocalSave, remote []romm.Save) SyncAction {
+	hasLocal := local != nil
+	hasRemote := len(remote) > 0
 
 	switch {
 	case !hasLocal && !hasRemote:
@@ -41,8 +50,8 @@ func (lrf LocalRomFile) syncAction() SyncAction {
 	// Both local and remote exist - compare timestamps
 	// Truncate to second precision to avoid timestamp precision issues
 	// API timestamps are typically second/millisecond precision, but filesystem is nanosecond
-	localTime := lrf.SaveFile.LastModified.Truncate(time.Second)
-	remoteTime := lrf.lastRemoteSave().UpdatedAt.Truncate(time.Second)
+	localTime := local.LastModified.Truncate(time.Second)
+	remoteTime := latestSave(remote).UpdatedAt.Truncate(time.Second)
 
 	switch localTime.Compare(remoteTime) {
 	case -1:
@@ -55,15 +64,19 @@ func (lrf LocalRomFile) syncAction() SyncAction {
 }
 
 func (lrf LocalRomFile) lastRemoteSave() romm.Save {
-	if len(lrf.RemoteSaves) == 0 {
+	return latestSave(lrf.RemoteSaves)
+}
+
+func latestSave(saves []romm.Save) romm.Save {
+	if len(saves) == 0 {
 		return romm.Save{}
 	}
 
-	slices.SortFunc(lrf.RemoteSaves, func(s1 romm.Save, s2 romm.Save) int {
+	slices.SortFunc(saves, func(s1 romm.Save, s2 romm.Save) int {
 		return s2.UpdatedAt.Compare(s1.UpdatedAt)
 	})
 
-	return lrf.RemoteSaves[0]
+	return saves[0]
 }
 
 // LocalRomScan holds the results of scanning local ROMs, keyed by platform fs_slug
@@ -107,6 +120,21 @@ func buildSaveFileMap(fsSlug string) map[string]*LocalSave {
 	return saveFileMap
 }
 
+// buildLocalFileMaps scans the saves and states of a platform, skipping whichever the config doesn't sync.
+func buildLocalFileMaps(fsSlug string, config *internal.Config) (map[string]*LocalSave, map[string][]LocalSave) {
+	var saveFileMap map[string]*LocalSave
+	var stateFileMap map[string][]LocalSave
+
+	if config == nil || config.SyncsSaves() {
+		saveFileMap = buildSaveFileMap(fsSlug)
+	}
+	if config != nil && config.SyncsStates() {
+		stateFileMap = buildStateFileMap(fsSlug)
+	}
+
+	return saveFileMap, stateFileMap
+}
+
 func scanRomsByPlatform(baseRomDir string, platformMap map[string][]string, config *internal.Config, currentCFW cfw.CFW) map[string][]LocalRomFile {
 	logger := gaba.GetLogger()
 	result := make(map[string][]LocalRomFile)
@@ -152,8 +180,8 @@ func scanRomsByPlatform(baseRomDir string, platformMap map[string][]string, conf
 
 				if matched {
 					romDir := filepath.Join(baseRomDir, dirName)
-					saveFileMap := buildSaveFileMap(fsSlug)
-					roms := scanRomDirectory(fsSlug, romDir, saveFileMap)
+					saveFileMap, stateFileMap := buildLocalFileMaps(fsSlug, config)
+					roms := scanRomDirectory(fsSlug, romDir, saveFileMap, stateFileMap)
 					if len(roms) > 0 {
 						result[fsSlug] = append(result[fsSlug], roms...)
 						logger.Debug("Found ROMs for platform", "fsSlug", fsSlug, "dir", dirName, "count", len(roms))
@@ -200,8 +228,8 @@ func scanRomsByPlatform(baseRomDir string, platformMap map[string][]string, conf
 					return
 				}
 
-				saveFileMap := buildSaveFileMap(s)
-				roms := scanRomDirectory(s, romDir, saveFileMap)
+				saveFileMap, stateFileMap := buildLocalFileMaps(s, config)
+				roms := scanRomDirectory(s, romDir, saveFileMap, stateFileMap)
 				resultChan <- platformResult{fsSlug: s, roms: roms}
 				if len(roms) > 0 {
 					logger.Debug("Found ROMs for platform", "fsSlug", s, "count", len(roms))
@@ -226,7 +254,7 @@ func scanRomsByPlatform(baseRomDir string, platformMap map[string][]string, conf
 	return result
 }
 
-func scanRomDirectory(fsSlug, romDir string, saveFileMap map[string]*LocalSave) []LocalRomFile {
+func scanRomDirectory(fsSlug, romDir string, saveFileMap map[string]*LocalSave, stateFileMap map[string][]LocalSave) []LocalRomFile {
 	logger := gaba.GetLogger()
 	var roms []LocalRomFile
 
@@ -245,9 +273,11 @@ func scanRomDirectory(fsSlug, romDir string, saveFileMap map[string]*LocalSave)
 		}
 
 		rom := LocalRomFile{
-			FSSlug:   fsSlug,
-			FileName: entry.Name(),
-			SaveFile: saveFile,
+			FSSlug:     fsSlug,
+			FileName:   entry.Name(),
+			Path:       filepath.Join(romDir, entry.Name()),
+			SaveFile:   saveFile,
+			StateFiles: stateFileMap[stateFileBase(entry.Name())],
 		}
 
 		roms = append(roms, rom)
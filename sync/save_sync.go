@@ -1,10 +1,11 @@
 package sync
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"grout/cache"
 	"grout/internal"
-	"grout/internal/fileutil"
 	"grout/romm"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 )
 
 type SaveSync struct {
+	Kind     SyncKind
 	RomID    int
 	RomName  string
 	FSSlug   string
@@ -22,8 +24,21 @@ type SaveSync struct {
 	Local    *LocalSave
 	Remote   romm.Save
 	Action   SyncAction
+
+	// Slot and StateBase are only set for states: the slot suffix, e.g. ".state1",
+	// and the name the CFW stores the game's states under
+	Slot      string
+	StateBase string
 }
 
+// SyncKind tells battery saves and save states apart.
+type SyncKind string
+
+const (
+	KindSave  SyncKind = "SAVE"
+	KindState SyncKind = "STATE"
+)
+
 type SyncAction string
 
 const (
@@ -33,6 +48,7 @@ const (
 )
 
 type SyncResult struct {
+	Kind           SyncKind
 	GameName       string
 	RomDisplayName string
 	Action         SyncAction
@@ -43,11 +59,12 @@ type SyncResult struct {
 }
 
 type UnmatchedSave struct {
+	Kind     SyncKind
 	SavePath string
 	FSSlug   string
 }
 
-func (s *SaveSync) Execute(host romm.Host, config *internal.Config) SyncResult {
+func (s *SaveSync) Execute(ctx context.Context, host romm.Host, config *internal.Config) SyncResult {
 	logger := gaba.GetLogger()
 
 	// Strip file extension from ROM name for cleaner display
@@ -55,8 +72,13 @@ func (s *SaveSync) Execute(host romm.Host, config *internal.Config) SyncResult {
 	if displayName != "" {
 		displayName = strings.TrimSuffix(displayName, filepath.Ext(displayName))
 	}
+	if displayName != "" && s.Kind == KindState {
+		// Tell the slots of a game apart in the report
+		displayName += " [" + strings.TrimPrefix(s.Slot, ".") + "]"
+	}
 
 	result := SyncResult{
+		Kind:           s.Kind,
 		GameName:       s.GameBase,
 		RomDisplayName: displayName,
 		Action:         s.Action,
@@ -64,6 +86,7 @@ func (s *SaveSync) Execute(host romm.Host, config *internal.Config) SyncResult {
 	}
 
 	logger.Debug("Executing sync",
+		"kind", s.Kind,
 		"action", s.Action,
 		"gameBase", s.GameBase,
 		"romName", s.RomName,
@@ -72,7 +95,7 @@ func (s *SaveSync) Execute(host romm.Host, config *internal.Config) SyncResult {
 	var err error
 	switch s.Action {
 	case Upload:
-		result.FilePath, err = s.upload(host, config)
+		result.FilePath, err = s.upload(ctx, host, config)
 		logger.Debug("Upload complete", "filePath", result.FilePath, "err", err)
 	case Download:
 		if s.Local != nil {
@@ -82,7 +105,7 @@ func (s *SaveSync) Execute(host romm.Host, config *internal.Config) SyncResult {
 				return result
 			}
 		}
-		result.FilePath, err = s.download(host, config)
+		result.FilePath, err = s.download(ctx, host, config)
 	case Skip:
 		result.Success = true
 		return result
@@ -97,19 +120,14 @@ func (s *SaveSync) Execute(host romm.Host, config *internal.Config) SyncResult {
 	return result
 }
 
-func (s *SaveSync) download(host romm.Host, config *internal.Config) (string, error) {
+func (s *SaveSync) download(ctx context.Context, host romm.Host, config *internal.Config) (string, error) {
 	logger := gaba.GetLogger()
 	if config == nil {
 		return "", fmt.Errorf("config is nil")
 	}
 	rc := romm.NewClientFromHost(host, config.ApiTimeout)
 
-	logger.Debug("Downloading save", "saveID", s.Remote.ID, "downloadPath", s.Remote.DownloadPath)
-
-	saveData, err := rc.DownloadSave(s.Remote.DownloadPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to download save: %w", err)
-	}
+	logger.Debug("Downloading save", "kind", s.Kind, "saveID", s.Remote.ID, "downloadPath", s.Remote.DownloadPath)
 
 	var destDir string
 	if s.Local != nil {
@@ -117,25 +135,51 @@ func (s *SaveSync) download(host romm.Host, config *internal.Config) (string, er
 		destDir = filepath.Dir(s.Local.Path)
 	} else {
 		var err error
-		destDir, err = ResolveSavePath(s.FSSlug, s.RomID, config)
+		if s.Kind == KindState {
+			destDir, err = ResolveStatePath(s.FSSlug, s.RomID, s.Remote.Emulator, config)
+		} else {
+			destDir, err = ResolveSavePath(s.FSSlug, s.RomID, config)
+		}
 		if err != nil {
 			return "", fmt.Errorf("cannot determine save location: %w", err)
 		}
 	}
 
-	ext := normalizeExt(s.Remote.FileExtension)
-	filename := s.GameBase + ext
+	filename := s.GameBase + normalizeExt(s.Remote.FileExtension)
+	if s.Kind == KindState {
+		filename = s.StateBase + s.Slot
+	}
 	destPath := filepath.Join(destDir, filename)
 
-	if s.Local != nil && s.Local.Path != destPath {
-		defer func() { _ = os.Remove(s.Local.Path) }()
+	// Stream into a sibling temp file so a dropped connection never leaves a truncated save behind
+	tmpPath := destPath + ".download"
+	tmpFile, err := os.Create(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to write save file: %w", err)
 	}
 
-	err = os.WriteFile(destPath, saveData, 0644)
+	if s.Kind == KindState {
+		_, err = rc.DownloadStateContext(ctx, s.Remote.DownloadPath, tmpFile)
+	} else {
+		_, err = rc.DownloadSaveContext(ctx, s.Remote.DownloadPath, tmpFile)
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to download save: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		_ = os.Remove(tmpPath)
 		return "", fmt.Errorf("failed to write save file: %w", err)
 	}
 
+	if s.Local != nil && s.Local.Path != destPath {
+		_ = os.Remove(s.Local.Path)
+	}
+
 	err = os.Chtimes(destPath, s.Remote.UpdatedAt, s.Remote.UpdatedAt)
 	if err != nil {
 		return "", fmt.Errorf("failed to update file timestamp: %w", err)
@@ -148,7 +192,7 @@ func (s *SaveSync) download(host romm.Host, config *internal.Config) (string, er
 	return destPath, nil
 }
 
-func (s *SaveSync) upload(host romm.Host, config *internal.Config) (string, error) {
+func (s *SaveSync) upload(ctx context.Context, host romm.Host, config *internal.Config) (string, error) {
 	if s.Local == nil {
 		return "", fmt.Errorf("cannot upload: no local save file")
 	}
@@ -159,6 +203,10 @@ func (s *SaveSync) upload(host romm.Host, config *internal.Config) (string, erro
 	rc := romm.NewClientFromHost(host, config.ApiTimeout)
 
 	ext := normalizeExt(filepath.Ext(s.Local.Path))
+	if s.Kind == KindState {
+		// filepath.Ext would cut ".state.auto" down to ".auto"
+		ext = s.Slot
+	}
 
 	fileInfo, err := os.Stat(s.Local.Path)
 	if err != nil {
@@ -168,17 +216,22 @@ func (s *SaveSync) upload(host romm.Host, config *internal.Config) (string, erro
 	timestamp := modTime.Format("[2006-01-02 15-04-05-000]")
 
 	filename := s.GameBase + " " + timestamp + ext
-	tmp := filepath.Join(fileutil.TempDir(), "uploads", filename)
 
-	err = fileutil.CopyFile(s.Local.Path, tmp)
+	file, err := os.Open(s.Local.Path)
 	if err != nil {
 		return "", err
 	}
+	defer file.Close()
 
 	// Get emulator from the save folder path
 	emulator := filepath.Base(filepath.Dir(s.Local.Path))
 
-	uploadedSave, err := rc.UploadSave(s.RomID, tmp, emulator)
+	var uploadedSave romm.Save
+	if s.Kind == KindState {
+		uploadedSave, err = rc.UploadStateReaderContext(ctx, s.RomID, filename, file, emulator)
+	} else {
+		uploadedSave, err = rc.UploadSaveReaderContext(ctx, s.RomID, filename, file, emulator)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -205,15 +258,59 @@ func lookupRomID(romFile *LocalRomFile) (int, string) {
 	return 0, ""
 }
 
-func FindSaveSyncs(host romm.Host, config *internal.Config) ([]SaveSync, []UnmatchedSave, error) {
-	return FindSaveSyncsFromScan(host, config, ScanRoms())
+func FindSaveSyncs(ctx context.Context, host romm.Host, config *internal.Config) ([]SaveSync, []UnmatchedSave, error) {
+	return FindSaveSyncsFromScan(ctx, host, config, ScanRoms())
+}
+
+// CheckServerSupport returns an UnsupportedError when the server is too old for what the config syncs.
+func CheckServerSupport(host romm.Host, config *internal.Config) error {
+	capabilities := host.Capabilities()
+	if config.SyncsSaves() {
+		if err := capabilities.Require(romm.CapabilitySavesByPlatform); err != nil {
+			return err
+		}
+	}
+	if config.SyncsStates() {
+		if err := capabilities.Require(romm.CapabilityStates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchRemoteFiles lists the saves or states RomM holds for a platform. It returns false when the
+// request failed, a platform without any files is not a failure.
+func fetchRemoteFiles(kind SyncKind, fsSlug string, fetch func() ([]romm.Save, error)) ([]romm.Save, bool) {
+	logger := gaba.GetLogger()
+
+	files, err := fetch()
+	if errors.Is(err, romm.ErrNotFound) {
+		// Nothing stored for this platform yet, not a failure
+		logger.Debug("FindSaveSyncs: Nothing stored for platform", "kind", kind, "fsSlug", fsSlug)
+		return nil, true
+	}
+	if err != nil {
+		var apiErr *romm.APIError
+		if errors.As(err, &apiErr) {
+			logger.Warn("FindSaveSyncs: RomM rejected request", "kind", kind, "fsSlug", fsSlug, "status", apiErr.StatusCode, "detail", apiErr.Detail)
+		} else {
+			logger.Warn("FindSaveSyncs: Could not retrieve files for platform", "kind", kind, "fsSlug", fsSlug, "error", err)
+		}
+		return nil, false
+	}
+
+	logger.Debug("FindSaveSyncs: Retrieved files for platform", "kind", kind, "fsSlug", fsSlug, "count", len(files))
+	return files, true
 }
 
-func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal LocalRomScan) ([]SaveSync, []UnmatchedSave, error) {
+func FindSaveSyncsFromScan(ctx context.Context, host romm.Host, config *internal.Config, scanLocal LocalRomScan) ([]SaveSync, []UnmatchedSave, error) {
 	logger := gaba.GetLogger()
 	if config == nil {
 		return nil, nil, fmt.Errorf("config is nil")
 	}
+	if err := CheckServerSupport(host, config); err != nil {
+		return nil, nil, err
+	}
 	rc := romm.NewClientFromHost(host, config.ApiTimeout)
 
 	logger.Debug("FindSaveSyncs: Scanned local ROMs", "platformCount", len(scanLocal))
@@ -228,7 +325,7 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 	}
 	if err != nil || len(platforms) == 0 {
 		// Fall back to API if cache miss
-		platforms, err = rc.GetPlatforms()
+		platforms, err = rc.GetPlatformsContext(ctx)
 		if err != nil {
 			logger.Error("FindSaveSyncs: Could not retrieve platforms", "error", err)
 			return []SaveSync{}, nil, err
@@ -240,11 +337,13 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 		fsSlugToPlatformID[p.FSSlug] = p.ID
 	}
 
-	// Fetch saves per platform in parallel (saves are not cached - always fresh from API)
+	// Fetch saves and states per platform in parallel (they are not cached - always fresh from API)
 	type platformFetchResult struct {
 		fsSlug   string
 		saves    []romm.Save
-		hasError bool
+		states   []romm.State
+		savesOK  bool
+		statesOK bool
 	}
 
 	resultChan := make(chan platformFetchResult, len(scanLocal))
@@ -264,17 +363,18 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 			result := platformFetchResult{
 				fsSlug: fsSlug,
 			}
+			query := romm.SaveQuery{PlatformID: platformID}
 
-			// Fetch saves for this platform (always from API - saves need to be fresh)
-			platformSaves, err := rc.GetSaves(romm.SaveQuery{PlatformID: platformID})
-			if err != nil {
-				logger.Warn("FindSaveSyncs: Could not retrieve saves for platform", "fsSlug", fsSlug, "error", err)
-				result.hasError = true
-				resultChan <- result
-				return
+			if config.SyncsSaves() {
+				result.saves, result.savesOK = fetchRemoteFiles(KindSave, fsSlug, func() ([]romm.Save, error) {
+					return rc.GetSavesContext(ctx, query)
+				})
+			}
+			if config.SyncsStates() {
+				result.states, result.statesOK = fetchRemoteFiles(KindState, fsSlug, func() ([]romm.Save, error) {
+					return rc.GetStatesContext(ctx, query)
+				})
 			}
-			result.saves = platformSaves
-			logger.Debug("FindSaveSyncs: Retrieved saves for platform", "fsSlug", fsSlug, "count", len(platformSaves))
 
 			resultChan <- result
 		}(fsSlug, platformID)
@@ -285,26 +385,35 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 		close(resultChan)
 	}()
 
-	// Collect saves by ROM ID
+	// Collect saves and states by ROM ID
 	savesByRomID := make(map[int][]romm.Save)
+	statesByRomID := make(map[int][]romm.State)
 	for result := range resultChan {
-		if result.hasError {
-			continue
+		if result.savesOK {
+			for _, s := range result.saves {
+				savesByRomID[s.RomID] = append(savesByRomID[s.RomID], s)
+			}
 		}
-
-		for _, s := range result.saves {
-			savesByRomID[s.RomID] = append(savesByRomID[s.RomID], s)
+		if result.statesOK {
+			for _, s := range result.states {
+				statesByRomID[s.RomID] = append(statesByRomID[s.RomID], s)
+			}
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return []SaveSync{}, nil, err
+	}
+
 	// Match local ROMs to cached ROMs by filename
 	var unmatched []UnmatchedSave
 	for fsSlug, localRoms := range scanLocal {
 		for idx := range localRoms {
 			romFile := &scanLocal[fsSlug][idx]
 
-			// Skip if no save file and no remote saves exist
-			if romFile.SaveFile == nil && len(savesByRomID) == 0 {
+			// Skip if nothing is stored locally and no remote saves or states exist
+			hasLocal := romFile.SaveFile != nil || len(romFile.StateFiles) > 0
+			if !hasLocal && len(savesByRomID) == 0 && len(statesByRomID) == 0 {
 				continue
 			}
 
@@ -314,6 +423,7 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 			if romID == 0 {
 				if romFile.SaveFile != nil {
 					unmatched = append(unmatched, UnmatchedSave{
+						Kind:     KindSave,
 						SavePath: romFile.SaveFile.Path,
 						FSSlug:   fsSlug,
 					})
@@ -322,6 +432,13 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 						"romFile", romFile.FileName,
 						"fsSlug", fsSlug)
 				}
+				for _, state := range romFile.StateFiles {
+					unmatched = append(unmatched, UnmatchedSave{
+						Kind:     KindState,
+						SavePath: state.Path,
+						FSSlug:   fsSlug,
+					})
+				}
 				continue
 			}
 
@@ -332,6 +449,10 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 				romFile.RemoteSaves = saves
 				logger.Debug("Found remote saves for ROM", "romName", romName, "saveCount", len(saves))
 			}
+			if states, ok := statesByRomID[romID]; ok {
+				romFile.RemoteStates = states
+				logger.Debug("Found remote states for ROM", "romName", romName, "stateCount", len(states))
+			}
 		}
 	}
 
@@ -345,12 +466,14 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 					"romName", r.RomName,
 					"romID", r.RomID,
 					"hasLocalSave", r.SaveFile != nil,
-					"remoteSaveCount", len(r.RemoteSaves))
+					"remoteSaveCount", len(r.RemoteSaves),
+					"localStateCount", len(r.StateFiles),
+					"remoteStateCount", len(r.RemoteStates))
 			}
+			baseName := strings.TrimSuffix(r.FileName, filepath.Ext(r.FileName))
+
 			action := r.syncAction()
 			if action == Upload || action == Download {
-				baseName := strings.TrimSuffix(r.FileName, filepath.Ext(r.FileName))
-
 				// Create unique key for deduplication
 				var key string
 				if r.SaveFile != nil {
@@ -362,18 +485,53 @@ func FindSaveSyncsFromScan(host romm.Host, config *internal.Config, scanLocal Lo
 				}
 
 				// Skip if already added (happens when multiple fs_slugs share same save dir)
+				if _, exists := syncMap[key]; !exists {
+					syncMap[key] = SaveSync{
+						Kind:     KindSave,
+						RomID:    r.RomID,
+						RomName:  r.RomName,
+						FSSlug:   fsSlug,
+						GameBase: baseName,
+						Local:    r.SaveFile,
+						Remote:   r.lastRemoteSave(),
+						Action:   action,
+					}
+				}
+			}
+
+			if r.RomID == 0 {
+				continue
+			}
+
+			// Every state slot is synced on its own
+			for slot, files := range r.stateSlots() {
+				action := syncActionFor(files.Local, files.Remote)
+				if action != Upload && action != Download {
+					continue
+				}
+
+				var key string
+				if files.Local != nil {
+					key = files.Local.Path
+				} else {
+					key = fmt.Sprintf("download_state_%d%s", r.RomID, slot)
+				}
+
 				if _, exists := syncMap[key]; exists {
 					continue
 				}
 
 				syncMap[key] = SaveSync{
-					RomID:    r.RomID,
-					RomName:  r.RomName,
-					FSSlug:   fsSlug,
-					GameBase: baseName,
-					Local:    r.SaveFile,
-					Remote:   r.lastRemoteSave(),
-					Action:   action,
+					Kind:      KindState,
+					RomID:     r.RomID,
+					RomName:   r.RomName,
+					FSSlug:    fsSlug,
+					GameBase:  baseName,
+					Local:     files.Local,
+					Remote:    latestSave(files.Remote),
+					Action:    action,
+					Slot:      slot,
+					StateBase: stateFileBase(r.FileName),
 				}
 			}
 		}
@@ -46,7 +46,26 @@ func (lc LocalSave) backup() error {
 func ResolveSavePath(fsSlug string, gameID int, config *internal.Config) (string, error) {
 	logger := gaba.GetLogger()
 	logger.Debug("ResolveSavePath called", "fsSlug", fsSlug, "gameID", gameID)
-	basePath := cfw.BaseSavePath()
+
+	selectedFolder, err := selectSaveFolder(fsSlug, gameID, config)
+	if err != nil {
+		return "", err
+	}
+
+	saveDir := filepath.Join(cfw.BaseSavePath(), selectedFolder)
+
+	if err := os.MkdirAll(saveDir, 0755); err != nil {
+		logger.Error("Failed to create save directory", "path", saveDir, "error", err)
+		return "", fmt.Errorf("failed to create save directory: %w", err)
+	}
+
+	return saveDir, nil
+}
+
+// selectSaveFolder picks the emulator folder for a game's saves: the per-game override,
+// then the platform mapping, then the first folder known for the platform.
+func selectSaveFolder(fsSlug string, gameID int, config *internal.Config) (string, error) {
+	logger := gaba.GetLogger()
 
 	emulatorFolders := cfw.EmulatorFoldersForFSSlug(fsSlug)
 
@@ -65,7 +84,7 @@ func ResolveSavePath(fsSlug string, gameID int, config *internal.Config) (string
 				if folder == override {
 					selectedFolder = override
 					logger.Debug("Using per-game override", "gameID", gameID, "folder", override)
-					goto createDir
+					return selectedFolder, nil
 				}
 			}
 			logger.Warn("Per-game override not valid for fsSlug, ignoring", "gameID", gameID, "override", override, "fsSlug", fsSlug)
@@ -79,36 +98,35 @@ func ResolveSavePath(fsSlug string, gameID int, config *internal.Config) (string
 				if folder == mapping {
 					selectedFolder = mapping
 					logger.Debug("Using platform mapping from config", "fsSlug", fsSlug, "folder", mapping)
-					goto createDir
+					return selectedFolder, nil
 				}
 			}
 			logger.Warn("Platform mapping not valid for fsSlug, ignoring", "mapping", mapping, "fsSlug", fsSlug)
 		}
 	}
 
-createDir:
 	logger.Debug("Final selectedFolder", "selectedFolder", selectedFolder)
-	saveDir := filepath.Join(basePath, selectedFolder)
-
-	if err := os.MkdirAll(saveDir, 0755); err != nil {
-		logger.Error("Failed to create save directory", "path", saveDir, "error", err)
-		return "", fmt.Errorf("failed to create save directory: %w", err)
-	}
-
-	return saveDir, nil
+	return selectedFolder, nil
 }
 
 func findSaveFiles(fsSlug string) []LocalSave {
-	logger := gaba.GetLogger()
-
-	basePath := cfw.BaseSavePath()
 	emulatorFolders := cfw.EmulatorFoldersForFSSlug(fsSlug)
 
 	if len(emulatorFolders) == 0 {
-		logger.Debug("No save folder mapping for fsSlug", "fsSlug", fsSlug)
+		gaba.GetLogger().Debug("No save folder mapping for fsSlug", "fsSlug", fsSlug)
 		return []LocalSave{}
 	}
 
+	// Knulli keeps states in the save folders, they are synced separately
+	return scanSaveFolders(fsSlug, cfw.BaseSavePath(), emulatorFolders, func(name string) bool {
+		return !isStateFile(name)
+	})
+}
+
+// scanSaveFolders lists the visible files accepted by include in each folder under basePath.
+func scanSaveFolders(fsSlug, basePath string, emulatorFolders []string, include func(name string) bool) []LocalSave {
+	logger := gaba.GetLogger()
+
 	// Use channels and goroutines to scan directories in parallel
 	type scanResult struct {
 		saves []LocalSave
@@ -144,6 +162,10 @@ func findSaveFiles(fsSlug string) []LocalSave {
 			result.saves = make([]LocalSave, 0, len(visibleFiles))
 
 			for _, entry := range visibleFiles {
+				if !include(entry.Name()) {
+					continue
+				}
+
 				savePath := filepath.Join(sd, entry.Name())
 
 				fileInfo, err := entry.Info()
@@ -0,0 +1,142 @@
+package sync
+
+import (
+	"fmt"
+	"grout/cfw"
+	"grout/internal"
+	"grout/romm"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+)
+
+// stateSlotPattern matches the slot suffix of a state file: RetroArch writes
+// .state, .state1 and .state.auto, NextUI's minarch writes .st0 through .st9.
+var stateSlotPattern = regexp.MustCompile(`(?i)\.(state\d*(\.auto)?|st\d)$`)
+
+// stateSlot splits a state file name into the game part and the slot suffix.
+func stateSlot(name string) (base, slot string, ok bool) {
+	loc := stateSlotPattern.FindStringIndex(name)
+	if loc == nil {
+		return name, "", false
+	}
+	return name[:loc[0]], strings.ToLower(name[loc[0]:]), true
+}
+
+func isStateFile(name string) bool {
+	_, _, ok := stateSlot(name)
+	return ok
+}
+
+// remoteStateSlot returns the slot a remote state was uploaded from. States uploaded
+// by other clients may not follow our naming, those fall back to the file extension.
+func remoteStateSlot(state romm.State) string {
+	if _, slot, ok := stateSlot(state.FileName); ok {
+		return slot
+	}
+	return strings.ToLower(normalizeExt(state.FileExtension))
+}
+
+// stateFileBase returns the name states of romFileName are stored under.
+// NextUI keeps the ROM extension in state names, the other CFWs drop it.
+func stateFileBase(romFileName string) string {
+	if cfw.GetCFW() == cfw.NextUI {
+		return romFileName
+	}
+	return strings.TrimSuffix(romFileName, filepath.Ext(romFileName))
+}
+
+func findStateFiles(fsSlug string) []LocalSave {
+	stateFolders := cfw.StateFoldersForFSSlug(fsSlug)
+
+	if len(stateFolders) == 0 {
+		gaba.GetLogger().Debug("No state folders for fsSlug", "fsSlug", fsSlug)
+		return []LocalSave{}
+	}
+
+	return scanSaveFolders(fsSlug, cfw.BaseStatePath(), stateFolders, isStateFile)
+}
+
+// buildStateFileMap groups the local states of a platform by the game part of their name.
+func buildStateFileMap(fsSlug string) map[string][]LocalSave {
+	stateFileMap := make(map[string][]LocalSave)
+	for _, state := range findStateFiles(fsSlug) {
+		base, _, _ := stateSlot(filepath.Base(state.Path))
+		stateFileMap[base] = append(stateFileMap[base], state)
+	}
+	return stateFileMap
+}
+
+// ResolveStatePath returns the folder a downloaded state is written to. The folder of the emulator
+// that made the state wins, then the folder saves for the game go to, then the first one found.
+func ResolveStatePath(fsSlug string, gameID int, emulator string, config *internal.Config) (string, error) {
+	logger := gaba.GetLogger()
+
+	stateFolders := cfw.StateFoldersForFSSlug(fsSlug)
+	if len(stateFolders) == 0 {
+		return "", fmt.Errorf("no state folder for fsSlug: %s", fsSlug)
+	}
+
+	selectedFolder := stateFolders[0]
+	saveFolder, _ := selectSaveFolder(fsSlug, gameID, config)
+
+	for _, folder := range stateFolders {
+		if emulator != "" && (folder == emulator || filepath.Base(folder) == emulator) {
+			selectedFolder = folder
+			break
+		}
+		if folder == saveFolder {
+			selectedFolder = folder
+		}
+	}
+
+	logger.Debug("Resolved state folder", "fsSlug", fsSlug, "emulator", emulator, "folder", selectedFolder)
+	stateDir := filepath.Join(cfw.BaseStatePath(), selectedFolder)
+
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		logger.Error("Failed to create state directory", "path", stateDir, "error", err)
+		return "", fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return stateDir, nil
+}
+
+// stateSlotFiles pairs the newest local state in a slot with the remote states for that slot.
+type stateSlotFiles struct {
+	Local  *LocalSave
+	Remote []romm.State
+}
+
+// stateSlots groups the local and remote states of a ROM by slot.
+func (lrf LocalRomFile) stateSlots() map[string]*stateSlotFiles {
+	slots := make(map[string]*stateSlotFiles)
+	slotFor := func(slot string) *stateSlotFiles {
+		if slots[slot] == nil {
+			slots[slot] = &stateSlotFiles{}
+		}
+		return slots[slot]
+	}
+
+	for i := range lrf.StateFiles {
+		_, slot, _ := stateSlot(filepath.Base(lrf.StateFiles[i].Path))
+		files := slotFor(slot)
+		// The same slot can exist in several emulator folders, the newest one is synced
+		if files.Local == nil || lrf.StateFiles[i].LastModified.After(files.Local.LastModified) {
+			files.Local = &lrf.StateFiles[i]
+		}
+	}
+
+	for _, state := range lrf.RemoteStates {
+		slot := remoteStateSlot(state)
+		if slot == "" {
+			continue
+		}
+		files := slotFor(slot)
+		files.Remote = append(files.Remote, state)
+	}
+
+	return slots
+}
@@ -0,0 +1,88 @@
+package sync
+
+import (
+	"context"
+	"grout/cache"
+	"grout/internal/fileutil"
+	"os"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"go.uber.org/atomic"
+)
+
+// UnknownRom is a local ROM file that matches no game in the cache, neither by name nor by hash.
+type UnknownRom struct {
+	FSSlug   string
+	FileName string
+	Path     string
+	Size     int64
+}
+
+// FindUnknownRoms returns the ROMs in scan the games cache has no entry for. Files whose name
+// doesn't match are hashed before they are reported, so renamed dumps of known games are skipped.
+func FindUnknownRoms(ctx context.Context, scan LocalRomScan, progress *atomic.Float64) ([]UnknownRom, error) {
+	logger := gaba.GetLogger()
+
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return nil, cache.ErrNotInitialized
+	}
+
+	total := 0
+	for _, roms := range scan {
+		total += len(roms)
+	}
+
+	var unknown []UnknownRom
+	checked := 0
+	for fsSlug, roms := range scan {
+		for _, rom := range roms {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			checked++
+			if progress != nil && total > 0 {
+				progress.Store(float64(checked) / float64(total))
+			}
+
+			if _, _, found := cm.GetRomIDByFilename(fsSlug, rom.FileName); found {
+				continue
+			}
+
+			checksums, err := fileutil.FileChecksums(rom.Path)
+			if err != nil {
+				logger.Warn("Failed to hash local ROM", "path", rom.Path, "error", err)
+				continue
+			}
+			if romID, romName, found := cm.GetRomByHash(checksums.MD5, checksums.SHA1, checksums.CRC); found {
+				logger.Debug("Matched local ROM by hash", "path", rom.Path, "romID", romID, "name", romName)
+				continue
+			}
+
+			info, err := os.Stat(rom.Path)
+			if err != nil {
+				continue
+			}
+
+			unknown = append(unknown, UnknownRom{
+				FSSlug:   fsSlug,
+				FileName: rom.FileName,
+				Path:     rom.Path,
+				Size:     info.Size(),
+			})
+		}
+	}
+
+	slices.SortFunc(unknown, func(a, b UnknownRom) int {
+		if c := strings.Compare(a.FSSlug, b.FSSlug); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a.FileName), strings.ToLower(b.FileName))
+	})
+
+	logger.Debug("Found unknown local ROMs", "checked", checked, "unknown", len(unknown))
+	return unknown, nil
+}
@@ -29,6 +29,10 @@ func NewArtworkSyncScreen() *ArtworkSyncScreen {
 }
 
 func (s *ArtworkSyncScreen) Execute(config internal.Config, host romm.Host) ArtworkSyncOutput {
+	if !RequireOnline() {
+		return ArtworkSyncOutput{}
+	}
+
 	s.draw(ArtworkSyncInput{
 		Config: config,
 		Host:   host,
@@ -170,8 +174,7 @@ func (s *ArtworkSyncScreen) draw(input ArtworkSyncInput) {
 		return
 	}
 
-	headers := make(map[string]string)
-	headers["Authorization"] = input.Host.BasicAuthHeader()
+	headers := romm.NewClientFromHost(input.Host, input.Config.ApiTimeout).DownloadHeaders()
 
 	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
 		AutoContinue: true,
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"grout/bios"
 	"grout/cfw"
@@ -34,6 +35,21 @@ func NewBIOSDownloadScreen() *BIOSDownloadScreen {
 }
 
 func (s *BIOSDownloadScreen) Execute(config internal.Config, host romm.Host, platform romm.Platform) BIOSDownloadOutput {
+	if !RequireOnline() {
+		return BIOSDownloadOutput{Platform: platform}
+	}
+
+	var unsupportedErr *romm.UnsupportedError
+	if errors.As(host.Capabilities().Require(romm.CapabilityFirmware), &unsupportedErr) {
+		gaba.GetLogger().Warn("BIOS downloads are not supported by this server", "error", unsupportedErr)
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "bios_download_unsupported", Other: "BIOS downloads require RomM {{.Version}} or newer!\nPlease update your RomM server."}, map[string]interface{}{"Version": unsupportedErr.Minimum.String()}),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return BIOSDownloadOutput{Platform: platform}
+	}
+
 	result, err := s.draw(BIOSDownloadInput{
 		Config:   config,
 		Host:     host,
@@ -241,8 +257,7 @@ func (s *BIOSDownloadScreen) draw(input BIOSDownloadInput) (ScreenResult[BIOSDow
 			"size", item.firmware.FileSizeBytes)
 	}
 
-	headers := make(map[string]string)
-	headers["Authorization"] = input.Host.BasicAuthHeader()
+	headers := romm.NewClientFromHost(input.Host, input.Config.ApiTimeout).DownloadHeaders()
 
 	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
 		AutoContinue: true,
@@ -0,0 +1,22 @@
+package ui
+
+import (
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+)
+
+// BindCancelButton calls cancel when B is pressed during a cancellable process message.
+// The returned func removes the binding again.
+func BindCancelButton(name string, cancel func()) func() {
+	// gabagool chords need at least two buttons, listing B twice lets a single press trigger it
+	gaba.RegisterChord(name, []buttons.VirtualButton{
+		buttons.VirtualButtonB,
+		buttons.VirtualButtonB,
+	}, gaba.ChordOptions{
+		OnTrigger: cancel,
+	})
+
+	return func() {
+		gaba.UnregisterCombo(name)
+	}
+}
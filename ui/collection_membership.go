@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type membershipChange int
+
+const (
+	membershipUnchanged membershipChange = iota
+	membershipAdd
+	membershipRemove
+)
+
+type CollectionMembershipOutput struct {
+	Updated []romm.Collection
+}
+
+type CollectionMembershipScreen struct{}
+
+func NewCollectionMembershipScreen() *CollectionMembershipScreen {
+	return &CollectionMembershipScreen{}
+}
+
+// Execute lets the user add games to, or remove them from, their regular collections. Every
+// collection written to RomM is also saved to the cache and returned in the output.
+func (s *CollectionMembershipScreen) Execute(config internal.Config, host romm.Host, games []romm.Rom) CollectionMembershipOutput {
+	output := CollectionMembershipOutput{}
+	if len(games) == 0 || !RequireOnline() {
+		return output
+	}
+
+	logger := gaba.GetLogger()
+	client := romm.NewClientFromHost(host, config.ApiTimeout)
+
+	// RomM replaces a collection's games wholesale, so start from the server's lists rather than the cache
+	collections, err := fetchRegularCollections(client)
+	if err != nil {
+		logger.Error("Failed to fetch collections", "error", err)
+		gaba.ConfirmationMessage(
+			fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "collection_membership_load_failed", Other: "Failed to load collections: %v"}, nil), err),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return output
+	}
+
+	newCollectionText := i18n.Localize(&goi18n.Message{ID: "collection_new", Other: "New Collection"}, nil)
+	items := []gaba.ItemWithOptions{{
+		Item:    gaba.MenuItem{Text: newCollectionText},
+		Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+	}}
+	for _, collection := range collections {
+		items = append(items, s.buildCollectionItem(collection, games))
+	}
+
+	title := i18n.Localize(&goi18n.Message{ID: "collection_membership_title", Other: "Collections"}, nil)
+	if len(games) > 1 {
+		title = i18n.Localize(&goi18n.Message{ID: "collection_membership_title_multi", Other: "Collections ({{.Count}} Games)"}, map[string]interface{}{"Count": len(games)})
+	}
+
+	result, err := gaba.OptionsList(
+		title,
+		gaba.OptionListSettings{
+			FooterHelpItems:      OptionsListFooter(),
+			InitialSelectedIndex: 0,
+			StatusBar:            StatusBar(),
+			SmallTitle:           true,
+		},
+		items,
+	)
+	if err != nil {
+		if !errors.Is(err, gaba.ErrCancelled) {
+			logger.Error("Collection membership screen error", "error", err)
+		}
+		return output
+	}
+
+	var pending []romm.Collection
+	for _, item := range result.Items {
+		collection, ok := item.Item.Metadata.(romm.Collection)
+		if !ok {
+			continue
+		}
+		change, _ := item.Options[item.SelectedOption].Value.(membershipChange)
+		if romIDs, changed := applyMembershipChange(collection.ROMIDs, games, change); changed {
+			collection.ROMIDs = romIDs
+			pending = append(pending, collection)
+		}
+	}
+
+	if result.Action == gaba.ListActionSelected && items[result.Selected].Item.Text == newCollectionText {
+		if name, ok := promptCollectionName(""); ok {
+			pending = append(pending, romm.Collection{Name: name, ROMIDs: gameIDs(games)})
+		}
+	}
+
+	if len(pending) == 0 {
+		return output
+	}
+
+	failed := 0
+	gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "collection_membership_saving", Other: "Saving collections..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			for _, collection := range pending {
+				saved, err := saveCollection(client, collection)
+				if err != nil {
+					logger.Error("Failed to save collection", "collection", collection.Name, "error", err)
+					failed++
+					continue
+				}
+				output.Updated = append(output.Updated, saved)
+			}
+			return nil, nil
+		},
+	)
+
+	if failed > 0 {
+		gaba.ConfirmationMessage(
+			fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "collection_membership_save_failed", Other: "%d collection(s) could not be saved. Check the log for details."}, nil), failed),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+	}
+
+	return output
+}
+
+func (s *CollectionMembershipScreen) buildCollectionItem(collection romm.Collection, games []romm.Rom) gaba.ItemWithOptions {
+	item := gaba.ItemWithOptions{
+		Item: gaba.MenuItem{Text: collection.Name, Metadata: collection},
+	}
+
+	// A single game shows its current membership, several games can only be added or removed together
+	if len(games) == 1 {
+		item.Options = []gaba.Option{
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "collection_membership_excluded", Other: "Not Included"}, nil), Value: membershipRemove},
+			{DisplayName: i18n.Localize(&goi18n.Message{ID: "collection_membership_included", Other: "Included"}, nil), Value: membershipAdd},
+		}
+		item.SelectedOption = boolToIndex(slices.Contains(collection.ROMIDs, games[0].ID))
+		return item
+	}
+
+	item.Options = []gaba.Option{
+		{DisplayName: i18n.Localize(&goi18n.Message{ID: "collection_membership_unchanged", Other: "Unchanged"}, nil), Value: membershipUnchanged},
+		{DisplayName: i18n.Localize(&goi18n.Message{ID: "collection_membership_add", Other: "Add"}, nil), Value: membershipAdd},
+		{DisplayName: i18n.Localize(&goi18n.Message{ID: "collection_membership_remove", Other: "Remove"}, nil), Value: membershipRemove},
+	}
+	return item
+}
+
+// applyMembershipChange returns romIDs with games added or removed, and whether that changed anything.
+func applyMembershipChange(romIDs []int, games []romm.Rom, change membershipChange) ([]int, bool) {
+	if change == membershipUnchanged {
+		return romIDs, false
+	}
+
+	updated := slices.Clone(romIDs)
+	for _, game := range games {
+		idx := slices.Index(updated, game.ID)
+		switch {
+		case change == membershipAdd && idx < 0:
+			updated = append(updated, game.ID)
+		case change == membershipRemove && idx >= 0:
+			updated = slices.Delete(updated, idx, idx+1)
+		}
+	}
+
+	return updated, len(updated) != len(romIDs)
+}
+
+func fetchRegularCollections(client *romm.Client) ([]romm.Collection, error) {
+	var collections []romm.Collection
+	_, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "collection_membership_loading", Other: "Loading collections..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			var err error
+			collections, err = client.GetCollections()
+			return nil, err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	collections = slices.DeleteFunc(collections, func(c romm.Collection) bool {
+		return c.IsSmart || c.IsVirtual
+	})
+	slices.SortFunc(collections, func(a, b romm.Collection) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return collections, nil
+}
+
+// saveCollection creates the collection when it has no ID yet, writes its fields and games to
+// RomM, then mirrors the result into the cache so collection screens pick it up right away.
+func saveCollection(client *romm.Client, collection romm.Collection) (romm.Collection, error) {
+	if collection.ID == 0 {
+		created, err := client.CreateCollection(collection.Name, collection.Description)
+		if err != nil {
+			return romm.Collection{}, err
+		}
+		created.ROMIDs = collection.ROMIDs
+		collection = created
+	}
+
+	saved, err := client.UpdateCollection(collection)
+	if err != nil {
+		return romm.Collection{}, err
+	}
+	if saved.ROMIDs == nil {
+		saved.ROMIDs = collection.ROMIDs
+	}
+	saved.ROMCount = len(saved.ROMIDs)
+
+	if cm := cache.GetCacheManager(); cm != nil {
+		if err := cm.SaveCollection(saved); err != nil {
+			gaba.GetLogger().Warn("Failed to cache collection", "collection", saved.Name, "error", err)
+		}
+	}
+
+	return saved, nil
+}
+
+func promptCollectionName(initial string) (string, bool) {
+	res, err := gaba.Keyboard(initial, i18n.Localize(&goi18n.Message{ID: "help_exit_text", Other: "Press any button to close help"}, nil))
+	if err != nil {
+		if !errors.Is(err, gaba.ErrCancelled) {
+			gaba.GetLogger().Error("Error with keyboard", "error", err)
+		}
+		return "", false
+	}
+
+	name := strings.TrimSpace(res.Text)
+	return name, name != ""
+}
+
+func gameIDs(games []romm.Rom) []int {
+	ids := make([]int, 0, len(games))
+	for _, game := range games {
+		ids = append(ids, game.ID)
+	}
+	return ids
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"errors"
 	"grout/internal"
+	"grout/internal/constants"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
@@ -13,7 +14,9 @@ type CollectionsSettingsInput struct {
 	Config *internal.Config
 }
 
-type CollectionsSettingsOutput struct{}
+type CollectionsSettingsOutput struct {
+	ManageCollectionsClicked bool
+}
 
 type CollectionsSettingsScreen struct{}
 
@@ -58,6 +61,12 @@ func (s *CollectionsSettingsScreen) Draw(input CollectionsSettingsInput) (Screen
 		return withCode(output, gaba.ExitCodeError), err
 	}
 
+	if result.Action == gaba.ListActionSelected &&
+		items[result.Selected].Item.Text == i18n.Localize(&goi18n.Message{ID: "settings_manage_collections", Other: "Manage Collections"}, nil) {
+		output.ManageCollectionsClicked = true
+		return withCode(output, constants.ExitCodeManageCollections), nil
+	}
+
 	return success(output), nil
 }
 
@@ -95,6 +104,10 @@ func (s *CollectionsSettingsScreen) buildMenuItems(config *internal.Config) []ga
 			},
 			SelectedOption: collectionViewToIndex(config.CollectionView),
 		},
+		{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_manage_collections", Other: "Manage Collections"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		},
 	}
 }
 
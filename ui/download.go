@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"grout/cfw"
@@ -37,9 +38,12 @@ type downloadInput struct {
 
 type downloadOutput struct {
 	DownloadedGames []romm.Rom
+	MismatchedGames []romm.Rom
 	Platform        romm.Platform
 	AllGames        []romm.Rom
 	SearchFilter    string
+	// Unreachable is set when downloads failed because RomM couldn't be reached
+	Unreachable bool
 }
 
 type DownloadScreen struct{}
@@ -70,6 +74,7 @@ func (s *DownloadScreen) Execute(config internal.Config, host romm.Host, platfor
 			AllGames:     allGames,
 			Platform:     platform,
 			SearchFilter: searchFilter,
+			Unreachable:  romm.IsUnreachable(err),
 		}
 	}
 
@@ -77,43 +82,138 @@ func (s *DownloadScreen) Execute(config internal.Config, host romm.Host, platfor
 		gaba.GetLogger().Debug("Successfully downloaded games", "count", len(result.Value.DownloadedGames))
 	}
 
+	if len(result.Value.MismatchedGames) > 0 {
+		gaba.GetLogger().Warn("Some downloads did not match RomM's hashes", "count", len(result.Value.MismatchedGames))
+	}
+
 	return result.Value
 }
 
+// maxVerificationRetries bounds how often downloads that failed verification are offered again.
+const maxVerificationRetries = 3
+
 func (s *DownloadScreen) draw(input downloadInput) (ScreenResult[downloadOutput], error) {
 	logger := gaba.GetLogger()
 
+	result, err := s.download(input)
+	if err != nil || result.ExitCode != gaba.ExitCodeSuccess {
+		return result, err
+	}
+	output := result.Value
+
+	for attempt := 1; len(output.MismatchedGames) > 0; attempt++ {
+		if attempt > maxVerificationRetries || !confirmVerificationRetry(output.MismatchedGames) {
+			logger.Warn("Keeping downloads that failed verification", "count", len(output.MismatchedGames))
+			break
+		}
+
+		logger.Info("Retrying downloads that failed verification", "count", len(output.MismatchedGames), "attempt", attempt)
+
+		retryInput := input
+		retryInput.SelectedGames = output.MismatchedGames
+
+		retry, err := s.download(retryInput)
+		if err != nil || retry.ExitCode != gaba.ExitCodeSuccess {
+			logger.Error("Retry of mismatched downloads failed", "error", err)
+			break
+		}
+		output.MismatchedGames = retry.Value.MismatchedGames
+	}
+
+	return success(output), nil
+}
+
+// download fetches the selected games once, verifying and extracting what completed.
+func (s *DownloadScreen) download(input downloadInput) (ScreenResult[downloadOutput], error) {
+	logger := gaba.GetLogger()
+
 	output := downloadOutput{
 		Platform:     input.Platform,
 		AllGames:     input.AllGames,
 		SearchFilter: input.SearchFilter,
 	}
 
-	downloads, artDownloads := s.buildDownloads(input.Config, input.Host, input.Platform, input.SelectedGames)
+	downloads, artDownloads, sources := s.buildDownloads(input.Config, input.Host, input.Platform, input.SelectedGames)
 
-	headers := make(map[string]string)
-	headers["Authorization"] = input.Host.BasicAuthHeader()
+	client := romm.NewClientFromHost(input.Host, input.Config.DownloadTimeout)
+
+	headers := client.DownloadHeaders()
 
 	slices.SortFunc(downloads, func(a, b gaba.Download) int {
 		return strings.Compare(strings.ToLower(a.DisplayName), strings.ToLower(b.DisplayName))
 	})
 
-	logger.Debug("Starting ROM download", "downloads", downloads)
+	hasher := newDownloadHasher()
 
-	res, err := gaba.DownloadManager(downloads, headers, gaba.DownloadManagerOptions{
-		AutoContinue: input.Config.DownloadArt,
-	})
-	if err != nil {
-		logger.Error("Error downloading", "error", err)
+	res, fresh, err := s.resumeDownloads(client, downloads, sources, hasher)
+	cancelled := errors.Is(err, gaba.ErrCancelled)
+	if err != nil && !cancelled {
+		logger.Error("Error resuming downloads", "error", err)
+		return withCode(output, gaba.ExitCodeError), err
+	}
 
-		// Clean up any partial downloads when cancelled
-		if errors.Is(err, gaba.ErrCancelled) {
-			for _, d := range downloads {
-				fileutil.DeleteFile(d.Location)
+	if len(fresh) > 0 {
+		// The download manager writes into .part files so an interrupted transfer can be resumed later
+		managed := make([]gaba.Download, len(fresh))
+		for i, d := range fresh {
+			managed[i] = d
+			managed[i].Location = partPath(d.Location)
+		}
+
+		recordCtx, cancelRecord := context.WithCancel(context.Background())
+		recorded := make(chan struct{})
+		go func() {
+			defer close(recorded)
+			recordPartialDownloads(recordCtx, client, fresh, sources)
+		}()
+
+		gamesByID := make(map[int]romm.Rom, len(input.SelectedGames))
+		for _, g := range input.SelectedGames {
+			gamesByID[g.ID] = g
+		}
+		for i, d := range fresh {
+			if src, ok := sources[d.Location]; ok && verifiable(gamesByID[src.RomID]) {
+				hasher.follow(d.Location, managed[i].Location)
 			}
 		}
 
-		return withCode(output, gaba.ExitCodeError), err
+		logger.Debug("Starting ROM download", "downloads", managed)
+
+		managedRes, err := gaba.DownloadManager(managed, headers, gaba.DownloadManagerOptions{
+			AutoContinue: input.Config.DownloadArt,
+		})
+		cancelRecord()
+		<-recorded
+		hasher.finish()
+
+		if err != nil {
+			logger.Error("Error downloading", "error", err)
+
+			// Cancelled downloads keep their .part file when they can be resumed
+			if errors.Is(err, gaba.ErrCancelled) {
+				for _, d := range fresh {
+					discardPartialDownload(d.Location)
+				}
+			}
+
+			return withCode(output, gaba.ExitCodeError), err
+		}
+
+		for _, d := range fresh {
+			if slices.ContainsFunc(managedRes.Completed, func(c gaba.Download) bool { return c.DisplayName == d.DisplayName }) {
+				if err := finishPartialDownload(d.Location); err != nil {
+					logger.Error("Failed to finalize download", "name", d.DisplayName, "error", err)
+					res.Failed = append(res.Failed, gaba.DownloadError{Download: d, Error: err})
+					continue
+				}
+				res.Completed = append(res.Completed, d)
+			}
+		}
+
+		for _, f := range managedRes.Failed {
+			f.Download.Location = strings.TrimSuffix(f.Download.Location, partSuffix)
+			res.Failed = append(res.Failed, f)
+		}
 	}
 
 	logger.Debug("Download results", "completed", len(res.Completed), "failed", len(res.Failed))
@@ -121,22 +221,22 @@ func (s *DownloadScreen) draw(input downloadInput) (ScreenResult[downloadOutput]
 	if len(res.Failed) > 0 {
 		for _, f := range res.Failed {
 			logger.Warn("Download failed", "name", f.Download.DisplayName, "url", f.Download.URL, "error", f.Error)
-		}
-
-		for _, g := range downloads {
-			failedMatch := slices.ContainsFunc(res.Failed, func(de gaba.DownloadError) bool {
-				return de.Download.DisplayName == g.DisplayName
-			})
-			if failedMatch {
-				fileutil.DeleteFile(g.Location)
+			discardPartialDownload(f.Download.Location)
+			if romm.IsUnreachable(f.Error) {
+				output.Unreachable = true
 			}
 		}
 	}
 
 	if len(res.Completed) == 0 {
+		if cancelled {
+			return withCode(output, gaba.ExitCodeError), gaba.ErrCancelled
+		}
 		return withCode(output, gaba.ExitCodeError), nil
 	}
 
+	mismatched := s.verifyDownloads(res.Completed, sources, input.SelectedGames, hasher)
+
 	for _, g := range input.SelectedGames {
 		if !g.HasMultipleFiles {
 			continue
@@ -173,12 +273,17 @@ func (s *DownloadScreen) draw(input downloadInput) (ScreenResult[downloadOutput]
 			func() (interface{}, error) {
 				logger.Debug("Extracting multi-file ROM", "game", g.DisplayName, "dest", extractDir)
 
-				if err := fileutil.Unzip(tmpZipPath, extractDir, progress); err != nil {
+				checksums, err := fileutil.UnzipWithChecksums(tmpZipPath, extractDir, progress)
+				if err != nil {
 					logger.Error("Failed to extract multi-file ROM", "game", g.DisplayName, "error", err)
 					os.Remove(tmpZipPath)
 					return nil, err
 				}
 
+				if !extractedFilesMatch(g, checksums) {
+					mismatched = append(mismatched, g)
+				}
+
 				if cfw.GetCFW() == cfw.MuOS {
 					if err := muos.OrganizeMultiFileRom(extractDir, romDirectory, g.FsNameNoExt); err != nil {
 						logger.Error("Failed to organize multi-file ROM for muOS", "game", g.FsNameNoExt, "error", err)
@@ -280,7 +385,7 @@ func (s *DownloadScreen) draw(input downloadInput) (ScreenResult[downloadOutput]
 				Progress:            progress,
 			},
 			func() (interface{}, error) {
-				s.downloadArt(artDownloads, downloadedGames, headers, progress)
+				s.downloadArt(input.Host.HTTPClient(romm.DefaultClientTimeout), artDownloads, downloadedGames, headers, progress)
 				return nil, nil
 			},
 		)
@@ -291,12 +396,15 @@ func (s *DownloadScreen) draw(input downloadInput) (ScreenResult[downloadOutput]
 	}
 
 	output.DownloadedGames = downloadedGames
+	output.MismatchedGames = mismatched
+
 	return success(output), nil
 }
 
-func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host, platform romm.Platform, games []romm.Rom) ([]gaba.Download, []artDownload) {
+func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host, platform romm.Platform, games []romm.Rom) ([]gaba.Download, []artDownload, map[string]romSource) {
 	downloads := make([]gaba.Download, 0, len(games))
 	artDownloads := make([]artDownload, 0, len(games))
+	sources := make(map[string]romSource, len(games))
 
 	for _, g := range games {
 		gamePlatform := platform
@@ -310,18 +418,20 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 
 		romDirectory := config.GetPlatformRomDirectory(gamePlatform)
 		downloadLocation := ""
-
-		sourceURL := ""
+		fileName := ""
 
 		if g.HasMultipleFiles {
 			tmpDir := fileutil.TempDir()
 			downloadLocation = filepath.Join(tmpDir, fmt.Sprintf("grout_multirom_%d.zip", g.ID))
-			sourceURL, _ = url.JoinPath(host.URL(), "/api/roms/", strconv.Itoa(g.ID), "content", g.FsName)
+			fileName = g.FsName
 		} else {
 			downloadLocation = filepath.Join(romDirectory, g.Files[0].FileName)
-			sourceURL, _ = url.JoinPath(host.URL(), "/api/roms/", strconv.Itoa(g.ID), "content", g.Files[0].FileName)
+			fileName = g.Files[0].FileName
 		}
 
+		sourceURL, _ := url.JoinPath(host.URL(), "/api/roms/", strconv.Itoa(g.ID), "content", fileName)
+		sources[downloadLocation] = romSource{RomID: g.ID, FileName: fileName}
+
 		downloads = append(downloads, gaba.Download{
 			URL:         sourceURL,
 			Location:    downloadLocation,
@@ -329,6 +439,16 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 			Timeout:     config.DownloadTimeout,
 		})
 
+		// Manuals go through the download manager with their game so they count towards the download
+		if manualPath := g.ManualPath(); config.ShouldDownloadManuals() && manualPath != "" {
+			downloads = append(downloads, gaba.Download{
+				URL:         host.URL() + strings.ReplaceAll(manualPath, " ", "%20"),
+				Location:    filepath.Join(config.GetManualDirectory(gamePlatform), g.ManualFileName()),
+				DisplayName: i18n.Localize(&goi18n.Message{ID: "download_manual_name", Other: "{{.Name}} (Manual)"}, map[string]interface{}{"Name": g.Name}),
+				Timeout:     config.DownloadTimeout,
+			})
+		}
+
 		if config.DownloadArt && (g.PathCoverLarge != "" || g.PathCoverSmall != "" || g.URLCover != "") {
 			artDir := config.GetArtDirectory(gamePlatform)
 			artFileName := g.FsNameNoExt + ".png"
@@ -354,10 +474,64 @@ func (s *DownloadScreen) buildDownloads(config internal.Config, host romm.Host,
 		}
 	}
 
-	return downloads, artDownloads
+	return downloads, artDownloads, sources
+}
+
+// resumeDownloads continues any download that left a resumable .part file behind and returns
+// the downloads that still need to go through the download manager. When the user cancels, the
+// resumes finished so far are returned with gabagool.ErrCancelled and nothing is left to download.
+func (s *DownloadScreen) resumeDownloads(client *romm.Client, downloads []gaba.Download, sources map[string]romSource, hasher *downloadHasher) (gaba.DownloadResult, []gaba.Download, error) {
+	logger := gaba.GetLogger()
+
+	var res gaba.DownloadResult
+	fresh := make([]gaba.Download, 0, len(downloads))
+
+	for _, d := range downloads {
+		partial, ok := loadPartialDownload(d.Location)
+		if !ok || partial.URL != d.URL {
+			fresh = append(fresh, d)
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		unbind := BindCancelButton("cancel-download-resume", cancel)
+
+		progress := &atomic.Float64{}
+		_, err := gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "download_resuming", Other: "Resuming {{.Name}}...\nPress B to cancel"}, map[string]interface{}{"Name": d.DisplayName}),
+			gaba.ProcessMessageOptions{
+				ShowThemeBackground: true,
+				ShowProgressBar:     true,
+				Progress:            progress,
+				ProcessInput:        true,
+			},
+			func() (interface{}, error) {
+				return nil, resumeDownload(ctx, client, d, sources[d.Location], partial, progress, hasher)
+			},
+		)
+
+		unbind()
+		cancel()
+
+		if errors.Is(err, context.Canceled) {
+			logger.Info("Download resume cancelled by user", "name", d.DisplayName)
+			return res, nil, gaba.ErrCancelled
+		}
+
+		if err != nil {
+			res.Failed = append(res.Failed, gaba.DownloadError{Download: d, Error: err})
+			continue
+		}
+
+		logger.Debug("Resumed download completed", "name", d.DisplayName)
+		res.Completed = append(res.Completed, d)
+	}
+
+	return res, fresh, nil
 }
 
-func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames []romm.Rom, headers map[string]string, progress *atomic.Float64) {
+func (s *DownloadScreen) downloadArt(client *http.Client, artDownloads []artDownload, downloadedGames []romm.Rom, headers map[string]string, progress *atomic.Float64) {
 	logger := gaba.GetLogger()
 
 	downloadedGameNames := make(map[string]bool)
@@ -407,7 +581,6 @@ func (s *DownloadScreen) downloadArt(artDownloads []artDownload, downloadedGames
 			req.Header.Set(k, v)
 		}
 
-		client := &http.Client{Timeout: romm.DefaultClientTimeout}
 		resp, err := client.Do(req)
 		if err != nil {
 			logger.Warn("Failed to download art", "game", art.GameName, "url", art.URL, "error", err)
@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"grout/internal/fileutil"
+	"grout/romm"
+	"io"
+	"os"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"go.uber.org/atomic"
+)
+
+const (
+	partSuffix    = ".part"
+	sidecarSuffix = ".part.json"
+)
+
+// romSource identifies the file behind a download so it can be re-requested with a Range header.
+type romSource struct {
+	RomID    int
+	FileName string
+}
+
+// partialDownload is stored next to a .part file and records what the partial bytes belong to.
+type partialDownload struct {
+	URL          string `json:"url"`
+	Size         int64  `json:"size"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (p partialDownload) validator() string {
+	return romm.ContentInfo{ETag: p.ETag, LastModified: p.LastModified}.Validator()
+}
+
+func partPath(location string) string {
+	return location + partSuffix
+}
+
+func sidecarPath(location string) string {
+	return location + sidecarSuffix
+}
+
+func loadPartialDownload(location string) (partialDownload, bool) {
+	var partial partialDownload
+
+	if _, err := os.Stat(partPath(location)); err != nil {
+		return partial, false
+	}
+
+	data, err := os.ReadFile(sidecarPath(location))
+	if err != nil {
+		return partial, false
+	}
+
+	if err := json.Unmarshal(data, &partial); err != nil || partial.validator() == "" {
+		return partial, false
+	}
+
+	return partial, true
+}
+
+// savePartialDownload records the sidecar for a download, or removes it when the server gave
+// nothing to validate a resume against.
+func savePartialDownload(location, sourceURL string, info romm.ContentInfo) error {
+	if info.Validator() == "" || info.Size <= 0 {
+		return removeIfExists(sidecarPath(location))
+	}
+
+	data, err := json.Marshal(partialDownload{
+		URL:          sourceURL,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(sidecarPath(location), data, 0644)
+}
+
+// finishPartialDownload moves a completed .part file into place and drops its sidecar.
+func finishPartialDownload(location string) error {
+	if err := os.Rename(partPath(location), location); err != nil {
+		return fmt.Errorf("failed to move completed download into place: %w", err)
+	}
+	return removeIfExists(sidecarPath(location))
+}
+
+// discardPartialDownload keeps a .part file that can be resumed later and deletes it otherwise.
+func discardPartialDownload(location string) {
+	if _, ok := loadPartialDownload(location); ok {
+		gaba.GetLogger().Debug("Keeping partial download for resume", "location", location)
+		return
+	}
+
+	removeIfExists(partPath(location))
+	removeIfExists(sidecarPath(location))
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// recordPartialDownloads looks up size and validators for downloads before they start, so the
+// sidecar exists even when the connection drops mid-transfer.
+func recordPartialDownloads(ctx context.Context, client *romm.Client, downloads []gaba.Download, sources map[string]romSource) {
+	logger := gaba.GetLogger()
+
+	for _, d := range downloads {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Only ROM content can be resumed, extras such as manuals are fetched again
+		src, ok := sources[d.Location]
+		if !ok {
+			continue
+		}
+
+		info, err := client.GetRomContentInfoContext(ctx, src.RomID, src.FileName)
+		if err != nil {
+			logger.Debug("Unable to look up ROM content info, download won't be resumable", "name", d.DisplayName, "error", err)
+			continue
+		}
+
+		if err := savePartialDownload(d.Location, d.URL, info); err != nil {
+			logger.Warn("Failed to write partial download sidecar", "location", d.Location, "error", err)
+		}
+	}
+}
+
+// resumeDownload continues a .part file from where it stopped. If the server ignores the range or
+// the file changed since the sidecar was written, the download restarts from the beginning.
+// The file is hashed along the way and its checksums recorded with hasher.
+func resumeDownload(ctx context.Context, client *romm.Client, d gaba.Download, src romSource, partial partialDownload, progress *atomic.Float64, hasher *downloadHasher) error {
+	logger := gaba.GetLogger()
+
+	var offset int64
+	if stat, err := os.Stat(partPath(d.Location)); err == nil {
+		offset = stat.Size()
+	}
+	// A complete or oversized part file can't be verified with a range, so fetch it again
+	if offset >= partial.Size {
+		offset = 0
+	}
+
+	content, err := client.OpenRomContentContext(ctx, src.RomID, src.FileName, offset, partial.validator())
+	if err != nil {
+		return err
+	}
+	defer content.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if content.Offset == 0 {
+		if offset > 0 {
+			logger.Info("Server sent the full file, restarting download", "name", d.DisplayName, "offset", offset)
+		}
+		flags |= os.O_TRUNC
+		if err := savePartialDownload(d.Location, d.URL, content.Info); err != nil {
+			logger.Warn("Failed to update partial download sidecar", "location", d.Location, "error", err)
+		}
+	} else {
+		logger.Debug("Resuming download", "name", d.DisplayName, "offset", content.Offset, "size", content.Info.Size)
+		flags |= os.O_APPEND
+	}
+
+	cw := fileutil.NewChecksumWriter()
+	if content.Offset > 0 {
+		// Hashing the bytes already on disk lets the appended ones finish a single pass over the file
+		part, err := os.Open(partPath(d.Location))
+		if err != nil {
+			return err
+		}
+		_, err = io.CopyN(cw, part, content.Offset)
+		part.Close()
+		if err != nil {
+			return fmt.Errorf("failed to hash partial download: %w", err)
+		}
+	}
+
+	out, err := os.OpenFile(partPath(d.Location), flags, 0644)
+	if err != nil {
+		return err
+	}
+
+	written := content.Offset
+	total := content.Info.Size
+	_, err = io.Copy(io.MultiWriter(out, cw), io.TeeReader(content.Body, progressFunc(func(n int) {
+		written += int64(n)
+		if total > 0 {
+			progress.Store(float64(written) / float64(total))
+		}
+	})))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("download interrupted at %d bytes: %w", written, err)
+	}
+
+	if total > 0 && written != total {
+		return fmt.Errorf("download incomplete: got %d of %d bytes", written, total)
+	}
+
+	if err := finishPartialDownload(d.Location); err != nil {
+		return err
+	}
+
+	hasher.record(d.Location, cw.Sum())
+	return nil
+}
+
+type progressFunc func(n int)
+
+func (f progressFunc) Write(p []byte) (int, error) {
+	f(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"grout/internal/fileutil"
+	"grout/romm"
+	"path/filepath"
+	"sync"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"go.uber.org/atomic"
+)
+
+// downloadHasher collects the checksums of ROM downloads as they are written, so verification
+// doesn't have to read every file a second time.
+type downloadHasher struct {
+	mu   sync.Mutex
+	sums map[string]fileutil.Checksums
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newDownloadHasher() *downloadHasher {
+	return &downloadHasher{
+		sums: make(map[string]fileutil.Checksums),
+		done: make(chan struct{}),
+	}
+}
+
+// follow hashes the file at path while the download manager writes it and records the result
+// for location. Call finish once the download manager has returned.
+func (h *downloadHasher) follow(location, path string) {
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		sums, err := fileutil.FollowChecksums(path, h.done)
+		if err != nil {
+			gaba.GetLogger().Debug("Unable to hash download while it was written", "location", location, "error", err)
+			return
+		}
+		h.record(location, sums)
+	}()
+}
+
+func (h *downloadHasher) finish() {
+	close(h.done)
+	h.wg.Wait()
+}
+
+func (h *downloadHasher) record(location string, sums fileutil.Checksums) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sums[location] = sums
+}
+
+func (h *downloadHasher) checksums(location string) (fileutil.Checksums, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sums, ok := h.sums[location]
+	return sums, ok
+}
+
+// verifiable reports whether a download of g can be checked against RomM's hashes once it
+// completes. Multi-file games are checked while they are extracted instead.
+func verifiable(g romm.Rom) bool {
+	return !g.HasMultipleFiles && len(g.Files) > 0 && !g.Files[0].Checksums().IsEmpty()
+}
+
+// verifyDownloads checks completed single-file downloads and returns the games whose files
+// don't match the hashes RomM reported. Files the hasher missed are read again to hash them.
+func (s *DownloadScreen) verifyDownloads(completed []gaba.Download, sources map[string]romSource, games []romm.Rom, hasher *downloadHasher) []romm.Rom {
+	logger := gaba.GetLogger()
+
+	gamesByID := make(map[int]romm.Rom, len(games))
+	for _, g := range games {
+		gamesByID[g.ID] = g
+	}
+
+	toVerify := make([]gaba.Download, 0, len(completed))
+	for _, d := range completed {
+		g, ok := gamesByID[sources[d.Location].RomID]
+		if !ok || !verifiable(g) {
+			continue
+		}
+		toVerify = append(toVerify, d)
+	}
+
+	if len(toVerify) == 0 {
+		return nil
+	}
+
+	var mismatched []romm.Rom
+
+	progress := &atomic.Float64{}
+	gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "download_verifying", Other: "Verifying downloads..."}, nil),
+		gaba.ProcessMessageOptions{
+			ShowThemeBackground: true,
+			ShowProgressBar:     true,
+			Progress:            progress,
+		},
+		func() (interface{}, error) {
+			for i, d := range toVerify {
+				g := gamesByID[sources[d.Location].RomID]
+				expected := g.Files[0].Checksums()
+
+				actual, ok := hasher.checksums(d.Location)
+				var err error
+				if !ok {
+					actual, err = fileutil.FileChecksums(d.Location)
+				}
+				if err != nil {
+					logger.Warn("Unable to hash downloaded ROM", "game", g.Name, "error", err)
+				} else if !actual.Matches(expected) {
+					logger.Warn("Downloaded ROM failed verification", "game", g.Name, "expected", expected, "actual", actual)
+					mismatched = append(mismatched, g)
+				}
+
+				progress.Store(float64(i+1) / float64(len(toVerify)))
+			}
+			return nil, nil
+		},
+	)
+
+	return mismatched
+}
+
+// extractedFilesMatch compares the checksums of files extracted from a multi-file ROM against
+// the matching entries in the game's file list. Files RomM doesn't know about are ignored.
+func extractedFilesMatch(g romm.Rom, checksums map[string]fileutil.Checksums) bool {
+	matches := true
+
+	for name, actual := range checksums {
+		for _, f := range g.Files {
+			if f.FileName != filepath.Base(name) {
+				continue
+			}
+			if !actual.Matches(f.Checksums()) {
+				gaba.GetLogger().Warn("Extracted file failed verification", "game", g.Name, "file", name, "expected", f.Checksums(), "actual", actual)
+				matches = false
+			}
+			break
+		}
+	}
+
+	return matches
+}
+
+// confirmVerificationRetry asks whether to download games that failed verification again.
+// Declining keeps the files as they are.
+func confirmVerificationRetry(mismatched []romm.Rom) bool {
+	_, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "download_verification_failed", Other: "{{.Count}} download(s) failed verification!\nThe files may be corrupted."}, map[string]interface{}{"Count": len(mismatched)}),
+		[]gaba.FooterHelpItem{
+			FooterKeep(),
+			FooterRetry(),
+		},
+		gaba.MessageOptions{},
+	)
+
+	return err == nil
+}
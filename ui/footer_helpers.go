@@ -29,6 +29,9 @@ func FooterLogout() gaba.FooterHelpItem   { return footerItem("X", "button_logou
 func FooterBIOS() gaba.FooterHelpItem     { return footerItem("Y", "button_bios", "BIOS") }
 func FooterSaveSync() gaba.FooterHelpItem { return footerItem("Y", "button_save_sync", "Sync") }
 func FooterMenu() gaba.FooterHelpItem     { return footerItem("Start", "button_menu", "Menu") }
+func FooterKeep() gaba.FooterHelpItem     { return footerItem("B", "button_keep", "Keep") }
+func FooterRetry() gaba.FooterHelpItem    { return footerItem("A", "button_retry", "Retry") }
+func FooterLater() gaba.FooterHelpItem    { return footerItem("B", "button_later", "Later") }
 
 func FooterStartConfirm() gaba.FooterHelpItem {
 	return footerItem("Start", "button_confirm", "Confirm")
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"grout/cache"
+	"grout/connectivity"
 	"grout/internal"
 	constants2 "grout/internal/constants"
 	"grout/internal/imageutil"
@@ -27,6 +28,8 @@ type GameDetailsInput struct {
 	Host     romm.Host
 	Platform romm.Platform
 	Game     romm.Rom
+	// Games is the list the game was picked from, used to look up its variants
+	Games []romm.Rom
 }
 
 type GameDetailsOutput struct {
@@ -65,28 +68,40 @@ func (s *GameDetailsScreen) Draw(input GameDetailsInput) (ScreenResult[GameDetai
 	if !internal.IsKidModeEnabled() {
 		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "button_options", Other: "Options"}, nil)})
 	}
-	footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_download", Other: "Download"}, nil)})
+	if connectivity.IsOffline() {
+		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_queue_download", Other: "Queue Download"}, nil)})
+	} else {
+		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_download", Other: "Download"}, nil)})
+	}
 
-	result, err := gaba.DetailScreen(input.Game.Name, options, footerItems)
+	for {
+		result, err := gaba.DetailScreen(input.Game.Name, options, footerItems)
 
-	if err != nil {
-		if errors.Is(err, gaba.ErrCancelled) {
-			return back(output), nil
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				return back(output), nil
+			}
+			logger.Error("Detail screen error", "error", err)
+			return withCode(output, gaba.ExitCodeError), err
 		}
-		logger.Error("Detail screen error", "error", err)
-		return withCode(output, gaba.ExitCodeError), err
-	}
 
-	if result.Action == gaba.DetailActionConfirmed {
-		output.DownloadRequested = true
-		return success(output), nil
-	}
+		if result.Action == gaba.DetailActionConfirmed {
+			// Backing out of the variant picker returns to the details
+			variant, ok := pickVariant(input.Config, input.Host, input.Game, input.Games)
+			if !ok {
+				continue
+			}
+			output.Game = variant
+			output.DownloadRequested = true
+			return success(output), nil
+		}
 
-	if result.Action == gaba.DetailActionTriggered {
-		return withCode(output, constants2.ExitCodeGameOptions), nil
-	}
+		if result.Action == gaba.DetailActionTriggered {
+			return withCode(output, constants2.ExitCodeGameOptions), nil
+		}
 
-	return back(output), nil
+		return back(output), nil
+	}
 }
 
 func (s *GameDetailsScreen) buildSections(input GameDetailsInput) []gaba.Section {
@@ -157,6 +172,13 @@ func (s *GameDetailsScreen) buildSections(input GameDetailsInput) []gaba.Section
 		})
 	}
 
+	if variants := variantIDs(game, input.Games); len(variants) > 0 {
+		metadata = append(metadata, gaba.MetadataItem{
+			Label: i18n.Localize(&goi18n.Message{ID: "game_details_variants", Other: "Variants"}, nil),
+			Value: fmt.Sprintf("%d", len(variants)+1),
+		})
+	}
+
 	if game.FsSizeBytes > 0 {
 		metadata = append(metadata, gaba.MetadataItem{
 			Label: i18n.Localize(&goi18n.Message{ID: "game_details_file_size", Other: "File Size"}, nil),
@@ -164,6 +186,13 @@ func (s *GameDetailsScreen) buildSections(input GameDetailsInput) []gaba.Section
 		})
 	}
 
+	if game.ManualPath() != "" {
+		metadata = append(metadata, gaba.MetadataItem{
+			Label: i18n.Localize(&goi18n.Message{ID: "game_details_manual", Other: "Manual"}, nil),
+			Value: i18n.Localize(&goi18n.Message{ID: "game_details_manual_available", Other: "Available"}, nil),
+		})
+	}
+
 	if game.HasMultipleFiles {
 		metadata = append(metadata, gaba.MetadataItem{
 			Label: i18n.Localize(&goi18n.Message{ID: "game_details_type", Other: "Type"}, nil),
@@ -243,6 +272,11 @@ func (s *GameDetailsScreen) getCoverImagePath(host romm.Host, game romm.Rom) str
 func (s *GameDetailsScreen) fetchImageFromURL(host romm.Host, imageURL string) []byte {
 	logger := gaba.GetLogger()
 
+	// Offline, only cached artwork is shown
+	if connectivity.IsOffline() {
+		return nil
+	}
+
 	imageURL = strings.ReplaceAll(imageURL, " ", "%20")
 
 	req, err := http.NewRequest("GET", imageURL, nil)
@@ -251,9 +285,9 @@ func (s *GameDetailsScreen) fetchImageFromURL(host romm.Host, imageURL string) [
 		return nil
 	}
 
-	req.SetBasicAuth(host.Username, host.Password)
+	req.Header.Set("Authorization", romm.NewClientFromHost(host).AuthorizationHeader())
 
-	client := &http.Client{Timeout: constants2.DefaultHTTPTimeout}
+	client := host.HTTPClient(constants2.DefaultHTTPTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		logger.Warn("Failed to fetch image", "url", imageURL, "error", err)
@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"grout/cfw"
 	"grout/internal"
 	"grout/romm"
@@ -16,11 +18,13 @@ import (
 
 type GameOptionsInput struct {
 	Config *internal.Config
+	Host   romm.Host
 	Game   romm.Rom
 }
 
 type GameOptionsOutput struct {
-	Config *internal.Config
+	Config             *internal.Config
+	UpdatedCollections []romm.Collection
 }
 
 type GameOptionsScreen struct{}
@@ -69,6 +73,17 @@ func (s *GameOptionsScreen) Draw(input GameOptionsInput) (ScreenResult[GameOptio
 		return withCode(output, gaba.ExitCodeError), err
 	}
 
+	if result.Action == gaba.ListActionSelected &&
+		items[result.Selected].Item.Text == i18n.Localize(&goi18n.Message{ID: "game_options_download_manual", Other: "Download Manual"}, nil) {
+		s.downloadManual(config, input.Host, input.Game)
+	}
+
+	if result.Action == gaba.ListActionSelected &&
+		items[result.Selected].Item.Text == i18n.Localize(&goi18n.Message{ID: "game_options_collections", Other: "Collections"}, nil) {
+		membership := NewCollectionMembershipScreen().Execute(*config, input.Host, []romm.Rom{input.Game})
+		output.UpdatedCollections = membership.Updated
+	}
+
 	return success(output), nil
 }
 
@@ -114,9 +129,73 @@ func (s *GameOptionsScreen) buildMenuItems(config *internal.Config, game romm.Ro
 		})
 	}
 
+	if game.GetLocalManualPath(config) != "" {
+		items = append(items, gaba.ItemWithOptions{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "game_options_download_manual", Other: "Download Manual"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		})
+	}
+
+	items = append(items, gaba.ItemWithOptions{
+		Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "game_options_collections", Other: "Collections"}, nil)},
+		Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+	})
+
 	return items
 }
 
+func (s *GameOptionsScreen) downloadManual(config *internal.Config, host romm.Host, game romm.Rom) {
+	if !RequireOnline() {
+		return
+	}
+
+	logger := gaba.GetLogger()
+	location := game.GetLocalManualPath(config)
+
+	_, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "game_options_downloading_manual", Other: "Downloading manual..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			if err := os.MkdirAll(filepath.Dir(location), 0755); err != nil {
+				return nil, fmt.Errorf("failed to create manual directory: %w", err)
+			}
+
+			tmpPath := location + ".download"
+			tmpFile, err := os.Create(tmpPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create manual file: %w", err)
+			}
+
+			client := romm.NewClientFromHost(host, config.DownloadTimeout)
+			_, err = client.DownloadManualContext(context.Background(), game, tmpFile)
+			if closeErr := tmpFile.Close(); err == nil {
+				err = closeErr
+			}
+			if err == nil {
+				err = os.Rename(tmpPath, location)
+			}
+			if err != nil {
+				os.Remove(tmpPath)
+				return nil, err
+			}
+
+			return nil, nil
+		},
+	)
+
+	if err != nil {
+		logger.Error("Failed to download manual", "game", game.Name, "error", err)
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "game_options_manual_failed", Other: "Unable to download the manual!"}, nil),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return
+	}
+
+	logger.Debug("Downloaded manual", "game", game.Name, "location", location)
+}
+
 func (s *GameOptionsScreen) applySettings(config *internal.Config, game romm.Rom, items []gaba.ItemWithOptions) {
 	logger := gaba.GetLogger()
 
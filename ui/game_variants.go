@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"grout/cache"
+	"grout/connectivity"
+	"grout/internal"
+	"grout/internal/stringutil"
+	"grout/romm"
+	"slices"
+	"strings"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	gabaconst "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// groupVariants keeps one entry for each title in the list, one game, one ROM. The entry is
+// the variant that best matches the region priority, named after the game along with how
+// many variants it stands for.
+func groupVariants(games []romm.Rom, priority []string) []romm.Rom {
+	groups := romm.GroupVariants(games)
+	grouped := make([]romm.Rom, 0, len(groups))
+
+	for _, group := range groups {
+		if len(group) > 1 {
+			romm.SortVariants(group, priority)
+		}
+
+		game := group[0]
+		if len(group) > 1 {
+			game.DisplayName = i18n.Localize(&goi18n.Message{ID: "games_list_variants", Other: "{{.Name}} [{{.Count}} Variants]"}, map[string]interface{}{
+				"Name":  stringutil.CleanRomName(game.Name),
+				"Count": len(group),
+			})
+		}
+		grouped = append(grouped, game)
+	}
+
+	return grouped
+}
+
+// variantIDs returns the IDs of the game's other variants: its RomM siblings and the games in
+// the loaded list grouped with it.
+func variantIDs(game romm.Rom, loaded []romm.Rom) []int {
+	ids := game.SiblingIDs()
+
+	for _, group := range romm.GroupVariants(append([]romm.Rom{game}, loaded...)) {
+		if group[0].ID != game.ID {
+			continue
+		}
+		for _, variant := range group[1:] {
+			ids = append(ids, variant.ID)
+		}
+		break
+	}
+
+	slices.Sort(ids)
+	return slices.DeleteFunc(slices.Compact(ids), func(id int) bool {
+		return id == game.ID
+	})
+}
+
+// pickVariant lets the user choose which of the game's variants to download, showing the
+// region, revision and size of each. Games without variants are returned as they are.
+func pickVariant(config *internal.Config, host romm.Host, game romm.Rom, loaded []romm.Rom) (romm.Rom, bool) {
+	if len(variantIDs(game, loaded)) == 0 {
+		return game, true
+	}
+
+	variants := resolveVariants(config, host, game, loaded)
+	if len(variants) < 2 {
+		return game, true
+	}
+
+	menuItems := make([]gaba.MenuItem, len(variants))
+	selectedIndex := 0
+	for i, variant := range variants {
+		menuItems[i] = gaba.MenuItem{Text: variantLabel(config, variant), Metadata: variant}
+		if variant.ID == game.ID {
+			selectedIndex = i
+		}
+	}
+
+	options := gaba.DefaultListOptions(stringutil.CleanRomName(game.Name), menuItems)
+	options.SmallTitle = true
+	options.SelectedIndex = selectedIndex
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		FooterBack(),
+		{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_download", Other: "Download"}, nil)},
+	}
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+	if err != nil || sel.Action != gaba.ListActionSelected || len(sel.Selected) == 0 {
+		return romm.Rom{}, false
+	}
+
+	return sel.Items[sel.Selected[0]].Metadata.(romm.Rom), true
+}
+
+// resolveVariants returns the game and its variants, best match for the region priority first.
+// Variants are taken from the loaded list first, then the cache, and only the rest are fetched
+// from RomM.
+func resolveVariants(config *internal.Config, host romm.Host, game romm.Rom, loaded []romm.Rom) []romm.Rom {
+	logger := gaba.GetLogger()
+	ids := variantIDs(game, loaded)
+
+	byID := make(map[int]romm.Rom, len(loaded))
+	for _, g := range loaded {
+		byID[g.ID] = g
+	}
+
+	var missing []int
+	for _, id := range ids {
+		if _, ok := byID[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	if cm := cache.GetCacheManager(); cm != nil && len(missing) > 0 {
+		if cached, err := cm.GetGamesByIDs(missing); err == nil {
+			for _, g := range cached {
+				byID[g.ID] = g
+			}
+		}
+	}
+
+	missing = slices.DeleteFunc(missing, func(id int) bool {
+		_, ok := byID[id]
+		return ok
+	})
+
+	// Offline, variants missing from the cache are left out
+	if len(missing) > 0 && !connectivity.IsOffline() {
+		client := romm.NewClientFromHost(host, config.ApiTimeout)
+		gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "game_details_loading_variants", Other: "Loading variants..."}, nil),
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (interface{}, error) {
+				for _, id := range missing {
+					rom, err := client.GetRom(id)
+					if err != nil {
+						logger.Warn("Unable to load game variant", "id", id, "error", err)
+						continue
+					}
+					byID[rom.ID] = rom
+				}
+				return nil, nil
+			},
+		)
+	}
+
+	variants := []romm.Rom{game}
+	for _, id := range ids {
+		if variant, ok := byID[id]; ok {
+			variants = append(variants, variant)
+		}
+	}
+
+	romm.SortVariants(variants, config.RegionPreferences())
+	return variants
+}
+
+func variantLabel(config *internal.Config, rom romm.Rom) string {
+	var parts []string
+	tags := rom.NameTags()
+
+	switch {
+	case len(rom.Regions) > 0:
+		parts = append(parts, strings.Join(rom.Regions, ", "))
+	case len(tags.Regions) > 0:
+		parts = append(parts, strings.Join(tags.Regions, ", "))
+	default:
+		parts = append(parts, rom.FsNameNoExt)
+	}
+
+	if revision := cmp.Or(rom.Revision, tags.Revision); revision != "" {
+		parts = append(parts, i18n.Localize(&goi18n.Message{ID: "game_details_revision", Other: "Rev {{.Revision}}"}, map[string]interface{}{"Revision": revision}))
+	}
+
+	if rom.FsSizeBytes > 0 {
+		parts = append(parts, stringutil.FormatBytes(int64(rom.FsSizeBytes)))
+	}
+
+	label := strings.Join(parts, " - ")
+	if rom.IsDownloaded(*config) {
+		label = fmt.Sprintf("%s %s", gabaconst.Download, label)
+	}
+
+	return label
+}
@@ -1,13 +1,16 @@
 package ui
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"grout/cache"
+	"grout/connectivity"
 	"grout/internal"
 	"grout/internal/constants"
 	"grout/internal/stringutil"
 	"grout/romm"
+	"os"
 	"slices"
 	"strings"
 	"sync"
@@ -35,6 +38,9 @@ type GameListInput struct {
 	Games                []romm.Rom
 	HasBIOS              bool
 	SearchFilter         string
+	SearchAll            string
+	Filters              cache.Filters
+	Sort                 cache.Sort
 	LastSelectedIndex    int
 	LastSelectedPosition int
 }
@@ -46,6 +52,9 @@ type GameListOutput struct {
 	SearchFilter         string
 	AllGames             []romm.Rom
 	HasBIOS              bool
+	FacetGameIDs         []int
+	NoFilterMatches      bool
+	Unreachable          bool
 	LastSelectedIndex    int
 	LastSelectedPosition int
 }
@@ -67,13 +76,17 @@ func (s *GameListScreen) Draw(input GameListInput) (ScreenResult[GameListOutput]
 	if len(games) == 0 {
 		loaded, err := s.loadGames(input)
 		if err != nil {
+			// Losing RomM is shown by the caller, which switches to browsing offline
+			if romm.IsUnreachable(err) {
+				return back(GameListOutput{Unreachable: true}), nil
+			}
 			s.showErrorMessage(err)
 			return back(GameListOutput{}), nil
 		}
 		games = loaded.games
 		hasBIOS = loaded.hasBIOS
 
-		if input.Config.ShowBoxArt {
+		if input.Config.ShowBoxArt && !connectivity.IsOffline() {
 			go cache.SyncArtworkInBackground(input.Host, games)
 		}
 	}
@@ -88,7 +101,23 @@ func (s *GameListScreen) Draw(input GameListInput) (ScreenResult[GameListOutput]
 		LastSelectedPosition: input.LastSelectedPosition,
 	}
 
+	// Results of a search across all platforms keep their order of relevance
+	var searchRank map[int]int
+	if input.SearchAll != "" {
+		searchRank = make(map[int]int, len(games))
+		for i, game := range games {
+			searchRank[game.ID] = i
+		}
+	}
+
 	displayGames := stringutil.PrepareRomNames(games)
+	if searchRank != nil {
+		sortByRank(displayGames, searchRank)
+	}
+
+	if input.Config.HidesRoms() {
+		displayGames = slices.DeleteFunc(slices.Clone(displayGames), input.Config.HidesRom)
+	}
 
 	if input.Config.DownloadedGames == "filter" {
 		filteredGames := make([]romm.Rom, 0, len(displayGames))
@@ -100,9 +129,36 @@ func (s *GameListScreen) Draw(input GameListInput) (ScreenResult[GameListOutput]
 		displayGames = filteredGames
 	}
 
+	// Facets are counted over the games the list would show without filters
+	output.FacetGameIDs = make([]int, len(displayGames))
+	for i, game := range displayGames {
+		output.FacetGameIDs[i] = game.ID
+	}
+
+	if input.Filters.Active() {
+		displayGames = filterGames(displayGames, input.Filters)
+		if len(displayGames) == 0 {
+			s.showNoFilterMatchesMessage()
+			output.NoFilterMatches = true
+			return withCode(output, constants.ExitCodeListOptions), nil
+		}
+	}
+
+	// Lists come in name order, search results in order of relevance, unless another order was picked
+	if input.Sort.Field != cache.SortDefault && (input.SearchAll != "" || !input.Sort.IsDefault()) {
+		displayGames = sortGames(displayGames, input.Sort, input.Config)
+	}
+
+	if input.Config.GroupVariants {
+		displayGames = groupVariants(displayGames, input.Config.RegionPreferences())
+	}
+
 	displayName := input.Platform.Name
 	allGamesFilteredOut := false
-	if isCollectionSet(input.Collection) {
+	if input.SearchAll != "" {
+		displayName = i18n.Localize(&goi18n.Message{ID: "games_list_search_prefix", Other: "[Search: \"{{.Query}}\"]"}, map[string]interface{}{"Query": input.SearchAll})
+		addPlatformBadges(displayGames, input.Config)
+	} else if isCollectionSet(input.Collection) {
 		displayName = input.Collection.Name
 		originalCount := len(displayGames)
 		filteredGames := make([]romm.Rom, 0, len(displayGames))
@@ -116,13 +172,7 @@ func (s *GameListScreen) Draw(input GameListInput) (ScreenResult[GameListOutput]
 		allGamesFilteredOut = originalCount > 0 && len(displayGames) == 0
 
 		if input.Platform.ID == 0 {
-			for i := range displayGames {
-				prefix := ""
-				if input.Config.DownloadedGames == "mark" && displayGames[i].IsDownloaded(*input.Config) {
-					prefix = gabaconst.Download + " "
-				}
-				displayGames[i].DisplayName = fmt.Sprintf("%s[%s] %s", prefix, displayGames[i].PlatformFSSlug, displayGames[i].DisplayName)
-			}
+			addPlatformBadges(displayGames, input.Config)
 		} else {
 			displayName = fmt.Sprintf("%s - %s", input.Collection.Name, input.Platform.Name)
 			if input.Config.DownloadedGames == "mark" {
@@ -144,10 +194,17 @@ func (s *GameListScreen) Draw(input GameListInput) (ScreenResult[GameListOutput]
 	}
 
 	title := displayName
+	if input.Sort.Field != cache.SortDefault && (input.SearchAll != "" || !input.Sort.IsDefault()) {
+		title = fmt.Sprintf("[%s] %s", sortLabel(input.Sort), title)
+	}
+	if input.Filters.Active() {
+		message := i18n.Localize(&goi18n.Message{ID: "games_list_filtered_prefix", Other: "[Filtered]"}, nil)
+		title = fmt.Sprintf("%s %s", message, title)
+	}
 	if input.SearchFilter != "" {
 		message := i18n.Localize(&goi18n.Message{ID: "games_list_search_prefix", Other: "[Search: \"{{.Query}}\"]"}, map[string]interface{}{"Query": input.SearchFilter})
-		title = fmt.Sprintf("%s %s", message, displayName)
-		displayGames = filterList(displayGames, input.SearchFilter)
+		title = fmt.Sprintf("%s %s", message, title)
+		displayGames = searchList(displayGames, input.SearchFilter, input.Platform.ID, input.Sort.IsDefault())
 	}
 
 	if len(displayGames) == 0 {
@@ -197,7 +254,7 @@ func (s *GameListScreen) Draw(input GameListInput) (ScreenResult[GameListOutput]
 	}
 
 	options.HelpTitle = i18n.Localize(&goi18n.Message{ID: "games_list_help_title", Other: "Games List Help"}, nil)
-	options.HelpText = strings.Split(i18n.Localize(&goi18n.Message{ID: "games_list_help_body", Other: "A - Select a game\nB - Go back to the previous screen\nX - Search for games by name\nSelect - Toggle multi-select mode\n  In multi-select mode:\n  - Use D-Pad to navigate\n  - Press A to toggle selection\n  - Press L1 to deselect all\n  - Press R1 to select all\n  - Press Start to confirm selections\nMenu - Show this help screen\nD-Pad - Navigate the game list"}, nil), "\n")
+	options.HelpText = strings.Split(i18n.Localize(&goi18n.Message{ID: "games_list_help_body", Other: "A - Select a game\nB - Go back to the previous screen\nX - Search, sort or filter the list\nY - Get BIOS files, on platforms that have them\nSelect - Toggle multi-select mode\n  In multi-select mode:\n  - Use D-Pad to navigate\n  - Press A to toggle selection\n  - Press L1 to deselect all\n  - Press R1 to select all\n  - Press Start to confirm selections\n  - Press X to edit their collections\nMenu - Show this help screen\nD-Pad - Navigate the game list"}, nil), "\n")
 	options.HelpExitText = i18n.Localize(&goi18n.Message{ID: "help_exit_text", Other: "Press any button to close help"}, nil)
 
 	footerItems := []gaba.FooterHelpItem{
@@ -208,7 +265,7 @@ func (s *GameListScreen) Draw(input GameListInput) (ScreenResult[GameListOutput]
 		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_bios", Other: "BIOS"}, nil)})
 	}
 
-	footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "button_search", Other: "Search"}, nil)})
+	footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "button_options", Other: "Options"}, nil)})
 
 	options.FooterHelpItems = footerItems
 
@@ -248,7 +305,25 @@ func (s *GameListScreen) Draw(input GameListInput) (ScreenResult[GameListOutput]
 		return success(output), nil
 
 	case gaba.ListActionTriggered:
-		return withCode(output, constants.ExitCodeSearch), nil
+		// X opens the list options, unless games were picked in multi-select mode, then it edits their collections
+		var selectedGames []romm.Rom
+		for _, idx := range res.Selected {
+			if res.Items[idx].Selected {
+				selectedGames = append(selectedGames, res.Items[idx].Metadata.(romm.Rom))
+			}
+		}
+		if len(selectedGames) > 0 && !internal.IsKidModeEnabled() {
+			slices.SortFunc(selectedGames, func(a, b romm.Rom) int {
+				return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+			})
+			output.SelectedGames = selectedGames
+			return withCode(output, constants.ExitCodeCollectionMembership), nil
+		}
+		if len(res.Selected) > 0 {
+			output.LastSelectedIndex = res.Selected[0]
+			output.LastSelectedPosition = res.VisiblePosition
+		}
+		return withCode(output, constants.ExitCodeListOptions), nil
 
 	case gaba.ListActionSecondaryTriggered:
 		return withCode(output, constants.ExitCodeBIOS), nil
@@ -315,6 +390,11 @@ func (s *GameListScreen) loadGames(input GameListInput) (loadGamesResult, error)
 		}
 	}
 
+	// Offline, only the cache has games
+	if connectivity.IsOffline() {
+		return loadGamesResult{}, connectivity.ErrOffline
+	}
+
 	// Cache miss or stale - show loading screen and fetch
 	var loadErr error
 
@@ -354,7 +434,7 @@ func (s *GameListScreen) loadGames(input GameListInput) (loadGamesResult, error)
 				// Check BIOS availability
 				if hasBIOS, wasFetched := cm.HasBIOS(platform.ID); wasFetched {
 					result.hasBIOS = hasBIOS
-				} else {
+				} else if host.Capabilities().Has(romm.CapabilityFirmware) {
 					wg.Add(1)
 					go func() {
 						defer wg.Done()
@@ -409,7 +489,7 @@ func (s *GameListScreen) loadGames(input GameListInput) (loadGamesResult, error)
 			}()
 
 			// Check BIOS availability (only for platforms, not collections)
-			if platform.ID != 0 && !isCollectionSet(collection) {
+			if platform.ID != 0 && !isCollectionSet(collection) && host.Capabilities().Has(romm.CapabilityFirmware) {
 				// First check cached BIOS info
 				if cm := cache.GetCacheManager(); cm != nil {
 					if hasBIOS, wasFetched := cm.HasBIOS(platform.ID); wasFetched {
@@ -490,11 +570,26 @@ func (s *GameListScreen) showFilteredOutMessage(collectionName string) {
 	)
 }
 
+func (s *GameListScreen) showNoFilterMatchesMessage() {
+	message := i18n.Localize(&goi18n.Message{ID: "games_list_no_filter_matches", Other: "No games match the filters"}, nil)
+
+	gaba.ProcessMessage(
+		message,
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			time.Sleep(time.Second * 1)
+			return nil, nil
+		},
+	)
+}
+
 func (s *GameListScreen) showErrorMessage(err error) {
 	var message string
 
 	classifiedErr := romm.ClassifyError(err)
-	if errors.Is(classifiedErr, romm.ErrTimeout) {
+	if errors.Is(err, connectivity.ErrOffline) {
+		message = i18n.Localize(&goi18n.Message{ID: "games_list_offline", Other: "These games aren't cached.\nThey can be loaded once RomM is back."}, nil)
+	} else if errors.Is(classifiedErr, romm.ErrTimeout) {
 		message = i18n.Localize(&goi18n.Message{ID: "games_list_load_timeout", Other: "Connection timed out!\nPlease check your network connection."}, nil)
 	} else {
 		message = i18n.Localize(&goi18n.Message{ID: "games_list_load_error", Other: "Failed to load games.\nPlease try again later."}, nil)
@@ -551,6 +646,168 @@ func fetchList(config *internal.Config, host romm.Host, queryID int, fetchType f
 	return nil, fmt.Errorf("unsupported fetch type")
 }
 
+// filterGames keeps the games matching the filters. Lists the cache can't filter are kept whole.
+func filterGames(games []romm.Rom, filters cache.Filters) []romm.Rom {
+	ids := make([]int, len(games))
+	for i, game := range games {
+		ids[i] = game.ID
+	}
+
+	matched, err := cache.GetCacheManager().FilterGameIDs(ids, filters)
+	if err != nil {
+		gaba.GetLogger().Warn("Unable to filter games", "error", err)
+		return games
+	}
+
+	keep := make(map[int]bool, len(matched))
+	for _, id := range matched {
+		keep[id] = true
+	}
+	return slices.DeleteFunc(slices.Clone(games), func(game romm.Rom) bool {
+		return !keep[game.ID]
+	})
+}
+
+// addPlatformBadges prefixes the names of games from several platforms with their platform.
+func addPlatformBadges(games []romm.Rom, config *internal.Config) {
+	for i := range games {
+		prefix := ""
+		if config.DownloadedGames == "mark" && games[i].IsDownloaded(*config) {
+			prefix = gabaconst.Download + " "
+		}
+		games[i].DisplayName = fmt.Sprintf("%s[%s] %s", prefix, games[i].PlatformFSSlug, games[i].DisplayName)
+	}
+}
+
+// searchList keeps the games matching filter, ranked by the cache's search index unless rank is
+// false, then they keep their order. Games the index doesn't know, like lists fetched while the
+// cache is unavailable, are matched by name.
+func searchList(games []romm.Rom, filter string, platformID int, rank bool) []romm.Rom {
+	cm := cache.GetCacheManager()
+	if cm == nil {
+		return filterList(games, filter)
+	}
+
+	ids, err := cm.SearchGameIDs(filter, platformID, 0)
+	if err != nil {
+		gaba.GetLogger().Warn("Search index unavailable, matching names", "error", err)
+		return filterList(games, filter)
+	}
+
+	ranks := make(map[int]int, len(ids))
+	for i, id := range ids {
+		ranks[id] = i
+	}
+
+	var result []romm.Rom
+	for _, game := range games {
+		if _, ok := ranks[game.ID]; ok {
+			result = append(result, game)
+		}
+	}
+	if len(result) == 0 {
+		return filterList(games, filter)
+	}
+
+	if rank {
+		sortByRank(result, ranks)
+	}
+	return result
+}
+
+// sortGames orders the games by sort. Cached games are sorted by the cache, other lists by the
+// games' own fields. Downloaded games are ordered by when their ROM file was written. Games
+// without a value to sort by come last, and games with the same value are ordered by name.
+func sortGames(games []romm.Rom, sort cache.Sort, config *internal.Config) []romm.Rom {
+	games = slices.Clone(games)
+
+	if sort.Field != cache.SortDownloaded {
+		ids := make([]int, len(games))
+		for i, game := range games {
+			ids[i] = game.ID
+		}
+
+		sorted, err := cache.GetCacheManager().SortGameIDs(ids, sort)
+		if err == nil {
+			ranks := make(map[int]int, len(sorted))
+			for i, id := range sorted {
+				ranks[id] = i
+			}
+			sortByRank(games, ranks)
+			return games
+		}
+		gaba.GetLogger().Debug("Sorting games outside the cache", "error", err)
+	}
+
+	values := make(map[int]int64, len(games))
+	for _, game := range games {
+		if value := gameSortValue(game, sort.Field, config); value != 0 {
+			values[game.ID] = value
+		}
+	}
+
+	slices.SortStableFunc(games, func(a, b romm.Rom) int {
+		x, hasX := values[a.ID]
+		y, hasY := values[b.ID]
+		if hasX != hasY {
+			if hasX {
+				return -1
+			}
+			return 1
+		}
+
+		if c := cmp.Compare(x, y); c != 0 {
+			if sort.Descending {
+				return -c
+			}
+			return c
+		}
+
+		c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		if sort.Descending && sort.Field == cache.SortName {
+			return -c
+		}
+		return c
+	})
+
+	return games
+}
+
+// gameSortValue returns the value a game is sorted by, 0 when it has none. Names are compared
+// on their own, so they have no value.
+func gameSortValue(game romm.Rom, field cache.SortField, config *internal.Config) int64 {
+	switch field {
+	case cache.SortReleaseDate:
+		return game.Metadatum.FirstReleaseDate
+	case cache.SortRating:
+		// Tenths keep the precision RomM shows ratings with
+		return int64(game.Metadatum.AverageRating * 10)
+	case cache.SortSize:
+		return int64(game.FsSizeBytes)
+	case cache.SortAdded:
+		if !game.CreatedAt.IsZero() {
+			return game.CreatedAt.Unix()
+		}
+	case cache.SortUpdated:
+		if !game.UpdatedAt.IsZero() {
+			return game.UpdatedAt.Unix()
+		}
+	case cache.SortDownloaded:
+		if path := game.GetLocalPath(*config); path != "" {
+			if info, err := os.Stat(path); err == nil {
+				return info.ModTime().Unix()
+			}
+		}
+	}
+	return 0
+}
+
+func sortByRank(games []romm.Rom, rank map[int]int) {
+	slices.SortStableFunc(games, func(a, b romm.Rom) int {
+		return rank[a.ID] - rank[b.ID]
+	})
+}
+
 func filterList(itemList []romm.Rom, filter string) []romm.Rom {
 	var result []romm.Rom
 
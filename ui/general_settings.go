@@ -3,6 +3,7 @@ package ui
 import (
 	"errors"
 	"grout/internal"
+	"grout/internal/constants"
 
 	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
 	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
@@ -55,6 +56,11 @@ func (s *GeneralSettingsScreen) Draw(input GeneralSettingsInput) (ScreenResult[G
 		return withCode(output, gaba.ExitCodeError), err
 	}
 
+	if result.Action == gaba.ListActionSelected &&
+		items[result.Selected].Item.Text == i18n.Localize(&goi18n.Message{ID: "settings_region_priority", Other: "Region Priority"}, nil) {
+		return withCode(output, constants.ExitCodeRegionPriority), nil
+	}
+
 	return success(output), nil
 }
 
@@ -77,6 +83,50 @@ func (s *GeneralSettingsScreen) buildMenuItems(config *internal.Config) []gaba.I
 			},
 			SelectedOption: downloadedGamesActionToIndex(config.DownloadedGames),
 		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_region_variants", Other: "Region Variants"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "region_variants_show_all", Other: "Show All"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "region_variants_group", Other: "1G1R"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(config.GroupVariants),
+		},
+		{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_region_priority", Other: "Region Priority"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_betas", Other: "Betas"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_show", Other: "Show"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_hide", Other: "Hide"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(config.HideBetas),
+		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_prototypes", Other: "Prototypes"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_show", Other: "Show"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_hide", Other: "Hide"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(config.HidePrototypes),
+		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_hacks", Other: "Hacks"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_show", Other: "Show"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_hide", Other: "Hide"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(config.HideHacks),
+		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_unlicensed", Other: "Unlicensed"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_show", Other: "Show"}, nil), Value: false},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "common_hide", Other: "Hide"}, nil), Value: true},
+			},
+			SelectedOption: boolToIndex(config.HideUnlicensed),
+		},
 		{
 			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_download_art", Other: "Download Art"}, nil)},
 			Options: []gaba.Option{
@@ -85,6 +135,15 @@ func (s *GeneralSettingsScreen) buildMenuItems(config *internal.Config) []gaba.I
 			},
 			SelectedOption: boolToIndex(!config.DownloadArt),
 		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_download_manuals", Other: "Download Manuals"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "download_manuals_off", Other: "Off"}, nil), Value: "off"},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "download_manuals_rom_folder", Other: "Next to ROM"}, nil), Value: "rom_folder"},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "download_manuals_manuals_folder", Other: "Manuals Folder"}, nil), Value: "manuals_folder"},
+			},
+			SelectedOption: downloadManualsToIndex(config.DownloadManuals),
+		},
 		{
 			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_compressed_downloads", Other: "Zipped Downloads"}, nil)},
 			Options: []gaba.Option{
@@ -125,11 +184,41 @@ func (s *GeneralSettingsScreen) applySettings(config *internal.Config, items []g
 				config.DownloadedGames = val
 			}
 
+		case i18n.Localize(&goi18n.Message{ID: "settings_region_variants", Other: "Region Variants"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.GroupVariants = val
+			}
+
+		case i18n.Localize(&goi18n.Message{ID: "settings_betas", Other: "Betas"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.HideBetas = val
+			}
+
+		case i18n.Localize(&goi18n.Message{ID: "settings_prototypes", Other: "Prototypes"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.HidePrototypes = val
+			}
+
+		case i18n.Localize(&goi18n.Message{ID: "settings_hacks", Other: "Hacks"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.HideHacks = val
+			}
+
+		case i18n.Localize(&goi18n.Message{ID: "settings_unlicensed", Other: "Unlicensed"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
+				config.HideUnlicensed = val
+			}
+
 		case i18n.Localize(&goi18n.Message{ID: "settings_download_art", Other: "Download Art"}, nil):
 			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
 				config.DownloadArt = val
 			}
 
+		case i18n.Localize(&goi18n.Message{ID: "settings_download_manuals", Other: "Download Manuals"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(string); ok {
+				config.DownloadManuals = val
+			}
+
 		case i18n.Localize(&goi18n.Message{ID: "settings_compressed_downloads", Other: "Zipped Downloads"}, nil):
 			if val, ok := item.Options[item.SelectedOption].Value.(bool); ok {
 				config.UnzipDownloads = val
@@ -155,3 +244,16 @@ func downloadedGamesActionToIndex(action string) int {
 		return 0
 	}
 }
+
+func downloadManualsToIndex(mode string) int {
+	switch mode {
+	case "off":
+		return 0
+	case "rom_folder":
+		return 1
+	case "manuals_folder":
+		return 2
+	default:
+		return 0
+	}
+}
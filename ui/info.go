@@ -2,6 +2,8 @@ package ui
 
 import (
 	"errors"
+	"fmt"
+	"grout/cache"
 	"grout/internal/constants"
 	"grout/internal/imageutil"
 	"grout/romm"
@@ -69,6 +71,12 @@ func (s *InfoScreen) buildSections(input InfoInput) []gaba.Section {
 		{Label: i18n.Localize(&goi18n.Message{ID: "info_commit", Other: "Commit"}, nil), Value: versionInfo.GitCommit},
 		{Label: i18n.Localize(&goi18n.Message{ID: "info_build_date", Other: "Build Date"}, nil), Value: versionInfo.BuildDate},
 	}
+	if cm := cache.GetCacheManager(); cm != nil {
+		versionMetadata = append(versionMetadata, gaba.MetadataItem{
+			Label: i18n.Localize(&goi18n.Message{ID: "info_cache_schema", Other: "Cache Schema"}, nil),
+			Value: fmt.Sprintf("v%d", cm.SchemaVersion()),
+		})
+	}
 	sections = append(sections, gaba.NewInfoSection("Grout", versionMetadata))
 
 	metadata := []gaba.MetadataItem{
@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/internal/constants"
+	"maps"
+	"slices"
+	"strconv"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	icons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type ListOptionsInput struct {
+	Config        *internal.Config
+	GameIDs       []int
+	Filters       cache.Filters
+	Sort          cache.Sort
+	SearchResults bool
+}
+
+type ListOptionsOutput struct {
+	Filters cache.Filters
+	Sort    cache.Sort
+}
+
+type ListOptionsScreen struct{}
+
+func NewListOptionsScreen() *ListOptionsScreen {
+	return &ListOptionsScreen{}
+}
+
+type listOption string
+
+const (
+	listOptionSearch       listOption = "search"
+	listOptionClearFilters listOption = "clear_filters"
+)
+
+// Draw shows the options of a game list: search, its order and a filter for each facet its games
+// have, with the values picked so far. Facets are counted from the games cache.
+func (s *ListOptionsScreen) Draw(input ListOptionsInput) (ScreenResult[ListOptionsOutput], error) {
+	logger := gaba.GetLogger()
+	output := ListOptionsOutput{Filters: input.Filters, Sort: input.Sort}
+	selectedIndex := 0
+
+	for {
+		facets, err := cache.GetCacheManager().GetFacets(input.GameIDs, output.Filters)
+		if err != nil {
+			logger.Warn("Unable to load game facets", "error", err)
+		}
+
+		items := s.buildMenuItems(input, &output, facets)
+
+		result, err := gaba.OptionsList(
+			i18n.Localize(&goi18n.Message{ID: "list_options_title", Other: "List Options"}, nil),
+			gaba.OptionListSettings{
+				FooterHelpItems:      []gaba.FooterHelpItem{FooterBack(), FooterCycle(), FooterSelect()},
+				StatusBar:            StatusBar(),
+				SmallTitle:           true,
+				InitialSelectedIndex: min(selectedIndex, len(items)-1),
+			},
+			items,
+		)
+
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				break
+			}
+			logger.Error("List options error", "error", err)
+			return withCode(output, gaba.ExitCodeError), err
+		}
+
+		if result.Action != gaba.ListActionSelected {
+			break
+		}
+		selectedIndex = result.Selected
+
+		switch action := items[result.Selected].Item.Metadata.(type) {
+		case cache.Facet:
+			if values, ok := pickFacetValues(action, facets[action], output.Filters[action]); ok {
+				output.Filters = output.Filters.With(action, values)
+			}
+		case listOption:
+			switch action {
+			case listOptionSearch:
+				if input.SearchResults {
+					return withCode(output, constants.ExitCodeSearchAll), nil
+				}
+				return withCode(output, constants.ExitCodeSearch), nil
+			case listOptionClearFilters:
+				output.Filters = nil
+				selectedIndex = 0
+			}
+		}
+	}
+
+	if output.Sort == input.Sort && filtersEqual(output.Filters, input.Filters) {
+		return back(output), nil
+	}
+	return success(output), nil
+}
+
+func (s *ListOptionsScreen) buildMenuItems(input ListOptionsInput, output *ListOptionsOutput, facets map[cache.Facet][]cache.FacetValue) []gaba.ItemWithOptions {
+	filters := output.Filters
+
+	fields := cache.SortFields
+	if input.SearchResults {
+		fields = append([]cache.SortField{cache.SortDefault}, fields...)
+	}
+
+	current := output.Sort.Field
+	if current == cache.SortDefault && !input.SearchResults {
+		current = cache.SortName
+	}
+
+	var sortOptions []gaba.Option
+	selectedSort := 0
+	for i, field := range fields {
+		if field == current {
+			selectedSort = i
+		}
+		sortOptions = append(sortOptions, gaba.Option{
+			DisplayName: sortFieldLabel(field),
+			Value:       field,
+			OnUpdate: func(value interface{}) {
+				output.Sort.Field = value.(cache.SortField)
+			},
+		})
+	}
+
+	setDescending := func(value interface{}) {
+		output.Sort.Descending = value.(bool)
+	}
+
+	items := []gaba.ItemWithOptions{
+		{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "list_options_search", Other: "Search"}, nil), Metadata: listOptionSearch},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		},
+		{
+			Item:           gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "list_options_sort", Other: "Sort By"}, nil)},
+			Options:        sortOptions,
+			SelectedOption: selectedSort,
+		},
+		{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "list_options_order", Other: "Order"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "sort_ascending", Other: "Ascending"}, nil), Value: false, OnUpdate: setDescending},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "sort_descending", Other: "Descending"}, nil), Value: true, OnUpdate: setDescending},
+			},
+			SelectedOption: boolToIndex(output.Sort.Descending),
+		},
+	}
+
+	for _, facet := range cache.Facets {
+		if len(facets[facet]) == 0 {
+			continue
+		}
+		items = append(items, gaba.ItemWithOptions{
+			Item: gaba.MenuItem{Text: facetLabel(facet), Metadata: facet},
+			Options: []gaba.Option{{
+				Type:        gaba.OptionTypeClickable,
+				DisplayName: facetSummary(facet, filters[facet]),
+			}},
+		})
+	}
+
+	if filters.Active() {
+		items = append(items, gaba.ItemWithOptions{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "list_options_clear_filters", Other: "Clear Filters"}, nil), Metadata: listOptionClearFilters},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		})
+	}
+
+	return items
+}
+
+// pickFacetValues lets the user pick any number of the facet's values, each shown with how many
+// games have it. It reports false when the user backs out without changing anything.
+func pickFacetValues(facet cache.Facet, values []cache.FacetValue, picked []string) ([]string, bool) {
+	menuItems := make([]gaba.MenuItem, len(values))
+	for i, value := range values {
+		menuItems[i] = gaba.MenuItem{
+			Text:     fmt.Sprintf("%s (%d)", facetValueLabel(facet, value.Value), value.Count),
+			Selected: slices.Contains(picked, value.Value),
+			Metadata: value.Value,
+		}
+	}
+
+	options := gaba.DefaultListOptions(facetLabel(facet), menuItems)
+	options.SmallTitle = true
+	options.StartInMultiSelectMode = true
+	options.SecondaryActionButton = icons.VirtualButtonY
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		FooterBack(),
+		{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_clear", Other: "Clear"}, nil)},
+		FooterSelect(),
+		{ButtonName: icons.Start, HelpText: i18n.Localize(&goi18n.Message{ID: "button_apply", Other: "Apply"}, nil), IsConfirmButton: true},
+	}
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		return nil, false
+	}
+
+	switch sel.Action {
+	case gaba.ListActionSecondaryTriggered:
+		return nil, true
+	case gaba.ListActionSelected:
+		indices := slices.Sorted(slices.Values(sel.Selected))
+		result := make([]string, len(indices))
+		for i, idx := range indices {
+			result[i] = sel.Items[idx].Metadata.(string)
+		}
+		return result, true
+	}
+
+	return nil, false
+}
+
+func sortFieldLabel(field cache.SortField) string {
+	switch field {
+	case cache.SortDefault:
+		return i18n.Localize(&goi18n.Message{ID: "sort_relevance", Other: "Relevance"}, nil)
+	case cache.SortName:
+		return i18n.Localize(&goi18n.Message{ID: "sort_name", Other: "Name"}, nil)
+	case cache.SortReleaseDate:
+		return i18n.Localize(&goi18n.Message{ID: "sort_release_date", Other: "Release Date"}, nil)
+	case cache.SortRating:
+		return i18n.Localize(&goi18n.Message{ID: "sort_rating", Other: "Rating"}, nil)
+	case cache.SortSize:
+		return i18n.Localize(&goi18n.Message{ID: "sort_size", Other: "File Size"}, nil)
+	case cache.SortAdded:
+		return i18n.Localize(&goi18n.Message{ID: "sort_added", Other: "Date Added"}, nil)
+	case cache.SortUpdated:
+		return i18n.Localize(&goi18n.Message{ID: "sort_updated", Other: "Last Updated"}, nil)
+	case cache.SortDownloaded:
+		return i18n.Localize(&goi18n.Message{ID: "sort_downloaded", Other: "Last Downloaded"}, nil)
+	}
+	return string(field)
+}
+
+// sortLabel names the order of a list for its title, like "Rating ↓".
+func sortLabel(sort cache.Sort) string {
+	if sort.Descending {
+		return sortFieldLabel(sort.Field) + " ↓"
+	}
+	return sortFieldLabel(sort.Field) + " ↑"
+}
+
+func facetLabel(facet cache.Facet) string {
+	switch facet {
+	case cache.FacetGenre:
+		return i18n.Localize(&goi18n.Message{ID: "list_options_genre", Other: "Genre"}, nil)
+	case cache.FacetYear:
+		return i18n.Localize(&goi18n.Message{ID: "list_options_year", Other: "Release Year"}, nil)
+	case cache.FacetRating:
+		return i18n.Localize(&goi18n.Message{ID: "list_options_rating", Other: "Rating"}, nil)
+	case cache.FacetPlayers:
+		return i18n.Localize(&goi18n.Message{ID: "list_options_players", Other: "Players"}, nil)
+	case cache.FacetLanguage:
+		return i18n.Localize(&goi18n.Message{ID: "list_options_language", Other: "Language"}, nil)
+	}
+	return string(facet)
+}
+
+// facetValueLabel names a facet value. Ratings are stored as the lowest rating of their range.
+func facetValueLabel(facet cache.Facet, value string) string {
+	if facet != cache.FacetRating {
+		return value
+	}
+
+	low, _ := strconv.Atoi(value)
+	if low >= 90 {
+		return fmt.Sprintf("%d-100", low)
+	}
+	return fmt.Sprintf("%d-%d", low, low+9)
+}
+
+func facetSummary(facet cache.Facet, picked []string) string {
+	switch len(picked) {
+	case 0:
+		return i18n.Localize(&goi18n.Message{ID: "list_options_any", Other: "Any"}, nil)
+	case 1:
+		return facetValueLabel(facet, picked[0])
+	}
+	return i18n.Localize(&goi18n.Message{ID: "list_options_selected_count", Other: "{{.Count}} Selected"}, map[string]interface{}{"Count": len(picked)})
+}
+
+func filtersEqual(a, b cache.Filters) bool {
+	return maps.EqualFunc(a, b, slices.Equal)
+}
@@ -20,6 +20,8 @@ import (
 
 type loginInput struct {
 	ExistingHost romm.Host
+	// Cancellable logins back out to the caller instead of quitting Grout
+	Cancellable bool
 }
 
 type loginOutput struct {
@@ -31,6 +33,11 @@ type loginAttemptResult struct {
 	ErrorType string
 	ErrorMsg  *goi18n.Message
 	Success   bool
+	Token     *romm.Token
+	// ServerVersion is empty when the heartbeat didn't report one
+	ServerVersion string
+	// Certificate is set when the server's certificate is untrusted or doesn't match the pin
+	Certificate *romm.CertificateError
 }
 
 type LoginScreen struct{}
@@ -135,6 +142,25 @@ func (s *LoginScreen) draw(input loginInput) (ScreenResult[loginOutput], error)
 				},
 			},
 		},
+		{
+			Item: gabagool.MenuItem{
+				Text: i18n.Localize(&goi18n.Message{ID: "login_ca_bundle", Other: "CA Bundle (optional)"}, nil),
+			},
+			Options: []gabagool.Option{
+				{
+					Type:           gabagool.OptionTypeKeyboard,
+					KeyboardLayout: gabagool.KeyboardLayoutURL,
+					DisplayName:    host.CABundle,
+					KeyboardPrompt: host.CABundle,
+					Value:          host.CABundle,
+				},
+			},
+		},
+	}
+
+	backItem := gabagool.FooterHelpItem{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_quit", Other: "Quit"}, nil)}
+	if input.Cancellable {
+		backItem = FooterCancel()
 	}
 
 	res, err := gabagool.OptionsList(
@@ -142,7 +168,7 @@ func (s *LoginScreen) draw(input loginInput) (ScreenResult[loginOutput], error)
 		gabagool.OptionListSettings{
 			DisableBackButton: false,
 			FooterHelpItems: []gabagool.FooterHelpItem{
-				{ButtonName: "B", HelpText: i18n.Localize(&goi18n.Message{ID: "button_quit", Other: "Quit"}, nil)},
+				backItem,
 				{ButtonName: icons.LeftRight, HelpText: i18n.Localize(&goi18n.Message{ID: "button_cycle", Other: "Cycle"}, nil)},
 				{ButtonName: icons.Start, HelpText: i18n.Localize(&goi18n.Message{ID: "button_login", Other: "Login"}, nil)},
 			},
@@ -166,25 +192,53 @@ func (s *LoginScreen) draw(input loginInput) (ScreenResult[loginOutput], error)
 		}(loginSettings[2].Value().(string)),
 		Username: loginSettings[3].Options[0].Value.(string),
 		Password: loginSettings[4].Options[0].Value.(string),
+		CABundle: strings.TrimSpace(loginSettings[5].Options[0].Value.(string)),
+	}
+
+	// A pin and custom headers belong to the server they were set up for
+	if newHost.URL() == host.URL() {
+		newHost.PinnedCertSHA256 = host.PinnedCertSHA256
+		newHost.Headers = host.Headers
 	}
 
 	return success(loginOutput{Host: newHost}), nil
 }
 
 func LoginFlow(existingHost romm.Host) (*internal.Config, error) {
+	host, err := loginFlow(loginInput{ExistingHost: existingHost})
+	if err != nil {
+		return nil, err
+	}
+
+	return &internal.Config{
+		Hosts: []romm.Host{host},
+	}, nil
+}
+
+// AddHostFlow logs in to another RomM server. Backing out returns gabagool.ErrCancelled
+// rather than quitting, since Grout is already connected to a server.
+func AddHostFlow() (romm.Host, error) {
+	return loginFlow(loginInput{Cancellable: true})
+}
+
+func loginFlow(input loginInput) (romm.Host, error) {
 	screen := newLoginScreen()
+	existingHost := input.ExistingHost
 
 	for {
-		result, err := screen.draw(loginInput{ExistingHost: existingHost})
+		result, err := screen.draw(loginInput{ExistingHost: existingHost, Cancellable: input.Cancellable})
 		if err != nil {
 			gabagool.ProcessMessage(i18n.Localize(&goi18n.Message{ID: "login_error_unexpected", Other: "Something unexpected happened!\nCheck the logs for more info."}, nil), gabagool.ProcessMessageOptions{}, func() (interface{}, error) {
 				time.Sleep(3 * time.Second)
 				return nil, nil
 			})
-			return nil, fmt.Errorf("unable to get login information: %w", err)
+			return romm.Host{}, fmt.Errorf("unable to get login information: %w", err)
 		}
 
 		if result.ExitCode == gabagool.ExitCodeBack || result.ExitCode == gabagool.ExitCodeCancel {
+			if input.Cancellable {
+				return romm.Host{}, gabagool.ErrCancelled
+			}
 			os.Exit(1)
 		}
 
@@ -192,11 +246,18 @@ func LoginFlow(existingHost romm.Host) (*internal.Config, error) {
 
 		loginResult := attemptLogin(host)
 
+		// Trust on first use, the certificate is pinned once the user accepts its fingerprint
+		for loginResult.Certificate != nil && confirmCertificate(host, loginResult.Certificate) {
+			host.PinnedCertSHA256 = loginResult.Certificate.Fingerprint
+			loginResult = attemptLogin(host)
+		}
+
 		if loginResult.Success {
-			config := &internal.Config{
-				Hosts: []romm.Host{host},
-			}
-			return config, nil
+			// Only the token pair is persisted, never the password
+			host.Token = loginResult.Token
+			host.Password = ""
+			host.ServerVersion = loginResult.ServerVersion
+			return host, nil
 		}
 
 		gabagool.ConfirmationMessage(
@@ -223,23 +284,81 @@ func attemptLogin(host romm.Host) loginAttemptResult {
 			}
 
 			loginClient := romm.NewClientFromHost(host, constants.LoginTimeout)
-			err = loginClient.Login(host.Username, host.Password)
+			token, err := loginClient.Login(host.Username, host.Password)
 			if err != nil {
 				return classifyLoginError(err), nil
 			}
 
-			return loginAttemptResult{Success: true}, nil
+			result := loginAttemptResult{Success: true, Token: token}
+			if heartbeat, err := validationClient.GetHeartbeat(); err == nil {
+				result.ServerVersion = heartbeat.Version()
+			} else {
+				gabagool.GetLogger().Debug("Unable to read RomM version", "error", err)
+			}
+
+			return result, nil
 		},
 	)
 
 	return result.(loginAttemptResult)
 }
 
+// confirmCertificate shows the fingerprint of a certificate Grout doesn't trust and asks the user
+// whether to pin it.
+func confirmCertificate(host romm.Host, certErr *romm.CertificateError) bool {
+	if certErr.Fingerprint == "" {
+		return false
+	}
+
+	message := &goi18n.Message{ID: "login_certificate_untrusted", Other: "{{.Host}} uses a certificate\nthat isn't trusted.\n\nSHA-256 fingerprint:\n{{.Fingerprint}}\n\nOnly trust it if it matches your server."}
+	if errors.Is(certErr, romm.ErrCertificateMismatch) {
+		message = &goi18n.Message{ID: "login_certificate_changed", Other: "The certificate of {{.Host}}\nhas changed!\n\nNew SHA-256 fingerprint:\n{{.Fingerprint}}\n\nOnly trust it if you replaced the certificate."}
+	}
+
+	_, err := gabagool.ConfirmationMessage(
+		i18n.Localize(message, map[string]interface{}{
+			"Host":        removeScheme(host.URL()),
+			"Fingerprint": fingerprintLines(certErr.Fingerprint),
+		}),
+		[]gabagool.FooterHelpItem{
+			FooterCancel(),
+			{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_trust", Other: "Trust"}, nil)},
+		},
+		gabagool.MessageOptions{
+			ConfirmButton: icons.VirtualButtonY,
+		},
+	)
+	if err != nil {
+		return false
+	}
+
+	gabagool.GetLogger().Info("Pinned server certificate", "host", host.URL(), "fingerprint", certErr.Fingerprint)
+	return true
+}
+
+// fingerprintLines splits a formatted SHA-256 fingerprint over two lines to fit the screen.
+func fingerprintLines(fingerprint string) string {
+	formatted := romm.FormatFingerprint(fingerprint)
+	half := len(formatted) / 2
+	if half == 0 || formatted[half] != ':' {
+		return formatted
+	}
+	return formatted[:half] + "\n" + formatted[half+1:]
+}
+
 func classifyLoginError(err error) loginAttemptResult {
 	if err == nil {
 		return loginAttemptResult{Success: true}
 	}
 
+	if certErr, ok := romm.AsCertificateError(err); ok {
+		return loginAttemptResult{
+			ErrorType:   "certificate",
+			ErrorMsg:    &goi18n.Message{ID: "login_error_certificate", Other: "The server's certificate is not trusted!\nAdd its CA bundle or trust the certificate."},
+			Certificate: certErr,
+		}
+	}
+
 	var protocolErr *romm.ProtocolError
 	if errors.As(err, &protocolErr) {
 		if protocolErr.CorrectProtocol == "https" {
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type ManageCollectionsOutput struct{}
+
+type ManageCollectionsScreen struct{}
+
+func NewManageCollectionsScreen() *ManageCollectionsScreen {
+	return &ManageCollectionsScreen{}
+}
+
+// Execute lists the user's regular collections for creating, renaming and deleting them,
+// until the user backs out.
+func (s *ManageCollectionsScreen) Execute(config internal.Config, host romm.Host) ManageCollectionsOutput {
+	if !RequireOnline() {
+		return ManageCollectionsOutput{}
+	}
+
+	logger := gaba.GetLogger()
+	client := romm.NewClientFromHost(host, config.ApiTimeout)
+	selectedIndex := 0
+
+	for {
+		collections, err := fetchRegularCollections(client)
+		if err != nil {
+			logger.Error("Failed to fetch collections", "error", err)
+			gaba.ConfirmationMessage(
+				fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "collection_membership_load_failed", Other: "Failed to load collections: %v"}, nil), err),
+				ContinueFooter(),
+				gaba.MessageOptions{},
+			)
+			return ManageCollectionsOutput{}
+		}
+
+		menuItems := []gaba.MenuItem{{
+			Text: i18n.Localize(&goi18n.Message{ID: "collection_new", Other: "New Collection"}, nil),
+		}}
+		for _, collection := range collections {
+			menuItems = append(menuItems, gaba.MenuItem{
+				Text:     fmt.Sprintf("%s (%d)", collection.Name, len(collection.ROMIDs)),
+				Metadata: collection,
+			})
+		}
+
+		options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "manage_collections_title", Other: "Manage Collections"}, nil), menuItems)
+		options.SmallTitle = true
+		options.FooterHelpItems = BackSelectFooter()
+		options.SelectedIndex = min(selectedIndex, len(menuItems)-1)
+		options.StatusBar = StatusBar()
+
+		sel, err := gaba.List(options)
+		if err != nil {
+			if !errors.Is(err, gaba.ErrCancelled) {
+				logger.Error("Manage collections list error", "error", err)
+			}
+			return ManageCollectionsOutput{}
+		}
+		if sel.Action != gaba.ListActionSelected || len(sel.Selected) == 0 {
+			return ManageCollectionsOutput{}
+		}
+
+		selectedIndex = sel.Selected[0]
+		collection, ok := sel.Items[selectedIndex].Metadata.(romm.Collection)
+		if !ok {
+			if name, ok := promptCollectionName(""); ok {
+				s.save(client, romm.Collection{Name: name})
+			}
+			continue
+		}
+
+		s.editCollection(client, collection)
+	}
+}
+
+func (s *ManageCollectionsScreen) editCollection(client *romm.Client, collection romm.Collection) {
+	renameText := i18n.Localize(&goi18n.Message{ID: "manage_collections_rename", Other: "Rename"}, nil)
+	deleteText := i18n.Localize(&goi18n.Message{ID: "manage_collections_delete", Other: "Delete"}, nil)
+
+	options := gaba.DefaultListOptions(collection.Name, []gaba.MenuItem{
+		{Text: renameText},
+		{Text: deleteText},
+	})
+	options.SmallTitle = true
+	options.FooterHelpItems = BackSelectFooter()
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+	if err != nil || sel.Action != gaba.ListActionSelected || len(sel.Selected) == 0 {
+		return
+	}
+
+	switch sel.Items[sel.Selected[0]].Text {
+	case renameText:
+		if name, ok := promptCollectionName(collection.Name); ok && name != collection.Name {
+			collection.Name = name
+			s.save(client, collection)
+		}
+
+	case deleteText:
+		s.delete(client, collection)
+	}
+}
+
+func (s *ManageCollectionsScreen) save(client *romm.Client, collection romm.Collection) {
+	_, err := gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "collection_membership_saving", Other: "Saving collections..."}, nil),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			_, err := saveCollection(client, collection)
+			return nil, err
+		},
+	)
+	if err != nil {
+		gaba.GetLogger().Error("Failed to save collection", "collection", collection.Name, "error", err)
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "manage_collections_save_failed", Other: "Failed to save {{.Name}}: {{.Error}}"}, map[string]interface{}{"Name": collection.Name, "Error": err.Error()}),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+	}
+}
+
+func (s *ManageCollectionsScreen) delete(client *romm.Client, collection romm.Collection) {
+	logger := gaba.GetLogger()
+
+	_, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "manage_collections_delete_confirm", Other: "Delete {{.Name}}?\nThe games stay in your library."}, map[string]interface{}{"Name": collection.Name}),
+		[]gaba.FooterHelpItem{
+			FooterCancel(),
+			{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "manage_collections_delete", Other: "Delete"}, nil)},
+		},
+		gaba.MessageOptions{
+			ConfirmButton: buttons.VirtualButtonY,
+		},
+	)
+	if err != nil {
+		return
+	}
+
+	_, err = gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "manage_collections_deleting", Other: "Deleting {{.Name}}..."}, map[string]interface{}{"Name": collection.Name}),
+		gaba.ProcessMessageOptions{ShowThemeBackground: true},
+		func() (interface{}, error) {
+			if err := client.DeleteCollection(collection.ID); err != nil && !errors.Is(err, romm.ErrNotFound) {
+				return nil, err
+			}
+			if cm := cache.GetCacheManager(); cm != nil {
+				if err := cm.DeleteCollection(collection); err != nil {
+					logger.Warn("Failed to remove collection from cache", "collection", collection.Name, "error", err)
+				}
+			}
+			return nil, nil
+		},
+	)
+	if err != nil {
+		logger.Error("Failed to delete collection", "collection", collection.Name, "error", err)
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "manage_collections_delete_failed", Other: "Failed to delete {{.Name}}: {{.Error}}"}, map[string]interface{}{"Name": collection.Name, "Error": err.Error()}),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"grout/connectivity"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// ShowOfflineNotice tells the user Grout couldn't reach RomM and is browsing its cache instead.
+func ShowOfflineNotice() {
+	gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "offline_notice", Other: "Unable to reach RomM!\nBrowsing the games cache offline until it's back."}, nil),
+		ContinueFooter(),
+		gaba.MessageOptions{},
+	)
+}
+
+// RequireOnline tells the user an action needs RomM while Grout is offline. It reports whether
+// the action can go ahead.
+func RequireOnline() bool {
+	if !connectivity.IsOffline() {
+		return true
+	}
+
+	gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "offline_unavailable", Other: "Not available offline!\nThis needs RomM, which can't be reached right now."}, nil),
+		ContinueFooter(),
+		gaba.MessageOptions{},
+	)
+	return false
+}
+
+// ShowDownloadsQueued tells the user the games picked while offline will download once RomM is back.
+func ShowDownloadsQueued(count int) {
+	gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "offline_downloads_queued", Other: "RomM can't be reached right now.\n{{.Count}} game(s) will download once it's back."}, map[string]interface{}{"Count": count}),
+		ContinueFooter(),
+		gaba.MessageOptions{},
+	)
+}
+
+// ConfirmQueuedDownloads asks whether to download the games queued while offline now that RomM
+// is back. It reports false when the user would rather wait.
+func ConfirmQueuedDownloads(count int) bool {
+	_, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "offline_downloads_resume", Other: "RomM is back!\nDownload the {{.Count}} game(s) queued while offline?"}, map[string]interface{}{"Count": count}),
+		[]gaba.FooterHelpItem{
+			FooterLater(),
+			FooterDownload(),
+		},
+		gaba.MessageOptions{},
+	)
+	return err == nil
+}
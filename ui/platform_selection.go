@@ -17,6 +17,7 @@ type PlatformSelectionInput struct {
 	Platforms            []romm.Platform
 	QuitOnBack           bool
 	ShowCollections      bool
+	ShowSearch           bool
 	ShowSaveSync         *atomic.Bool // nil = hidden, otherwise controls visibility dynamically
 	LastSelectedIndex    int
 	LastSelectedPosition int
@@ -57,6 +58,16 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (ScreenResu
 		})
 	}
 
+	if input.ShowSearch {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:           i18n.Localize(&goi18n.Message{ID: "platform_selection_search_all", Other: "Search All"}, nil),
+			Selected:       false,
+			Focused:        false,
+			Metadata:       romm.Platform{FSSlug: "search"},
+			NotReorderable: true,
+		})
+	}
+
 	for _, platform := range input.Platforms {
 		menuItems = append(menuItems, gaba.MenuItem{
 			Text:     platform.Name,
@@ -116,7 +127,10 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (ScreenResu
 	platformsReordered := false
 	startIndex := 0
 	if input.ShowCollections {
-		startIndex = 1
+		startIndex++
+	}
+	if input.ShowSearch {
+		startIndex++
 	}
 
 	if sel != nil && len(sel.Items) > 0 {
@@ -160,6 +174,10 @@ func (s *PlatformSelectionScreen) Draw(input PlatformSelectionInput) (ScreenResu
 			return withCode(output, constants.ExitCodeCollections), nil
 		}
 
+		if platform.FSSlug == "search" {
+			return withCode(output, constants.ExitCodeSearchAll), nil
+		}
+
 		return success(output), nil
 
 	case gaba.ListActionTriggered:
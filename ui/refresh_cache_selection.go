@@ -40,6 +40,9 @@ type refreshCacheItem struct {
 
 func (s *RefreshCacheScreen) Draw() (ScreenResult[RefreshCacheOutput], error) {
 	output := RefreshCacheOutput{}
+	if !RequireOnline() {
+		return back(output), nil
+	}
 
 	cm := cache.GetCacheManager()
 
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"errors"
+	"grout/internal"
+	"slices"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type RegionPriorityInput struct {
+	Config *internal.Config
+}
+
+type RegionPriorityOutput struct {
+	Config *internal.Config
+}
+
+type RegionPriorityScreen struct{}
+
+func NewRegionPriorityScreen() *RegionPriorityScreen {
+	return &RegionPriorityScreen{}
+}
+
+// Draw lists the regions and languages in the order variants are preferred. The list is
+// reordered with Select and saved when the user backs out, Y restores the default order.
+func (s *RegionPriorityScreen) Draw(input RegionPriorityInput) (ScreenResult[RegionPriorityOutput], error) {
+	config := input.Config
+	output := RegionPriorityOutput{Config: config}
+	priority := regionPriorityEntries(config.RegionPreferences())
+
+	for {
+		menuItems := make([]gaba.MenuItem, len(priority))
+		for i, entry := range priority {
+			menuItems[i] = gaba.MenuItem{Text: entry, Metadata: entry}
+		}
+
+		options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "region_priority_title", Other: "Region Priority"}, nil), menuItems)
+		options.SmallTitle = true
+		options.ReorderButton = buttons.VirtualButtonSelect
+		options.SecondaryActionButton = buttons.VirtualButtonY
+		options.FooterHelpItems = []gaba.FooterHelpItem{
+			FooterBack(),
+			{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_reset", Other: "Reset"}, nil)},
+			{ButtonName: "Select", HelpText: i18n.Localize(&goi18n.Message{ID: "button_reorder", Other: "Reorder"}, nil)},
+		}
+		options.StatusBar = StatusBar()
+
+		sel, err := gaba.List(options)
+
+		// The list comes back reordered even when the user backs out
+		if sel != nil && len(sel.Items) == len(priority) {
+			for i, item := range sel.Items {
+				priority[i] = item.Metadata.(string)
+			}
+		}
+
+		if err == nil && sel.Action == gaba.ListActionSecondaryTriggered {
+			priority = slices.Clone(internal.DefaultRegionPriority)
+			continue
+		}
+
+		if err != nil && !errors.Is(err, gaba.ErrCancelled) {
+			gaba.GetLogger().Error("Region priority list error", "error", err)
+			return withCode(output, gaba.ExitCodeError), err
+		}
+
+		break
+	}
+
+	if slices.Equal(priority, config.RegionPreferences()) {
+		return back(output), nil
+	}
+
+	// Keep following the default order, including changes to it, until the user picks their own
+	if slices.Equal(priority, internal.DefaultRegionPriority) {
+		config.RegionPriority = nil
+	} else {
+		config.RegionPriority = priority
+	}
+
+	if err := internal.SaveConfig(config); err != nil {
+		gaba.GetLogger().Error("Error saving region priority", "error", err)
+		return withCode(output, gaba.ExitCodeError), err
+	}
+
+	return success(output), nil
+}
+
+// regionPriorityEntries returns the priority followed by any default regions and languages it
+// leaves out, so every one of them can be placed.
+func regionPriorityEntries(priority []string) []string {
+	entries := slices.Clone(priority)
+	for _, entry := range internal.DefaultRegionPriority {
+		if !slices.Contains(entries, entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"grout/internal"
 	"grout/romm"
 	"grout/sync"
@@ -27,6 +29,9 @@ func NewSaveSyncScreen() *SaveSyncScreen {
 
 func (s *SaveSyncScreen) Draw(input SaveSyncInput) (ScreenResult[SaveSyncOutput], error) {
 	output := SaveSyncOutput{}
+	if !RequireOnline() {
+		return back(output), nil
+	}
 
 	// Scan local ROMs and match with save files
 	romScan, _ := gaba.ProcessMessage(i18n.Localize(&goi18n.Message{ID: "save_sync_scanning_roms", Other: "Scanning ROMs..."}, nil), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
@@ -39,14 +44,17 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (ScreenResult[SaveSyncOutput]
 	}
 
 	// Then, find save syncs using the pre-scanned ROM data
-	scanData, _ := gaba.ProcessMessage(i18n.Localize(&goi18n.Message{ID: "save_sync_scanning", Other: "Scanning save files..."}, nil), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+	scanData, scanErr := gaba.ProcessMessage(i18n.Localize(&goi18n.Message{ID: "save_sync_scanning", Other: "Scanning save files..."}, nil), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
 		localRoms, ok := romScan.(sync.LocalRomScan)
 		if !ok {
 			gaba.GetLogger().Error("Unable to scan ROMs!")
 			return nil, nil
 		}
 
-		syncs, unmatched, err := sync.FindSaveSyncsFromScan(input.Host, input.Config, localRoms)
+		syncs, unmatched, err := sync.FindSaveSyncsFromScan(context.Background(), input.Host, input.Config, localRoms)
+		if errors.Is(err, romm.ErrUnsupported) {
+			return nil, err
+		}
 		if err != nil {
 			gaba.GetLogger().Error("Unable to scan save files!", "error", err)
 			return nil, nil
@@ -55,6 +63,17 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (ScreenResult[SaveSyncOutput]
 		return scanResult{Syncs: syncs, Unmatched: unmatched}, nil
 	})
 
+	var unsupportedErr *romm.UnsupportedError
+	if errors.As(scanErr, &unsupportedErr) {
+		gaba.GetLogger().Warn("Save sync is not supported by this server", "error", unsupportedErr)
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "save_sync_unsupported", Other: "Save sync requires RomM {{.Version}} or newer!\nPlease update your RomM server."}, map[string]interface{}{"Version": unsupportedErr.Minimum.String()}),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return back(output), nil
+	}
+
 	var results []sync.SyncResult
 	var unmatched []sync.UnmatchedSave
 
@@ -75,7 +94,7 @@ func (s *SaveSyncScreen) Draw(input SaveSyncInput) (ScreenResult[SaveSyncOutput]
 					total := len(scan.Syncs)
 					for i := range scan.Syncs {
 						s := &scan.Syncs[i]
-						result := s.Execute(input.Host, input.Config)
+						result := s.Execute(context.Background(), input.Host, input.Config)
 						results = append(results, result)
 						if !result.Success {
 							gaba.GetLogger().Error("Unable to sync save!", "game", s.GameBase, "error", result.Error)
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"errors"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+	"slices"
+	"time"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// searchAllLimit caps the results of a search across all platforms.
+const searchAllLimit = 500
+
+type SearchAllInput struct {
+	Config      *internal.Config
+	InitialText string
+}
+
+type SearchAllOutput struct {
+	Query string
+	Games []romm.Rom
+}
+
+type SearchAllScreen struct{}
+
+func NewSearchAllScreen() *SearchAllScreen {
+	return &SearchAllScreen{}
+}
+
+// Draw asks for a query and searches every cached platform for it, asking again when nothing
+// matches. It runs on the cache alone, so it works without a connection to RomM.
+func (s *SearchAllScreen) Draw(input SearchAllInput) (ScreenResult[SearchAllOutput], error) {
+	logger := gaba.GetLogger()
+	text := input.InitialText
+
+	for {
+		res, err := gaba.Keyboard(text, i18n.Localize(&goi18n.Message{ID: "help_exit_text", Other: "Press any button to close help"}, nil))
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				return back(SearchAllOutput{}), nil
+			}
+			logger.Error("Error with keyboard", "error", err)
+			return withCode(SearchAllOutput{}, gaba.ExitCodeError), err
+		}
+		text = res.Text
+
+		games, err := cache.GetCacheManager().SearchGames(text, 0, searchAllLimit)
+		if err != nil {
+			logger.Error("Search across platforms failed", "query", text, "error", err)
+		}
+
+		// Only games of mapped platforms can be downloaded
+		games = slices.DeleteFunc(games, func(game romm.Rom) bool {
+			_, mapped := input.Config.DirectoryMappings[game.PlatformFSSlug]
+			return !mapped
+		})
+
+		if len(games) > 0 {
+			return success(SearchAllOutput{Query: text, Games: games}), nil
+		}
+
+		gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "games_list_no_results", Other: "No results found for \"{{.Query}}\""}, map[string]interface{}{"Query": text}),
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (interface{}, error) {
+				time.Sleep(time.Second * 1)
+				return nil, nil
+			},
+		)
+	}
+}
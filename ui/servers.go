@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/romm"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	buttons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type ServersInput struct {
+	Config *internal.Config
+}
+
+type ServersOutput struct {
+	// SelectedHost is the server to switch to, set when the exit code is success
+	SelectedHost romm.Host
+}
+
+type ServersScreen struct{}
+
+func NewServersScreen() *ServersScreen {
+	return &ServersScreen{}
+}
+
+// Draw lists the configured RomM servers. Servers can be added and removed in place, picking
+// one other than the active server returns it to switch to.
+func (s *ServersScreen) Draw(input ServersInput) (ScreenResult[ServersOutput], error) {
+	config := input.Config
+	output := ServersOutput{}
+	logger := gaba.GetLogger()
+
+	for {
+		active := config.ActiveHostIndex()
+
+		menuItems := make([]gaba.MenuItem, len(config.Hosts))
+		for i, host := range config.Hosts {
+			text := host.Label()
+			if i == active {
+				text = fmt.Sprintf("%s %s", buttons.CloudCheck, text)
+			}
+			menuItems[i] = gaba.MenuItem{Text: text, Metadata: host}
+		}
+
+		options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "servers_title", Other: "RomM Servers"}, nil), menuItems)
+		options.SmallTitle = true
+		options.SelectedIndex = active
+		options.ActionButton = buttons.VirtualButtonX
+		options.SecondaryActionButton = buttons.VirtualButtonY
+		options.FooterHelpItems = []gaba.FooterHelpItem{
+			FooterBack(),
+			{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_remove", Other: "Remove"}, nil)},
+			{ButtonName: "X", HelpText: i18n.Localize(&goi18n.Message{ID: "button_add", Other: "Add"}, nil)},
+			{ButtonName: "A", HelpText: i18n.Localize(&goi18n.Message{ID: "button_switch", Other: "Switch"}, nil)},
+		}
+		options.StatusBar = StatusBar()
+
+		sel, err := gaba.List(options)
+		if err != nil {
+			if errors.Is(err, gaba.ErrCancelled) {
+				return back(output), nil
+			}
+			logger.Error("Servers list error", "error", err)
+			return withCode(output, gaba.ExitCodeError), err
+		}
+
+		switch sel.Action {
+		case gaba.ListActionSelected:
+			host := sel.Items[sel.Selected[0]].Metadata.(romm.Host)
+			if host.Key() == config.CurrentHost().Key() {
+				return back(output), nil
+			}
+			output.SelectedHost = host
+			return success(output), nil
+
+		case gaba.ListActionTriggered:
+			host, err := AddHostFlow()
+			if err != nil {
+				if !errors.Is(err, gaba.ErrCancelled) {
+					logger.Error("Adding server failed", "error", err)
+				}
+				continue
+			}
+
+			// The new server only becomes active once the app has switched to it
+			previous := config.ActiveHost
+			config.AddHost(host)
+			config.ActiveHost = previous
+			if err := internal.SaveConfig(config); err != nil {
+				logger.Error("Failed to save config after adding server", "error", err)
+			}
+			output.SelectedHost = host
+			return success(output), nil
+
+		case gaba.ListActionSecondaryTriggered:
+			if len(sel.Selected) > 0 {
+				s.removeHost(config, sel.Items[sel.Selected[0]].Metadata.(romm.Host))
+			}
+			continue
+		}
+
+		return back(output), nil
+	}
+}
+
+func (s *ServersScreen) removeHost(config *internal.Config, host romm.Host) {
+	logger := gaba.GetLogger()
+
+	if host.Key() == config.CurrentHost().Key() {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "servers_remove_active", Other: "Switch to another server before removing this one,\nor use Logout in Grout Info."}, nil),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return
+	}
+
+	_, err := gaba.ConfirmationMessage(
+		i18n.Localize(&goi18n.Message{ID: "servers_remove_confirm", Other: "Remove {{.Name}}?\nIts cached games and artwork are deleted."}, map[string]interface{}{"Name": host.Label()}),
+		[]gaba.FooterHelpItem{
+			FooterCancel(),
+			{ButtonName: "Y", HelpText: i18n.Localize(&goi18n.Message{ID: "button_remove", Other: "Remove"}, nil)},
+		},
+		gaba.MessageOptions{
+			ConfirmButton: buttons.VirtualButtonY,
+		},
+	)
+	if err != nil {
+		return
+	}
+
+	config.RemoveHost(host.Key())
+	if err := internal.SaveConfig(config); err != nil {
+		logger.Error("Failed to save config after removing server", "error", err)
+	}
+
+	if err := cache.DeleteHostCacheFolder(host); err != nil {
+		logger.Warn("Failed to delete cache of removed server", "host", host.URL(), "error", err)
+	}
+
+	logger.Info("Removed RomM server", "host", host.URL())
+}
@@ -29,6 +29,7 @@ type SettingsInput struct {
 type SettingsOutput struct {
 	Config                     *internal.Config
 	GeneralSettingsClicked     bool
+	ServersClicked             bool
 	InfoClicked                bool
 	CollectionsSettingsClicked bool
 	DirectoryMappingsClicked   bool
@@ -49,9 +50,11 @@ type SettingType string
 
 const (
 	SettingGeneralSettings     SettingType = "general_settings"
+	SettingServers             SettingType = "servers"
 	SettingCollectionsSettings SettingType = "collections_settings"
 	SettingDirectoryMappings   SettingType = "directory_mappings"
 	SettingSaveSync            SettingType = "save_sync"
+	SettingSaveSyncContent     SettingType = "save_sync_content"
 	SettingSaveSyncSettings    SettingType = "save_sync_settings"
 	SettingAdvancedSettings    SettingType = "advanced_settings"
 	SettingInfo                SettingType = "info"
@@ -60,9 +63,11 @@ const (
 
 var settingsOrder = []SettingType{
 	SettingGeneralSettings,
+	SettingServers,
 	SettingCollectionsSettings,
 	SettingDirectoryMappings,
 	SettingSaveSync,
+	SettingSaveSyncContent,
 	SettingSaveSyncSettings,
 	SettingAdvancedSettings,
 	SettingInfo,
@@ -112,6 +117,11 @@ func (s *SettingsScreen) Draw(input SettingsInput) (ScreenResult[SettingsOutput]
 			return withCode(output, constants.ExitCodeGeneralSettings), nil
 		}
 
+		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_servers", Other: "RomM Servers"}, nil) {
+			output.ServersClicked = true
+			return withCode(output, constants.ExitCodeServers), nil
+		}
+
 		if selectedText == i18n.Localize(&goi18n.Message{ID: "settings_info", Other: "Grout Info"}, nil) {
 			output.InfoClicked = true
 			return withCode(output, constants.ExitCodeInfo), nil
@@ -163,6 +173,12 @@ func (s *SettingsScreen) buildMenuItem(settingType SettingType, config *internal
 			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
 		}
 
+	case SettingServers:
+		return gaba.ItemWithOptions{
+			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_servers", Other: "RomM Servers"}, nil)},
+			Options: []gaba.Option{{Type: gaba.OptionTypeClickable}},
+		}
+
 	case SettingCollectionsSettings:
 		return gaba.ItemWithOptions{
 			Item:    gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_collections", Other: "Collections Settings"}, nil)},
@@ -192,6 +208,18 @@ func (s *SettingsScreen) buildMenuItem(settingType SettingType, config *internal
 			SelectedOption: saveSyncModeToIndex(config.SaveSyncMode),
 		}
 
+	case SettingSaveSyncContent:
+		return gaba.ItemWithOptions{
+			Item: gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_save_sync_content", Other: "Sync Content"}, nil)},
+			Options: []gaba.Option{
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "save_sync_content_saves", Other: "Saves"}, nil), Value: "saves"},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "save_sync_content_states", Other: "States"}, nil), Value: "states"},
+				{DisplayName: i18n.Localize(&goi18n.Message{ID: "save_sync_content_both", Other: "Saves & States"}, nil), Value: "both"},
+			},
+			SelectedOption: saveSyncContentToIndex(config.SaveSyncContent),
+			VisibleWhen:    &visibility.saveSyncSettings,
+		}
+
 	case SettingSaveSyncSettings:
 		return gaba.ItemWithOptions{
 			Item:        gaba.MenuItem{Text: i18n.Localize(&goi18n.Message{ID: "settings_save_sync_settings", Other: "Save Sync Settings"}, nil)},
@@ -235,6 +263,10 @@ func (s *SettingsScreen) applySettings(config *internal.Config, items []gaba.Ite
 			if val, ok := item.Options[item.SelectedOption].Value.(string); ok {
 				config.SaveSyncMode = val
 			}
+		case i18n.Localize(&goi18n.Message{ID: "settings_save_sync_content", Other: "Sync Content"}, nil):
+			if val, ok := item.Options[item.SelectedOption].Value.(string); ok {
+				config.SaveSyncContent = val
+			}
 		}
 	}
 }
@@ -293,6 +325,19 @@ func saveSyncModeToIndex(mode string) int {
 	}
 }
 
+func saveSyncContentToIndex(content string) int {
+	switch content {
+	case "saves":
+		return 0
+	case "states":
+		return 1
+	case "both":
+		return 2
+	default:
+		return 0
+	}
+}
+
 func collectionViewToIndex(view string) int {
 	switch view {
 	case "platform":
@@ -42,6 +42,9 @@ func NewPlatformMappingScreen() *PlatformMappingScreen {
 func (s *PlatformMappingScreen) Draw(input PlatformMappingInput) (ScreenResult[PlatformMappingOutput], error) {
 	logger := gaba.GetLogger()
 	output := PlatformMappingOutput{Mappings: make(map[string]internal.DirectoryMapping)}
+	if !RequireOnline() {
+		return back(output), nil
+	}
 
 	rommPlatforms, err := s.fetchPlatforms(input)
 	if err != nil {
@@ -54,10 +54,65 @@ func (s *SyncReportScreen) draw(input syncReportInput) (ScreenResult[syncReportO
 	return success(output), nil
 }
 
+// syncReportLabels holds the section titles for one kind of sync, saves and states are reported separately.
+type syncReportLabels struct {
+	Summary    string
+	Downloaded string
+	Uploaded   string
+	Failed     string
+	Unmatched  string
+}
+
 func (s *SyncReportScreen) buildSections(results []sync.SyncResult, unmatched []sync.UnmatchedSave) []gaba.Section {
 	logger := gaba.GetLogger()
 	logger.Debug("Building sync report", "totalResults", len(results), "unmatched", len(unmatched))
 
+	var saveResults, stateResults []sync.SyncResult
+	for _, r := range results {
+		if r.Kind == sync.KindState {
+			stateResults = append(stateResults, r)
+		} else {
+			saveResults = append(saveResults, r)
+		}
+	}
+
+	var unmatchedSaves, unmatchedStates []sync.UnmatchedSave
+	for _, u := range unmatched {
+		if u.Kind == sync.KindState {
+			unmatchedStates = append(unmatchedStates, u)
+		} else {
+			unmatchedSaves = append(unmatchedSaves, u)
+		}
+	}
+
+	sections := make([]gaba.Section, 0)
+
+	if len(saveResults) > 0 || len(unmatchedSaves) > 0 || len(stateResults) == 0 {
+		sections = append(sections, s.buildKindSections(saveResults, unmatchedSaves, syncReportLabels{
+			Summary:    i18n.Localize(&goi18n.Message{ID: "save_sync_summary_section", Other: "Summary"}, nil),
+			Downloaded: i18n.Localize(&goi18n.Message{ID: "save_sync_downloaded", Other: "Downloaded"}, nil),
+			Uploaded:   i18n.Localize(&goi18n.Message{ID: "save_sync_uploaded", Other: "Uploaded"}, nil),
+			Failed:     i18n.Localize(&goi18n.Message{ID: "save_sync_failed", Other: "Failed"}, nil),
+			Unmatched:  i18n.Localize(&goi18n.Message{ID: "save_sync_unmatched_saves", Other: "Unmatched Saves"}, nil),
+		})...)
+	}
+
+	if len(stateResults) > 0 || len(unmatchedStates) > 0 {
+		sections = append(sections, s.buildKindSections(stateResults, unmatchedStates, syncReportLabels{
+			Summary:    i18n.Localize(&goi18n.Message{ID: "save_sync_states_section", Other: "States"}, nil),
+			Downloaded: i18n.Localize(&goi18n.Message{ID: "save_sync_states_downloaded", Other: "Downloaded States"}, nil),
+			Uploaded:   i18n.Localize(&goi18n.Message{ID: "save_sync_states_uploaded", Other: "Uploaded States"}, nil),
+			Failed:     i18n.Localize(&goi18n.Message{ID: "save_sync_states_failed", Other: "Failed States"}, nil),
+			Unmatched:  i18n.Localize(&goi18n.Message{ID: "save_sync_unmatched_states", Other: "Unmatched States"}, nil),
+		})...)
+	}
+
+	return sections
+}
+
+func (s *SyncReportScreen) buildKindSections(results []sync.SyncResult, unmatched []sync.UnmatchedSave, labels syncReportLabels) []gaba.Section {
+	logger := gaba.GetLogger()
+
 	sections := make([]gaba.Section, 0)
 
 	uploadedCount := 0
@@ -103,7 +158,7 @@ func (s *SyncReportScreen) buildSections(results []sync.SyncResult, unmatched []
 			Label: i18n.Localize(&goi18n.Message{ID: "save_sync_failed", Other: "Failed"}, nil), Value: fmt.Sprintf("%d", failedCount)})
 	}
 
-	sections = append(sections, gaba.NewInfoSection(i18n.Localize(&goi18n.Message{ID: "save_sync_summary_section", Other: "Summary"}, nil), summary))
+	sections = append(sections, gaba.NewInfoSection(labels.Summary, summary))
 
 	if downloadedCount > 0 {
 		downloadedFiles := ""
@@ -119,7 +174,7 @@ func (s *SyncReportScreen) buildSections(results []sync.SyncResult, unmatched []
 				downloadedFiles += displayName
 			}
 		}
-		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_downloaded", Other: "Downloaded"}, nil), downloadedFiles))
+		sections = append(sections, gaba.NewDescriptionSection(labels.Downloaded, downloadedFiles))
 	}
 
 	if uploadedCount > 0 {
@@ -141,7 +196,7 @@ func (s *SyncReportScreen) buildSections(results []sync.SyncResult, unmatched []
 				uploadedFiles += displayName
 			}
 		}
-		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_uploaded", Other: "Uploaded"}, nil), uploadedFiles))
+		sections = append(sections, gaba.NewDescriptionSection(labels.Uploaded, uploadedFiles))
 	}
 
 	if failedCount > 0 {
@@ -162,7 +217,7 @@ func (s *SyncReportScreen) buildSections(results []sync.SyncResult, unmatched []
 				failedFiles += fmt.Sprintf("%s (%s): %s", displayName, r.Action, errorMsg)
 			}
 		}
-		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_failed", Other: "Failed"}, nil), failedFiles))
+		sections = append(sections, gaba.NewDescriptionSection(labels.Failed, failedFiles))
 	}
 
 	// Display unmatched saves (ROM not found in RomM)
@@ -174,7 +229,7 @@ func (s *SyncReportScreen) buildSections(results []sync.SyncResult, unmatched []
 			}
 			unmatchedText += i18n.Localize(&goi18n.Message{ID: "save_sync_rom_not_found", Other: "{{.Name}} (ROM not found in RomM)"}, map[string]interface{}{"Name": filepath.Base(u.SavePath)})
 		}
-		sections = append(sections, gaba.NewDescriptionSection(i18n.Localize(&goi18n.Message{ID: "save_sync_unmatched_saves", Other: "Unmatched Saves"}, nil), unmatchedText))
+		sections = append(sections, gaba.NewDescriptionSection(labels.Unmatched, unmatchedText))
 	}
 
 	return sections
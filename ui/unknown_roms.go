@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"grout/cache"
+	"grout/internal"
+	"grout/internal/fileutil"
+	"grout/internal/stringutil"
+	"grout/romm"
+	"grout/sync"
+	"os"
+
+	gaba "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool"
+	icons "github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/constants"
+	"github.com/BrandonKowalski/gabagool/v2/pkg/gabagool/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"go.uber.org/atomic"
+)
+
+type UnknownRomsInput struct {
+	Config internal.Config
+	Host   romm.Host
+}
+
+type UnknownRomsOutput struct{}
+
+type UnknownRomsScreen struct{}
+
+func NewUnknownRomsScreen() *UnknownRomsScreen {
+	return &UnknownRomsScreen{}
+}
+
+func (s *UnknownRomsScreen) Execute(config internal.Config, host romm.Host) UnknownRomsOutput {
+	if !RequireOnline() {
+		return UnknownRomsOutput{}
+	}
+
+	s.draw(UnknownRomsInput{
+		Config: config,
+		Host:   host,
+	})
+	return UnknownRomsOutput{}
+}
+
+func (s *UnknownRomsScreen) draw(input UnknownRomsInput) {
+	logger := gaba.GetLogger()
+
+	// Pressing B while scanning stops the scan
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := romm.NewClientFromHost(input.Host, input.Config.ApiTimeout)
+	platforms, err := client.GetPlatformsContext(ctx)
+	if err != nil {
+		logger.Error("Failed to fetch platforms", "error", err)
+		gaba.ConfirmationMessage(
+			fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "unknown_roms_platforms_failed", Other: "Failed to fetch platforms: %v"}, nil), err),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return
+	}
+	romm.DisambiguatePlatformNames(platforms)
+
+	platformsBySlug := make(map[string]romm.Platform)
+	for _, p := range platforms {
+		platformsBySlug[p.FSSlug] = p
+	}
+
+	var unknown []sync.UnknownRom
+	unbind := BindCancelButton("cancel-unknown-roms-scan", cancel)
+	progress := &atomic.Float64{}
+	_, err = gaba.ProcessMessage(
+		i18n.Localize(&goi18n.Message{ID: "unknown_roms_scanning", Other: "Looking for ROMs missing from RomM...\nPress B to cancel"}, nil),
+		gaba.ProcessMessageOptions{
+			ShowThemeBackground: true,
+			ShowProgressBar:     true,
+			Progress:            progress,
+			ProcessInput:        true,
+		},
+		func() (interface{}, error) {
+			var err error
+			unknown, err = sync.FindUnknownRoms(ctx, sync.ScanRoms(), progress)
+			return nil, err
+		},
+	)
+	unbind()
+	if errors.Is(err, context.Canceled) {
+		logger.Info("Unknown ROM scan cancelled by user")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to scan for unknown ROMs", "error", err)
+		gaba.ConfirmationMessage(
+			fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "unknown_roms_scan_failed", Other: "Failed to scan local ROMs: %v"}, nil), err),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return
+	}
+
+	// Only files of platforms that exist in RomM have somewhere to go
+	var menuItems []gaba.MenuItem
+	for _, rom := range unknown {
+		platform, ok := platformsBySlug[rom.FSSlug]
+		if !ok {
+			continue
+		}
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     fmt.Sprintf("%s - %s (%s)", rom.FileName, platform.Name, stringutil.FormatBytes(rom.Size)),
+			Metadata: rom,
+		})
+	}
+
+	if len(menuItems) == 0 {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "unknown_roms_none", Other: "Every local ROM is already in your RomM library."}, nil),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return
+	}
+
+	options := gaba.DefaultListOptions(i18n.Localize(&goi18n.Message{ID: "unknown_roms_title", Other: "Unknown Local ROMs"}, nil), menuItems)
+	options.SmallTitle = true
+	options.StartInMultiSelectMode = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		FooterBack(),
+		{ButtonName: icons.Start, HelpText: i18n.Localize(&goi18n.Message{ID: "button_upload", Other: "Upload"}, nil), IsConfirmButton: true},
+	}
+	options.StatusBar = StatusBar()
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		logger.Error("Unknown ROM selection failed", "error", err)
+		return
+	}
+
+	if sel.Action != gaba.ListActionSelected || len(sel.Selected) == 0 {
+		return
+	}
+
+	// Large ROMs can take longer than any fixed timeout over a slow link, pressing B stops them instead
+	uploadClient := romm.NewClientFromHost(input.Host, 0)
+	uploadCtx, cancelUpload := context.WithCancel(context.Background())
+	defer cancelUpload()
+	uploadedPlatforms := make(map[int]romm.Platform)
+	successCount := 0
+	failedCount := 0
+	forbidden := false
+
+	for _, idx := range sel.Selected {
+		rom := sel.Items[idx].Metadata.(sync.UnknownRom)
+		platform := platformsBySlug[rom.FSSlug]
+
+		unbind := BindCancelButton("cancel-unknown-roms-upload", cancelUpload)
+		progress := &atomic.Float64{}
+		_, err := gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "unknown_roms_uploading", Other: "Uploading {{.Name}}...\nPress B to cancel"}, map[string]interface{}{"Name": rom.FileName}),
+			gaba.ProcessMessageOptions{
+				ShowThemeBackground: true,
+				ShowProgressBar:     true,
+				Progress:            progress,
+				ProcessInput:        true,
+			},
+			func() (interface{}, error) {
+				file, err := os.Open(rom.Path)
+				if err != nil {
+					return nil, err
+				}
+				defer file.Close()
+
+				r := fileutil.NewProgressReader(file, rom.Size, progress)
+				return nil, uploadClient.UploadRomReaderContext(uploadCtx, platform.ID, rom.FileName, r)
+			},
+		)
+		unbind()
+		if errors.Is(err, context.Canceled) {
+			logger.Info("ROM upload cancelled by user", "path", rom.Path)
+			break
+		}
+		if errors.Is(err, romm.ErrForbidden) {
+			// Every other upload would be refused the same way
+			logger.Error("RomM refused the upload, the account lacks the upload scope", "path", rom.Path, "error", err)
+			forbidden = true
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to upload ROM", "path", rom.Path, "platform", platform.FSSlug, "error", err)
+			failedCount++
+			continue
+		}
+
+		logger.Info("Uploaded ROM", "path", rom.Path, "platform", platform.FSSlug)
+		uploadedPlatforms[platform.ID] = platform
+		successCount++
+	}
+
+	if cm := cache.GetCacheManager(); cm != nil && len(uploadedPlatforms) > 0 {
+		gaba.ProcessMessage(
+			i18n.Localize(&goi18n.Message{ID: "unknown_roms_refreshing", Other: "Refreshing games cache..."}, nil),
+			gaba.ProcessMessageOptions{ShowThemeBackground: true},
+			func() (interface{}, error) {
+				for _, platform := range uploadedPlatforms {
+					if err := cm.RefreshPlatformGames(platform); err != nil {
+						logger.Warn("Failed to refresh platform games after upload", "platform", platform.Name, "error", err)
+					}
+				}
+				return nil, nil
+			},
+		)
+	}
+
+	if forbidden {
+		gaba.ConfirmationMessage(
+			i18n.Localize(&goi18n.Message{ID: "unknown_roms_upload_forbidden", Other: "Your RomM account can't upload ROMs!\nUploads need an editor or admin account, if yours is one log in again."}, nil),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return
+	}
+
+	if failedCount > 0 {
+		gaba.ConfirmationMessage(
+			fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "unknown_roms_upload_partial", Other: "Uploaded %d ROM(s), %d failed. Check the log for details."}, nil), successCount, failedCount),
+			ContinueFooter(),
+			gaba.MessageOptions{},
+		)
+		return
+	}
+
+	gaba.ConfirmationMessage(
+		fmt.Sprintf(i18n.Localize(&goi18n.Message{ID: "unknown_roms_upload_complete", Other: "Uploaded %d ROM(s). They appear in Grout once RomM has scanned its library."}, nil), successCount),
+		ContinueFooter(),
+		gaba.MessageOptions{},
+	)
+}